@@ -65,6 +65,9 @@ func newAICommand() *cobra.Command {
 
 			client := ai.NewClient()
 			session := ai.NewSession(files, prompt, sequenceSeparator, client)
+			session.SetExtensionModel(scope.ExtensionModel)
+			session.SetSidecars(scope.WorkingDir, scope.Sidecars)
+			session.SetReferences(scope.References)
 
 			reader := bufio.NewReader(cmd.InOrStdin())
 			out := cmd.OutOrStdout()
@@ -96,6 +99,7 @@ func newAICommand() *cobra.Command {
 						Notes:             session.Notes(),
 						Model:             session.Model(),
 						SequenceSeparator: session.SequenceSeparator(),
+						NameFilter:        scope.NameFilter.Metadata(),
 					}, out)
 					if err != nil {
 						return err
@@ -137,6 +141,7 @@ func newAICommand() *cobra.Command {
 						Notes:             session.Notes(),
 						Model:             session.Model(),
 						SequenceSeparator: session.SequenceSeparator(),
+						NameFilter:        scope.NameFilter.Metadata(),
 					}, out)
 					if err != nil {
 						return err
@@ -184,11 +189,8 @@ func collectScopeEntries(ctx context.Context, req *listing.ListingRequest) ([]st
 				return nil
 			}
 		} else {
-			if len(extensions) > 0 {
-				ext := strings.ToLower(filepath.Ext(entry.Name()))
-				if _, ok := extensions[ext]; !ok {
-					return nil
-				}
+			if !req.ExtensionModel.Allows(entry.Name(), extensions) {
+				return nil
 			}
 		}
 
@@ -196,6 +198,9 @@ func collectScopeEntries(ctx context.Context, req *listing.ListingRequest) ([]st
 		if relSlash == "." {
 			return nil
 		}
+		if !req.NameFilter.Allows(relSlash, entry.IsDir()) || (!entry.IsDir() && req.Sidecars.IsFollower(relSlash)) {
+			return nil
+		}
 		files = append(files, relSlash)
 		return nil
 	})
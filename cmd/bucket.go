@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/bucket"
+	"github.com/rogeecn/renamer/internal/listing"
+)
+
+func newBucketCommand() *cobra.Command {
+	var (
+		size      int
+		start     int
+		width     int
+		joiner    string
+		collision string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bucket",
+		Short: "Split large directories into numbered range subdirectories",
+		Long: `Sort the files of each directory by name and move every run of --size files into a range
+subdirectory next to them: 0001-1000/, 1001-2000/, and so on. Numbering begins at --start and
+bounds are zero-padded to --width digits, derived from the largest bound when zero. With
+--recursive every directory is bucketed on its own.
+
+When a bucket already holds a file of the same name, --on-collision fail reports a conflict,
+suffix appends _1, _2, … and skip leaves the file in place. Created directories are recorded in
+the ledger; "renamer undo" moves the files back and removes the buckets once empty.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := bucket.ParseCollisionPolicy(collision)
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := bucket.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetLayout(size, start, width, joiner, policy)
+
+			summary, planned, err := bucket.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				return nil
+			}
+
+			if _, err := bucket.Apply(cmd.Context(), req, planned, summary); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Moved %d files into %d new directories. Ledger updated.\n",
+				len(planned), len(summary.Directories))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&size, "size", 1000, "Number of files per bucket")
+	cmd.Flags().IntVar(&start, "start", 1, "Number of the first file")
+	cmd.Flags().IntVar(&width, "width", 0, "Zero-pad range bounds to this many digits (0 derives it)")
+	cmd.Flags().StringVar(&joiner, "joiner", "-", "Text placed between the lower and upper bound")
+	cmd.Flags().StringVar(&collision, "on-collision", string(bucket.CollisionFail), "Handling of taken names: fail, suffix, or skip")
+
+	cmd.Example = `  renamer bucket --dry-run
+  renamer bucket --size 500 --start 0 --yes
+  renamer bucket --size 100 --width 5 --joiner _ --recursive`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newBucketCommand())
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/casing"
+	"github.com/rogeecn/renamer/internal/listing"
+)
+
+func newCaseCommand() *cobra.Command {
+	var applyTo string
+
+	cmd := &cobra.Command{
+		Use:   "case <lower|upper|title|sentence|snake|kebab|camel|pascal>",
+		Short: "Convert file and directory names between case styles",
+		Long: `Convert names to a case style. Words are split on spaces, underscores, hyphens,
+camelCase boundaries, and letter/digit transitions using Unicode letter classes. The stem is
+converted by default; use --apply-to to convert the extension instead or as well. Directory
+names are converted whole when --include-dirs is set. Case-only renames (foo.txt -> Foo.txt)
+are supported on case-insensitive filesystems.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			style, err := casing.ParseStyle(args[0])
+			if err != nil {
+				return err
+			}
+			part, err := casing.ParsePart(applyTo)
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := casing.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetStyle(style, part)
+
+			summary, planned, err := casing.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; names already use the requested case.")
+				}
+				return nil
+			}
+
+			entry, err := casing.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d case updates. Ledger updated.\n", len(entry.Operations))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&applyTo, "apply-to", string(casing.PartStem), "Name portion to convert: stem, extension, or both")
+
+	cmd.Example = `  renamer case snake --dry-run
+  renamer case lower --apply-to extension --yes --recursive
+  renamer case title --include-dirs --path ./albums`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newCaseCommand())
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+	"github.com/rogeecn/renamer/internal/dupes"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/output"
+)
+
+func newDupesCommand() *cobra.Command {
+	var (
+		algorithm string
+		workers   int
+		minSize   int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dupes",
+		Short: "Report groups of files with identical content",
+		Long: `Group files in scope by identical content. Files are compared by size first, so only
+same-size files are read; those are hashed in parallel with --workers readers and every match is
+confirmed byte for byte before it is reported. The report is read-only and never touches the
+ledger. Progress is drawn on stderr when it is a terminal.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alg, err := contenthash.ParseAlgorithm(algorithm)
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := dupes.NewRequest(scope)
+			req.MinSize = minSize
+			progress := output.NewByteProgress(progressWriter(cmd), "Hashing")
+			req.SetHashing(alg, workers, progress.Update)
+
+			report, err := dupes.Find(cmd.Context(), req)
+			progress.Finish()
+			if err != nil {
+				return err
+			}
+
+			dupes.Render(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&algorithm, "algorithm", string(contenthash.DefaultAlgorithm), "Digest used to group files: sha256, xxhash, or blake3")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Concurrent file readers (0 = number of CPUs)")
+	cmd.Flags().Int64Var(&minSize, "min-size", 1, "Ignore files smaller than this many bytes")
+
+	cmd.Example = `  renamer dupes --recursive
+  renamer dupes -e ".jpg|.heic" --algorithm xxhash --recursive
+  renamer dupes --min-size 1048576 --workers 8 --recursive`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newDupesCommand())
+}
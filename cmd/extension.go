@@ -12,12 +12,34 @@ import (
 
 // NewExtensionCommand constructs the extension CLI command; exported for testing.
 func NewExtensionCommand() *cobra.Command {
+	var byContent, onlyMismatched bool
+
 	cmd := &cobra.Command{
-		Use:          "extension <source-ext...> <target-ext>",
-		Short:        "Normalize multiple file extensions to a single target extension",
-		Args:         cobra.MinimumNArgs(2),
+		Use:   "extension <source-ext...> <target-ext>",
+		Short: "Normalize multiple file extensions to a single target extension",
+		Long: `Rename every file whose extension matches one of the source extensions (case-insensitively)
+to the target extension.
+
+With --by-content no extensions are given: each file's magic bytes are read and the file is
+renamed to the canonical extension of the detected type. A .jpg that is really PNG becomes .png,
+extension-less downloads gain one, and aliases such as .jpeg become .jpg. Files already named
+correctly, of unknown type, or detected with low confidence keep their names. --only-mismatched
+limits the run to files whose extension names a different type.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if byContent {
+				if len(args) > 0 {
+					return errors.New("--by-content does not take extension arguments")
+				}
+				return nil
+			}
+			return cobra.MinimumNArgs(2)(cmd, args)
+		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if onlyMismatched && !byContent {
+				return errors.New("--only-mismatched requires --by-content")
+			}
+
 			scope, err := listing.ScopeFromCmd(cmd)
 			if err != nil {
 				return err
@@ -38,13 +60,17 @@ func NewExtensionCommand() *cobra.Command {
 			}
 			req.SetExecutionMode(dryRun, autoApply)
 
-			parsed, err := extension.ParseArgs(args)
-			if err != nil {
-				return fmt.Errorf("invalid extension arguments: %w", err)
-			}
+			if byContent {
+				req.SetContentMode(true, onlyMismatched)
+			} else {
+				parsed, err := extension.ParseArgs(args)
+				if err != nil {
+					return fmt.Errorf("invalid extension arguments: %w", err)
+				}
 
-			req.SetExtensions(parsed.SourcesCanonical, parsed.SourcesDisplay, parsed.Target)
-			req.SetWarnings(parsed.Duplicates, parsed.NoOps)
+				req.SetExtensions(parsed.SourcesCanonical, parsed.SourcesDisplay, parsed.Target)
+				req.SetWarnings(parsed.Duplicates, parsed.NoOps)
+			}
 
 			summary, planned, err := extension.Preview(cmd.Context(), req, cmd.OutOrStdout())
 			if err != nil {
@@ -81,8 +107,12 @@ func NewExtensionCommand() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&byContent, "by-content", false, "Detect each file's type from its magic bytes and propose the matching extension")
+	cmd.Flags().BoolVar(&onlyMismatched, "only-mismatched", false, "With --by-content, only list files whose extension names a different type")
+
 	cmd.Example = `  renamer extension .jpeg .JPG .jpg --dry-run
-  renamer extension .yaml .yml .yml --yes --recursive`
+  renamer extension .yaml .yml .yml --yes --recursive
+  renamer extension --by-content --only-mismatched --recursive`
 
 	return cmd
 }
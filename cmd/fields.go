@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/fields"
+	"github.com/rogeecn/renamer/internal/listing"
+)
+
+func newFieldsCommand() *cobra.Command {
+	var (
+		split string
+		order string
+		join  string
+		trim  bool
+		count int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fields",
+		Short: "Swap, reorder, drop, or duplicate name fields around delimiters",
+		Long: `Split each stem into fields at the literal --split delimiters (pipe-separated, so ", | - "
+splits at either) and rejoin them in the --order given, using --join between fields (defaults to the
+first delimiter). Positions are 1-based: fields left out of --order are dropped and repeated ones
+duplicated. A position may carry modifiers such as 2:upper or 1:trim:title (trim or any case style).
+--trim strips whitespace around every field. Names must split into --count fields (default: the
+highest position in --order); the others are shown as field mismatches and left alone. Extensions
+are kept; directory names are split whole when --include-dirs is set.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			delimiters, err := fields.ParseDelimiters(split)
+			if err != nil {
+				return err
+			}
+			var specs []fields.FieldSpec
+			if order != "" {
+				if specs, err = fields.ParseOrder(order); err != nil {
+					return err
+				}
+			}
+			var joiners []string
+			if cmd.Flags().Changed("join") {
+				if joiners, err = fields.ParseJoiners(join); err != nil {
+					return err
+				}
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := fields.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetLayout(delimiters, specs, joiners, trim, count)
+
+			summary, planned, err := fields.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; no name changes with this layout.")
+				}
+				return nil
+			}
+
+			entry, err := fields.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d field updates. Ledger updated.\n", len(entry.Operations))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&split, "split", "", "Pipe-separated literal delimiters to split names at (required)")
+	cmd.Flags().StringVar(&order, "order", "", "Comma-separated 1-based field positions with optional :trim/:<case style> modifiers (default: original order)")
+	cmd.Flags().StringVar(&join, "join", "", "Pipe-separated text placed between output fields; the last one repeats (default: first delimiter)")
+	cmd.Flags().BoolVar(&trim, "trim", true, "Strip whitespace around every field")
+	cmd.Flags().IntVar(&count, "count", 0, "Number of fields a name must have (default: highest position in --order)")
+	_ = cmd.MarkFlagRequired("split")
+
+	cmd.Example = `  renamer fields --split ", | - " --order 3,2,1 --join " - | "
+  renamer fields --split " - " --order 3,1,2 --join "_" --yes
+  renamer fields --split "_" --order 1:upper,2,2 --dry-run`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newFieldsCommand())
+}
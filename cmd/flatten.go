@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/flatten"
+	"github.com/rogeecn/renamer/internal/listing"
+)
+
+func newFlattenCommand() *cobra.Command {
+	var (
+		joiner    string
+		collision string
+		keepEmpty bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "flatten",
+		Short: "Move nested files to the top level, folding their directories into the name",
+		Long: `Move every file below --path into --path itself, joining its directory segments into the
+name with --joiner (a/b/c.jpg becomes a_b_c.jpg). The whole tree is always traversed; top-level
+files stay where they are. When a flattened name is already taken, --on-collision fail reports
+a conflict, suffix appends _1, _2, … to the later file, and skip leaves it in place.
+
+Directories left empty by the moves are removed unless --keep-empty is set. Removals are
+recorded in the ledger, so "renamer undo" recreates them and moves every file back.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := flatten.ParseCollisionPolicy(collision)
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+			if scope.Sidecars != nil && !scope.Recursive {
+				// Flatten walks the whole tree regardless of --recursive, so companions are grouped
+				// across it as well.
+				scope.Recursive = true
+				if err := scope.GroupSidecars(scope.SidecarRules); err != nil {
+					return err
+				}
+			}
+
+			req := flatten.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetLayout(joiner, policy, keepEmpty)
+
+			summary, planned, err := flatten.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; the tree is already flat.")
+				}
+				return nil
+			}
+
+			entry, err := flatten.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Moved %d files and removed %d directories. Ledger updated.\n",
+				len(planned), entry.Metadata["removedDirectories"])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&joiner, "joiner", "_", "Text placed between folded directory segments")
+	cmd.Flags().StringVar(&collision, "on-collision", string(flatten.CollisionFail), "Handling of taken names: fail, suffix, or skip")
+	cmd.Flags().BoolVar(&keepEmpty, "keep-empty", false, "Keep directories left empty by the moves")
+
+	cmd.Example = `  renamer flatten --dry-run
+  renamer flatten --joiner " - " --on-collision suffix --yes
+  renamer flatten -e ".jpg|.png" --keep-empty --yes`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newFlattenCommand())
+}
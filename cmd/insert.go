@@ -12,14 +12,40 @@ import (
 )
 
 func newInsertCommand() *cobra.Command {
+	var (
+		before      string
+		after       string
+		beforeRegex string
+		afterRegex  string
+		nth         int
+		at          []string
+		tokens      bool
+	)
+
 	cmd := &cobra.Command{
-		Use:   "insert <position> <text>",
-		Short: "Insert text into filenames at specified positions",
+		Use:   "insert <position> <text> | insert --before|--after|--before-regex|--after-regex ANCHOR <text>",
+		Short: "Insert text into filenames at positions or relative to anchors",
 		Long: `Insert a Unicode string into each candidate filename at a specific position.
 Supported positions: "^" (start), "$" (before extension), positive indexes (1-based),
-and suffix offsets like "3$" counting backward from the end.`,
-		Args: cobra.MinimumNArgs(2),
+and suffix offsets like "3$" counting backward from the end.
+
+Instead of a position, --before/--after place the text around a literal substring of the stem and
+--before-regex/--after-regex around an RE2 match; --nth picks the occurrence (negative counts from
+the end). Candidates without the anchor are skipped and reported. Repeat --at POSITION=TEXT to add
+further insertions; every position resolves against the original stem.
+
+Text is inserted literally. With --tokens it may use template tokens such as {parent}, {n:03}, or
+{mtime:2006-01-02}, and literal braces are written as {{ and }}.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			anchor, err := insertAnchorFromFlags(cmd, before, after, beforeRegex, afterRegex, nth)
+			if err != nil {
+				return err
+			}
+			if anchor == nil && len(args) < 2 {
+				return errors.New("insert requires <position> <text>, or an anchor flag followed by <text>")
+			}
+
 			scope, err := listing.ScopeFromCmd(cmd)
 			if err != nil {
 				return err
@@ -39,10 +65,21 @@ and suffix offsets like "3$" counting backward from the end.`,
 				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
 			}
 			req.SetExecutionMode(dryRun, autoApply)
+			req.SetTokens(tokens)
 
-			positionToken := args[0]
-			insertText := strings.Join(args[1:], " ")
-			req.SetPositionAndText(positionToken, insertText)
+			if anchor != nil {
+				req.SetPositionAndText(anchor.String(), strings.Join(args, " "))
+				req.SetAnchor(*anchor)
+			} else {
+				req.SetPositionAndText(args[0], strings.Join(args[1:], " "))
+			}
+			for _, value := range at {
+				insertion, err := insert.ParseAt(value, tokens)
+				if err != nil {
+					return err
+				}
+				req.AddInsertion(insertion)
+			}
 
 			summary, planned, err := insert.Preview(cmd.Context(), req, cmd.OutOrStdout())
 			if err != nil {
@@ -79,12 +116,57 @@ and suffix offsets like "3$" counting backward from the end.`,
 		},
 	}
 
+	cmd.Flags().StringVar(&before, "before", "", "Insert before this literal substring of the stem")
+	cmd.Flags().StringVar(&after, "after", "", "Insert after this literal substring of the stem")
+	cmd.Flags().StringVar(&beforeRegex, "before-regex", "", "Insert before the match of this RE2 expression")
+	cmd.Flags().StringVar(&afterRegex, "after-regex", "", "Insert after the match of this RE2 expression")
+	cmd.Flags().IntVar(&nth, "nth", 1, "Anchor occurrence to use (1-based; negative counts from the end)")
+	cmd.Flags().StringArrayVar(&at, "at", nil, "Additional insertion as POSITION=TEXT (repeatable)")
+	cmd.Flags().BoolVar(&tokens, "tokens", false, "Expand template tokens such as {parent} or {n:03} in the inserted text")
+
 	cmd.Example = `  renamer insert ^ "[2025] " --dry-run
-  renamer insert 1$ _FINAL --yes --path ./reports`
+  renamer insert 1$ _FINAL --yes --path ./reports
+  renamer insert --before _v "_final" --dry-run
+  renamer insert --after-regex '\d{4}' - --nth -1
+  renamer insert ^ "{parent}_" --at '$=_{n:03}' --tokens`
 
 	return cmd
 }
 
+// insertAnchorFromFlags returns the content anchor selected by flags, or nil when the position
+// argument should be used.
+func insertAnchorFromFlags(cmd *cobra.Command, before, after, beforeRegex, afterRegex string, nth int) (*insert.Anchor, error) {
+	candidates := []struct {
+		flag  string
+		kind  insert.AnchorKind
+		value string
+	}{
+		{"before", insert.AnchorBefore, before},
+		{"after", insert.AnchorAfter, after},
+		{"before-regex", insert.AnchorBeforeRegex, beforeRegex},
+		{"after-regex", insert.AnchorAfterRegex, afterRegex},
+	}
+
+	var selected *insert.Anchor
+	for _, candidate := range candidates {
+		if !cmd.Flags().Changed(candidate.flag) {
+			continue
+		}
+		if selected != nil {
+			return nil, errors.New("use only one of --before, --after, --before-regex, --after-regex")
+		}
+		anchor, err := insert.NewContentAnchor(candidate.kind, candidate.value, nth)
+		if err != nil {
+			return nil, err
+		}
+		selected = &anchor
+	}
+	if selected == nil && cmd.Flags().Changed("nth") {
+		return nil, errors.New("--nth requires an anchor flag")
+	}
+	return selected, nil
+}
+
 func init() {
 	rootCmd.AddCommand(newInsertCommand())
 }
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/normalize"
+)
+
+func newNormalizeCommand() *cobra.Command {
+	var (
+		form     string
+		ascii    bool
+		mapping  []string
+		mapFile  string
+		fallback string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "normalize",
+		Short: "Normalize Unicode names and optionally transliterate them to ASCII",
+		Long: `Rewrite names into a Unicode normalization form (NFC by default) so visually identical
+names copied from different systems compare equal. With --ascii, names are transliterated to
+ASCII (é -> e, ß -> ss); custom mappings from --map or --map-file take precedence over the
+built-in table, and characters without a transliteration are replaced by --fallback.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedForm, err := normalize.ParseForm(form)
+			if err != nil {
+				return err
+			}
+
+			mappings, err := normalize.ParseMappings(mapping)
+			if err != nil {
+				return err
+			}
+			if mapFile != "" {
+				fromFile, err := normalize.LoadMappingFile(mapFile)
+				if err != nil {
+					return err
+				}
+				// Command-line mappings override entries loaded from the file.
+				for from, to := range mappings {
+					fromFile[from] = to
+				}
+				mappings = fromFile
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := normalize.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.Form = parsedForm
+			req.SetTransliteration(ascii, mappings, fallback)
+
+			summary, planned, err := normalize.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; names already normalized.")
+				}
+				return nil
+			}
+
+			entry, err := normalize.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d normalization updates. Ledger updated.\n", len(entry.Operations))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&form, "form", string(normalize.FormNFC), "Unicode normalization form: nfc, nfd, nfkc, or nfkd")
+	cmd.Flags().BoolVar(&ascii, "ascii", false, "Transliterate names to ASCII")
+	cmd.Flags().StringArrayVar(&mapping, "map", nil, "Custom transliteration mapping from=to (repeatable, requires --ascii)")
+	cmd.Flags().StringVar(&mapFile, "map-file", "", "File with one from=to transliteration mapping per line (requires --ascii)")
+	cmd.Flags().StringVar(&fallback, "fallback", normalize.DefaultFallback, "Replacement for characters without an ASCII transliteration")
+
+	cmd.Example = `  renamer normalize --form nfc --recursive --dry-run
+  renamer normalize --ascii --yes
+  renamer normalize --ascii --map "ä=ae" --map "ö=oe" --map "ü=ue" --path ./exports`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newNormalizeCommand())
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/organize"
+	"github.com/rogeecn/renamer/internal/template"
+)
+
+func newOrganizeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "organize <rule>",
+		Short: "Move files into directories computed from a template rule",
+		Long: `Move each file into the directory rendered from <rule>, relative to --path, keeping its name.
+Rules use the template token library (see "renamer template --help"), so "/" separates
+directory levels: "{mtime:2006}/{mtime:01}", "{ext|lower}", or "{mime-major}". {ext} renders
+without its leading dot here, so extension folders are not hidden.
+Missing directories are created on apply and recorded in the ledger; "renamer undo" moves the
+files back and removes the directories it created once they are empty. Only files are moved.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rule, err := template.Parse(args[0])
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := organize.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetRule(rule)
+
+			summary, planned, err := organize.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; files are already organized.")
+				}
+				return nil
+			}
+
+			if _, err := organize.Apply(cmd.Context(), req, planned, summary); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Moved %d files into %d new directories. Ledger updated.\n",
+				len(planned), len(summary.Directories))
+			return nil
+		},
+	}
+
+	cmd.Example = `  renamer organize "{mtime:2006}/{mtime:01}" --dry-run
+  renamer organize "{ext|lower}" --yes
+  renamer organize "{mime-major}/{exif:2006}" --recursive`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newOrganizeCommand())
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/pad"
+)
+
+func newPadCommand() *cobra.Command {
+	var (
+		width      int
+		occurrence string
+		strip      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pad",
+		Short: "Zero-pad numbers embedded in names so they sort naturally",
+		Long: `Find runs of digits in each stem and left-pad them with zeros so ep1, ep2, ep10 become
+ep01, ep02, ep10. Without --width the width is detected from the longest run at each digit-run
+position among names of the same shape in a directory (s1e2 and s10e3 share s#e#; ep001x does not
+widen them); with --width every selected number is rewritten to exactly that many digits (longer
+numbers are kept whole). --occurrence limits padding to the first or last digit run, and --strip
+removes leading zeros instead. Directory names are padded whole when --include-dirs is set.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedOccurrence, err := pad.ParseOccurrence(occurrence)
+			if err != nil {
+				return err
+			}
+			if strip && cmd.Flags().Changed("width") {
+				return errors.New("--strip cannot be combined with --width")
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := pad.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetPadding(width, parsedOccurrence, strip)
+
+			summary, planned, err := pad.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; numbers already use the requested width.")
+				}
+				return nil
+			}
+
+			entry, err := pad.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d pad updates. Ledger updated.\n", len(entry.Operations))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&width, "width", 0, "Digit width to pad to (default: longest digit run per directory)")
+	cmd.Flags().StringVar(&occurrence, "occurrence", string(pad.OccurrenceAll), "Digit runs to pad: all, first, or last")
+	cmd.Flags().BoolVar(&strip, "strip", false, "Remove leading zeros instead of adding them")
+
+	cmd.Example = `  renamer pad --dry-run
+  renamer pad --width 3 --occurrence last --yes
+  renamer pad --strip --recursive`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newPadCommand())
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// progressWriter returns the command's stderr when it is an interactive terminal, so long-running
+// work such as hashing can draw a status line without polluting piped or captured output.
+func progressWriter(cmd *cobra.Command) io.Writer {
+	file, ok := cmd.ErrOrStderr().(*os.File)
+	if !ok {
+		return nil
+	}
+	info, err := file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return file
+}
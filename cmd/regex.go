@@ -6,18 +6,35 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/rogeecn/renamer/internal/filters"
 	"github.com/rogeecn/renamer/internal/listing"
 	"github.com/rogeecn/renamer/internal/regex"
 )
 
 func newRegexCommand() *cobra.Command {
+	var (
+		target     string
+		replace    string
+		ignoreCase bool
+		multiline  bool
+	)
+
 	cmd := &cobra.Command{
 		Use:   "regex <pattern> <template>",
 		Short: "Rename files using regex capture groups",
 		Long: `Preview and apply filename changes by extracting capture groups from a regular
 expression pattern. Placeholders like @1, @2 refer to captured groups; @0 expands to the full match,
-and @@ emits a literal @. Undefined placeholders and invalid replacement templates result in
-validation errors before any filesystem changes occur.`,
+and @@ emits a literal @. Braced placeholders reference named groups and take ':'-separated
+modifiers: @{date} for (?P<date>...), @{1:upper} (any case style: lower, upper, title, …),
+@{2:03} to zero-pad numeric captures, and @{3:-none} for a group that did not participate in the
+match. Undefined placeholders and invalid replacement templates result in validation errors
+before any filesystem changes occur.
+
+By default the pattern is matched against the stem and the rendered template replaces the whole
+stem. --target basename matches the name including its extension and path matches the path
+relative to --path (a rewritten path may move entries into other directories, which are created
+as needed). --replace all or --replace N substitutes every match, or the first N, in place and
+keeps the unmatched text. The preview brackets the text each pattern match consumed.`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			scope, err := listing.ScopeFromCmd(cmd)
@@ -37,13 +54,30 @@ validation errors before any filesystem changes occur.`,
 				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
 			}
 
+			matchTarget, err := filters.ParseNameMatchTarget(target)
+			if err != nil {
+				return err
+			}
+			limit, err := regex.ParseLimit(replace)
+			if err != nil {
+				return err
+			}
+
 			request := regex.NewRequest(scope.WorkingDir)
 			request.Pattern = args[0]
 			request.Template = args[1]
+			request.Target = matchTarget
+			request.Limit = limit
+			request.IgnoreCase = ignoreCase
+			request.Multiline = multiline
 			request.IncludeDirectories = scope.IncludeDirectories
 			request.Recursive = scope.Recursive
 			request.IncludeHidden = scope.IncludeHidden
 			request.Extensions = append([]string(nil), scope.Extensions...)
+			request.ExtensionModel = scope.ExtensionModel
+			request.NameFilter = scope.NameFilter
+			request.Sidecars = scope.Sidecars
+			request.References = scope.References
 			request.DryRun = dryRun
 			request.AutoConfirm = autoApply
 
@@ -85,14 +119,22 @@ validation errors before any filesystem changes occur.`,
 				return nil
 			}
 
-			fmt.Fprintf(out, "Applied %d regex renames. Ledger updated.\n", len(entry.Operations))
+			fmt.Fprintf(out, "Applied %d regex renames. Ledger updated.\n", len(planned))
 			return nil
 		},
 	}
 
+	cmd.Flags().StringVar(&target, "target", string(filters.MatchTargetStem), "Text the pattern is matched against: stem, basename, or path")
+	cmd.Flags().StringVar(&replace, "replace", "", "Substitute matches in place: all, first, or a count (default replaces the whole target)")
+	cmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Match case-insensitively, like a leading (?i)")
+	cmd.Flags().BoolVar(&multiline, "multiline", false, "Let ^ and $ match at line breaks, like a leading (?m)")
+
 	cmd.Example = `  renamer regex "^(\\w+)-(\\d+)" "@2_@1" --dry-run
   renamer regex "^(build)_(\\d+)_v(.*)$" "release-@2-@1-v@3" --yes --path ./artifacts
-  renamer regex "^(.*)$" "release-@1" --dry-run   # fails when placeholders are undefined`
+  renamer regex "^(.*)$" "release-@1" --dry-run   # fails when placeholders are undefined
+  renamer regex "\\s+" "_" --replace all --dry-run
+  renamer regex "^draft" "final" --ignore-case --replace first --target basename
+  renamer regex "^(\\d{4})/(.*)$" "archive/@1/@2" --target path --recursive`
 
 	return cmd
 }
@@ -12,14 +12,66 @@ import (
 
 // NewRemoveCommand constructs the remove CLI command; exported for testing.
 func NewRemoveCommand() *cobra.Command {
+	var (
+		ranges         []string
+		first          int
+		last           int
+		bracketed      string
+		classes        []string
+		trimSeparators bool
+	)
+
 	cmd := &cobra.Command{
-		Use:   "remove <pattern1> [pattern2 ...]",
-		Short: "Remove literal substrings sequentially from names",
-		Args:  cobra.MinimumNArgs(1),
+		Use:   "remove [pattern1 pattern2 ...]",
+		Short: "Remove literal substrings, rune ranges, bracketed segments, or character classes from names",
+		Long: `Remove literal substrings sequentially from names, optionally combined with removal modes
+that act on the stem and keep a file's extension:
+
+  --range START:END   runes between two insert-style positions (^, $, N, N$), e.g. 2:8 or 4$:$
+  --first N/--last N  the first or last N runes of the stem
+  --bracketed         balanced (), [], {} segments such as "[1080p]" or "(1)"; pass pairs to
+                      narrow the set, e.g. --bracketed="[]"
+  --class NAME        every rune of a class: digits, letters, spaces, punct, or nonascii
+  --trim-separators   trim spaces, "_", "-", and "." from the stem's ends and collapse repeats
+
+Positions resolve against the original stem; literal tokens then apply to the whole name, followed
+by bracketed segments, classes, and trimming. Candidates whose stem is too short for a range are
+skipped with a warning.`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			parsed, err := remove.ParseArgs(args)
-			if err != nil {
-				return err
+			modes := remove.Modes{First: first, Last: last, TrimSeparators: trimSeparators}
+			if first < 0 || last < 0 {
+				return errors.New("--first and --last must not be negative")
+			}
+			for _, value := range ranges {
+				r, err := remove.ParseRange(value)
+				if err != nil {
+					return err
+				}
+				modes.Ranges = append(modes.Ranges, r)
+			}
+			if cmd.Flags().Changed("bracketed") {
+				pairs, err := remove.ParseBrackets(bracketed)
+				if err != nil {
+					return err
+				}
+				modes.Brackets = pairs
+			}
+			for _, value := range classes {
+				class, err := remove.ParseClass(value)
+				if err != nil {
+					return err
+				}
+				modes.Classes = append(modes.Classes, class)
+			}
+
+			var parsed remove.ParseArgsResult
+			if len(args) > 0 || !modes.Active() {
+				var err error
+				parsed, err = remove.ParseArgs(args)
+				if err != nil {
+					return err
+				}
 			}
 
 			scope, err := listing.ScopeFromCmd(cmd)
@@ -27,7 +79,7 @@ func NewRemoveCommand() *cobra.Command {
 				return err
 			}
 
-			req, err := remove.FromListing(scope, parsed.Tokens)
+			req, err := remove.FromListing(scope, parsed.Tokens, modes)
 			if err != nil {
 				return err
 			}
@@ -56,6 +108,10 @@ func NewRemoveCommand() *cobra.Command {
 				fmt.Fprintf(out, "Warning: %s would become empty; skipping\n", empty)
 			}
 
+			for _, skipped := range summary.OutOfBounds {
+				fmt.Fprintf(out, "Warning: %s; skipping\n", skipped)
+			}
+
 			for _, dup := range summary.SortedDuplicates() {
 				fmt.Fprintf(out, "Warning: token %q provided multiple times\n", dup)
 			}
@@ -97,7 +153,19 @@ func NewRemoveCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Example = "  renamer remove \" copy\" \" draft\" --dry-run\n  renamer remove foo bar --yes --path ./docs"
+	cmd.Flags().StringArrayVar(&ranges, "range", nil, "Remove runes between two positions, START:END (repeatable)")
+	cmd.Flags().IntVar(&first, "first", 0, "Remove the first N runes of the stem")
+	cmd.Flags().IntVar(&last, "last", 0, "Remove the last N runes of the stem")
+	cmd.Flags().StringVar(&bracketed, "bracketed", "", "Remove balanced segments delimited by opening/closing pairs (bare flag: ()[]{})")
+	cmd.Flags().Lookup("bracketed").NoOptDefVal = remove.DefaultBrackets
+	cmd.Flags().StringArrayVar(&classes, "class", nil, "Remove a character class: digits, letters, spaces, punct, nonascii (repeatable)")
+	cmd.Flags().BoolVar(&trimSeparators, "trim-separators", false, "Trim separators from the stem ends and collapse repeats")
+
+	cmd.Example = `  renamer remove " copy" " draft" --dry-run
+  renamer remove foo bar --yes --path ./docs
+  renamer remove --range 2:8 --dry-run
+  renamer remove --bracketed --trim-separators --yes
+  renamer remove --class digits --last 3 -e .mp3`
 
 	return cmd
 }
@@ -13,16 +13,33 @@ import (
 
 // NewReplaceCommand constructs the replace CLI command; exported for testing.
 func NewReplaceCommand() *cobra.Command {
+	var (
+		ignoreCase bool
+		wholeWord  bool
+		occurrence string
+		stemOnly   bool
+	)
+
 	cmd := &cobra.Command{
 		Use:   "replace <pattern1> [pattern2 ...] <replacement>",
 		Short: "Replace multiple literals in file and directory names",
-		Args:  cobra.MinimumNArgs(2),
+		Long: `Replace each literal pattern with the replacement, in argument order, across entry names.
+Matching is case-sensitive, covers every occurrence, and includes the extension unless refined:
+--ignore-case matches regardless of case, --whole-word only matches text not flanked by letters
+or digits, --occurrence first|last replaces a single occurrence per pattern, and --stem-only
+leaves file extensions untouched. Per-pattern counts report the occurrences actually replaced.`,
+		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			parseResult, err := replace.ParseArgs(args)
 			if err != nil {
 				return err
 			}
 
+			parsedOccurrence, err := replace.ParseOccurrence(occurrence)
+			if err != nil {
+				return err
+			}
+
 			scope, err := listing.ScopeFromCmd(cmd)
 			if err != nil {
 				return err
@@ -36,6 +53,16 @@ func NewReplaceCommand() *cobra.Command {
 				Recursive:          scope.Recursive,
 				IncludeHidden:      scope.IncludeHidden,
 				Extensions:         scope.Extensions,
+				ExtensionModel:     scope.ExtensionModel,
+				NameFilter:         scope.NameFilter,
+				Sidecars:           scope.Sidecars,
+				References:         scope.References,
+				Options: replace.Options{
+					IgnoreCase: ignoreCase,
+					WholeWord:  wholeWord,
+					Occurrence: parsedOccurrence,
+					StemOnly:   stemOnly,
+				},
 			}
 
 			dryRun, err := getBool(cmd, "dry-run")
@@ -74,8 +101,10 @@ func NewReplaceCommand() *cobra.Command {
 			}
 
 			fmt.Fprintf(out, "Planned replacements: %d entries updated across %d candidates\n", summary.ChangedCount, summary.TotalCandidates)
-			for pattern, count := range summary.PatternMatches {
-				fmt.Fprintf(out, "  %s -> %d occurrences\n", pattern, count)
+			for _, pattern := range parseResult.Patterns {
+				if count, ok := summary.PatternMatches[pattern]; ok {
+					fmt.Fprintf(out, "  %s -> %d occurrences\n", pattern, count)
+				}
 			}
 
 			if dryRun || !autoApply {
@@ -98,8 +127,15 @@ func NewReplaceCommand() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Match patterns regardless of letter case")
+	cmd.Flags().BoolVar(&wholeWord, "whole-word", false, "Only match patterns not flanked by letters or digits")
+	cmd.Flags().StringVar(&occurrence, "occurrence", string(replace.OccurrenceAll), "Occurrences replaced per pattern: all, first, or last")
+	cmd.Flags().BoolVar(&stemOnly, "stem-only", false, "Leave file extensions untouched")
+
 	cmd.Example = `  renamer replace draft Draft final --dry-run
-  renamer replace "Project X" "Project-X" ProjectX --yes --path ./docs`
+  renamer replace "Project X" "Project-X" ProjectX --yes --path ./docs
+  renamer replace copy "" --ignore-case --whole-word --dry-run
+  renamer replace . _ --stem-only --occurrence last`
 
 	return cmd
 }
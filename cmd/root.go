@@ -53,6 +53,17 @@ func NewRootCommand() *cobra.Command {
 	cmd.AddCommand(newInsertCommand())
 	cmd.AddCommand(newRegexCommand())
 	cmd.AddCommand(newSequenceCommand())
+	cmd.AddCommand(newCaseCommand())
+	cmd.AddCommand(newNormalizeCommand())
+	cmd.AddCommand(newSanitizeCommand())
+	cmd.AddCommand(newTruncateCommand())
+	cmd.AddCommand(newTemplateCommand())
+	cmd.AddCommand(newDupesCommand())
+	cmd.AddCommand(newOrganizeCommand())
+	cmd.AddCommand(newFlattenCommand())
+	cmd.AddCommand(newBucketCommand())
+	cmd.AddCommand(newPadCommand())
+	cmd.AddCommand(newFieldsCommand())
 	cmd.AddCommand(newUndoCommand())
 
 	return cmd
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/sanitize"
+)
+
+func newSanitizeCommand() *cobra.Command {
+	var (
+		profile     string
+		replacement string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sanitize",
+		Short: "Rewrite names that are illegal or unsafe on a target platform",
+		Long: `Rewrite names so they are valid under a target profile. Control characters are removed,
+illegal characters are replaced with --replacement (an empty value strips them), and repeated
+separators are collapsed. The windows and portable profiles also trim trailing dots and spaces
+and suffix reserved device names (CON, NUL, COM1, LPT1, ...). When several names sanitize to
+the same target, the first in path order keeps it and later ones receive _1, _2, ... suffixes.
+
+Profiles:
+  posix     control characters only
+  windows   \ : * ? " < > |, trailing dots/spaces, reserved names
+  portable  windows rules plus # and %, and leading spaces (default)
+  s3        anything outside the S3 safe key set A-Z a-z 0-9 ! - _ . * ' ( )`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedProfile, err := sanitize.ParseProfile(profile)
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := sanitize.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetProfile(parsedProfile, replacement)
+
+			summary, planned, err := sanitize.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintf(cmd.OutOrStdout(), "Nothing to apply; names are already %s-safe.\n", req.Profile)
+				}
+				return nil
+			}
+
+			entry, err := sanitize.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d sanitize updates. Ledger updated.\n", len(entry.Operations))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", string(sanitize.ProfilePortable), "Target profile: posix, windows, portable, or s3")
+	cmd.Flags().StringVar(&replacement, "replacement", "_", "Text substituted for illegal characters (empty strips them)")
+
+	cmd.Example = `  renamer sanitize --dry-run
+  renamer sanitize --profile windows --recursive --yes
+  renamer sanitize --profile s3 --replacement - --path ./uploads`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newSanitizeCommand())
+}
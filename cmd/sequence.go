@@ -16,8 +16,15 @@ func newSequenceCommand() *cobra.Command {
 		Use:   "sequence",
 		Short: "Append or prepend sequential numbers to filenames",
 		Long: `Preview and apply numbered renames across the active scope. Sequence numbers
-respect deterministic traversal order, support configurable start offsets, and record every
-batch in the .renamer ledger for undo.`,
+follow a deterministic order chosen with --sort (ties broken by path so runs are repeatable),
+support configurable start offsets and steps, can restart in every directory with
+--per-directory, and record every batch in the .renamer ledger for undo.
+
+With --renumber, the existing number in each name (the last digit run by default, or the one
+located by --field-regex/--field-position) is rewritten using --start, --width, and --step.
+Files are ordered by their current number unless --sort is given, so renumbering after deleting
+files closes the gaps. Overlapping targets are renamed in a safe order, through temporary names
+when needed. --width defaults to the widest existing number in this mode.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			scope, err := listing.ScopeFromCmd(cmd)
@@ -61,6 +68,59 @@ batch in the .renamer ledger for undo.`,
 			if err != nil {
 				return err
 			}
+			sortValue, err := cmd.Flags().GetString("sort")
+			if err != nil {
+				return err
+			}
+			sortKey, sortSeed, err := sequence.ParseSort(sortValue)
+			if err != nil {
+				return err
+			}
+			reverse, err := cmd.Flags().GetBool("reverse")
+			if err != nil {
+				return err
+			}
+			perDirectory, err := cmd.Flags().GetBool("per-directory")
+			if err != nil {
+				return err
+			}
+			step, err := cmd.Flags().GetInt("step")
+			if err != nil {
+				return err
+			}
+			renumber, err := cmd.Flags().GetBool("renumber")
+			if err != nil {
+				return err
+			}
+			fieldRegex, err := cmd.Flags().GetString("field-regex")
+			if err != nil {
+				return err
+			}
+			fieldPositionValue, err := cmd.Flags().GetString("field-position")
+			if err != nil {
+				return err
+			}
+			fieldPosition, err := sequence.ParseFieldPosition(fieldPositionValue)
+			if err != nil {
+				return err
+			}
+			if !renumber && (cmd.Flags().Changed("field-regex") || cmd.Flags().Changed("field-position")) {
+				return errors.New("--field-regex and --field-position require --renumber")
+			}
+			if fieldRegex != "" && cmd.Flags().Changed("field-position") {
+				return errors.New("use either --field-regex or --field-position, not both")
+			}
+			styleValue, err := cmd.Flags().GetString("style")
+			if err != nil {
+				return err
+			}
+			style, err := sequence.ParseStyle(styleValue)
+			if err != nil {
+				return err
+			}
+			if renumber && !cmd.Flags().Changed("sort") {
+				sortKey = sequence.SortNumber
+			}
 
 			opts := sequence.DefaultOptions()
 			opts.WorkingDir = scope.WorkingDir
@@ -68,6 +128,10 @@ batch in the .renamer ledger for undo.`,
 			opts.IncludeHidden = scope.IncludeHidden
 			opts.Recursive = scope.Recursive
 			opts.Extensions = append([]string(nil), scope.Extensions...)
+			opts.ExtensionModel = scope.ExtensionModel
+			opts.NameFilter = scope.NameFilter
+			opts.Sidecars = scope.Sidecars
+			opts.References = scope.References
 			opts.DryRun = dryRun
 			opts.AutoApply = autoApply
 			if start != 0 {
@@ -85,6 +149,15 @@ batch in the .renamer ledger for undo.`,
 			}
 			opts.NumberPrefix = numberPrefix
 			opts.NumberSuffix = numberSuffix
+			opts.Sort = sortKey
+			opts.SortSeed = sortSeed
+			opts.Reverse = reverse
+			opts.PerDirectory = perDirectory
+			opts.Step = step
+			opts.Style = style
+			opts.Renumber = renumber
+			opts.FieldPattern = fieldRegex
+			opts.FieldPosition = fieldPosition
 
 			out := cmd.OutOrStdout()
 
@@ -103,6 +176,8 @@ batch in the .renamer ledger for undo.`,
 				}
 				fmt.Fprintf(out, "%s: %s -> %s\n", status, candidate.OriginalPath, candidate.ProposedPath)
 			}
+			plan.Sidecars.WritePreview(out)
+			plan.References.WritePreview(out)
 
 			for _, conflict := range plan.SkippedConflicts {
 				fmt.Fprintf(out, "Warning: %s skipped due to %s (target %s)\n", conflict.OriginalPath, conflict.Reason, conflict.ConflictingPath)
@@ -116,11 +191,15 @@ batch in the .renamer ledger for undo.`,
 				return nil
 			}
 
-			fmt.Fprintf(out, "Preview: %d candidates, %d renames, %d skipped (width %d).\n",
+			widthNote := ""
+			if plan.Summary.AppliedWidth > 0 {
+				widthNote = fmt.Sprintf(" (width %d)", plan.Summary.AppliedWidth)
+			}
+			fmt.Fprintf(out, "Preview: %d candidates, %d renames, %d skipped%s.\n",
 				plan.Summary.TotalCandidates,
 				plan.Summary.RenamedCount,
 				plan.Summary.SkippedCount,
-				plan.Summary.AppliedWidth,
+				widthNote,
 			)
 
 			if dryRun || !autoApply {
@@ -135,7 +214,12 @@ batch in the .renamer ledger for undo.`,
 				return err
 			}
 
-			fmt.Fprintf(out, "Applied %d sequence updates. Ledger updated.\n", len(entry.Operations))
+			applied := len(entry.Operations)
+			if renumber {
+				// Temporary hops are recorded as extra operations; report files renamed instead.
+				applied = plan.Summary.RenamedCount
+			}
+			fmt.Fprintf(out, "Applied %d sequence updates. Ledger updated.\n", applied)
 			if plan.Summary.SkippedCount > 0 {
 				fmt.Fprintf(out, "%d candidates were skipped due to conflicts.\n", plan.Summary.SkippedCount)
 			}
@@ -144,11 +228,27 @@ batch in the .renamer ledger for undo.`,
 	}
 
 	cmd.Flags().Int("start", 1, "Starting sequence value (>=1)")
-	cmd.Flags().Int("width", 0, "Minimum digit width for zero padding (defaults to 3 digits, auto-expands as needed)")
+	cmd.Flags().Int("width", 0, "Minimum digit width for zero padding (default: digits of the largest number; ignored by alpha and roman)")
 	cmd.Flags().String("placement", string(sequence.PlacementPrefix), "Placement for the sequence number: prefix or suffix")
 	cmd.Flags().String("separator", "_", "Separator between the filename and sequence label and the original name")
 	cmd.Flags().String("number-prefix", "", "Static text placed immediately before the sequence digits")
 	cmd.Flags().String("number-suffix", "", "Static text placed immediately after the sequence digits")
+	cmd.Flags().String("sort", string(sequence.SortName), "Numbering order: name (traversal order), natural (file2 before file10), mtime, size, exif (capture time, falling back to mtime), number (the existing number; --renumber only), or random:SEED")
+	cmd.Flags().Bool("reverse", false, "Number candidates in reverse sort order")
+	cmd.Flags().Bool("per-directory", false, "Restart numbering at --start in every directory")
+	cmd.Flags().Int("step", 1, "Increment between consecutive sequence numbers (>=1)")
+	cmd.Flags().String("style", string(sequence.StyleDecimal), "Numbering style: decimal, alpha (a, b, …, aa), ALPHA, roman, hex, or of (03of12)")
+	cmd.Flags().Bool("renumber", false, "Rewrite an existing number in each name instead of adding one (orders by that number and closes gaps)")
+	cmd.Flags().String("field-regex", "", "Regular expression locating the number to renumber (its capture group, if any, must be digits)")
+	cmd.Flags().String("field-position", string(sequence.FieldLast), "Digit run to renumber when no --field-regex is given: first or last")
+
+	cmd.Example = `  renamer sequence --sort natural --dry-run
+  renamer sequence -r --per-directory --sort mtime --yes
+  renamer sequence --start 10 --step 10 --reverse
+  renamer sequence --sort random:42 --placement suffix
+  renamer sequence --style of --placement suffix --separator " "
+  renamer sequence --renumber --dry-run
+  renamer sequence --renumber --field-regex '_(\d+)$' --start 1 --width 4 --yes`
 
 	return cmd
 }
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/output"
+	"github.com/rogeecn/renamer/internal/template"
+)
+
+func newTemplateCommand() *cobra.Command {
+	var (
+		missing     string
+		placeholder string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "template <template>",
+		Short: "Build names from file attributes using {token} placeholders",
+		Long:  templateLongHelp(),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl, err := template.Parse(args[0])
+			if err != nil {
+				return err
+			}
+
+			policy, err := template.ParseMissingPolicy(missing)
+			if err != nil {
+				return err
+			}
+
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := template.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetTemplate(tmpl)
+			req.SetMissingPolicy(policy, placeholder)
+			progress := output.NewByteProgress(progressWriter(cmd), "Hashing")
+			req.SetProgress(progress.Update)
+
+			summary, planned, err := template.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			progress.Finish()
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; names already match the template.")
+				}
+				return nil
+			}
+
+			if _, err := template.Apply(cmd.Context(), req, planned, summary); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d template renames. Ledger updated.\n", len(planned))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&missing, "missing", string(template.MissingFail), "Handling of missing audio tags: fail, skip, or placeholder")
+	cmd.Flags().StringVar(&placeholder, "placeholder", template.DefaultPlaceholder, "Value substituted for missing tags with --missing placeholder")
+
+	cmd.Example = `  renamer template "{mtime:2006-01-02}_{parent}_{stem}_{n:03}{ext}" --dry-run
+  renamer template "{stem|lower|slice:0:10}{ext}" --yes
+  renamer template "{n:4:100:10}-{stem|snake}{ext}" --recursive
+  renamer template "{artist} - {album}/{track:02} {title}{ext}" -e ".mp3|.flac" --missing skip
+  renamer template "{hash:blake3:16}{ext|lower}" --recursive`
+
+	return cmd
+}
+
+func templateLongHelp() string {
+	var builder strings.Builder
+	builder.WriteString(`Rename entries by rendering a template of literal text and {token} placeholders. Tokens
+may take an argument after ':' and be piped through filters with '|', e.g.
+{stem|lower|slice:0:10}. Write literal braces as {{ and }}. Counters advance in traversal
+order. A "/" in the rendered name moves the entry into a subdirectory of its current
+directory, creating it as needed; undo removes created directories once empty. Empty names
+are reported as conflicts.
+
+Audio tag tokens read ID3v2 (MP3), Vorbis comments (FLAC, Ogg Vorbis/Opus), and MP4/M4A
+atoms. When a tag is missing, --missing fail reports a conflict, skip leaves the file
+unchanged, and placeholder substitutes --placeholder. The preview lists the tag behind each
+segment.
+
+{hash} digests file content in parallel before rendering. Identical files that would share a
+hashed name are reported as duplicate_content rather than duplicate_target.
+
+Tokens:
+`)
+	for _, doc := range template.Library() {
+		fmt.Fprintf(&builder, "  %-24s %s\n", doc.Usage, doc.Description)
+	}
+	builder.WriteString("\nFilters:\n")
+	for _, doc := range template.Filters() {
+		fmt.Fprintf(&builder, "  %-24s %s\n", doc.Usage, doc.Description)
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func init() {
+	rootCmd.AddCommand(newTemplateCommand())
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/truncate"
+)
+
+func newTruncateCommand() *cobra.Command {
+	var (
+		maxBytes int
+		maxPath  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "truncate",
+		Short: "Shorten names that exceed byte or path length limits",
+		Long: `Shorten names longer than --max-bytes bytes, or whose absolute path would exceed --max-path
+bytes. The stem is cut at a UTF-8 rune boundary and the extension is kept. When a shortened
+name collides with another entry, a short hash of the original name is inserted before the
+extension of every colliding name (e.g. "very-long-na~1a2b3c4d.txt"). Every other rename command applies the same
+default limits (255 bytes per name, 4096 bytes per path) during preview and reports
+violations as conflicts.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scope, err := listing.ScopeFromCmd(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := truncate.NewRequest(scope)
+
+			dryRun, err := getBool(cmd, "dry-run")
+			if err != nil {
+				return err
+			}
+			autoApply, err := getBool(cmd, "yes")
+			if err != nil {
+				return err
+			}
+			if dryRun && autoApply {
+				return errors.New("--dry-run cannot be combined with --yes; remove one of them")
+			}
+			req.SetExecutionMode(dryRun, autoApply)
+			req.SetLimits(maxBytes, maxPath)
+
+			summary, planned, err := truncate.Preview(cmd.Context(), req, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if summary.HasConflicts() {
+				return errors.New("conflicts detected; resolve them before applying")
+			}
+
+			if dryRun || !autoApply {
+				if !autoApply {
+					fmt.Fprintln(cmd.OutOrStdout(), "Preview complete. Re-run with --yes to apply.")
+				}
+				return nil
+			}
+
+			if len(planned) == 0 {
+				if summary.TotalCandidates == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No candidates found.")
+				} else {
+					fmt.Fprintln(cmd.OutOrStdout(), "Nothing to apply; names are already within limits.")
+				}
+				return nil
+			}
+
+			entry, err := truncate.Apply(cmd.Context(), req, planned, summary)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %d truncations. Ledger updated.\n", len(entry.Operations))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&maxBytes, "max-bytes", pathlimit.DefaultMaxNameBytes, "Maximum bytes per name, extension included")
+	cmd.Flags().IntVar(&maxPath, "max-path", pathlimit.DefaultMaxPathBytes, "Maximum bytes per absolute path (0 disables)")
+
+	cmd.Example = `  renamer truncate --dry-run --recursive
+  renamer truncate --max-bytes 100 --yes
+  renamer truncate --max-path 260 --include-dirs --recursive`
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newTruncateCommand())
+}
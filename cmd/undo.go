@@ -28,10 +28,22 @@ func newUndoCommand() *cobra.Command {
 
 			out := cmd.OutOrStdout()
 			fmt.Fprintf(out, "Undo applied: %d operations reversed\n", len(entry.Operations))
+			edited := 0
+			for _, op := range entry.Operations {
+				if op.Kind == history.OperationEdit {
+					edited++
+				}
+			}
+			if edited > 0 {
+				fmt.Fprintf(out, "Restored contents of %d edited document(s)\n", edited)
+			}
 
 			if entry.Metadata != nil {
 				switch entry.Command {
 				case "extension":
+					if byContent, ok := entry.Metadata["byContent"].(bool); ok && byContent {
+						fmt.Fprintln(out, "Restored extensions replaced by content detection")
+					}
 					if target, ok := entry.Metadata["targetExtension"].(string); ok && target != "" {
 						fmt.Fprintf(out, "Restored extensions to %s\n", target)
 					}
@@ -55,6 +67,52 @@ func newUndoCommand() *cobra.Command {
 							fmt.Fprintf(out, "Inserted text %q removed\n", insertText)
 						}
 					}
+				case "case":
+					if style, ok := entry.Metadata["style"].(string); ok && style != "" {
+						fmt.Fprintf(out, "Restored original casing (reverted %s conversion)\n", style)
+					}
+				case "normalize":
+					if form, ok := entry.Metadata["form"].(string); ok && form != "" {
+						fmt.Fprintf(out, "Restored names from %s normalization\n", form)
+					}
+				case "sanitize":
+					if profile, ok := entry.Metadata["profile"].(string); ok && profile != "" {
+						fmt.Fprintf(out, "Restored names sanitized for the %s profile\n", profile)
+					}
+				case "truncate":
+					if maxBytes, ok := entry.Metadata["maxBytes"].(float64); ok && maxBytes > 0 {
+						fmt.Fprintf(out, "Restored names truncated to %d bytes\n", int(maxBytes))
+					}
+				case "template":
+					if tmpl, ok := entry.Metadata["template"].(string); ok && tmpl != "" {
+						fmt.Fprintf(out, "Reverted template %q\n", tmpl)
+					}
+				case "organize":
+					if rule, ok := entry.Metadata["rule"].(string); ok && rule != "" {
+						fmt.Fprintf(out, "Reverted organize rule %q\n", rule)
+					}
+				case "flatten":
+					if removed, ok := entry.Metadata["removedDirectories"].(float64); ok && removed > 0 {
+						fmt.Fprintf(out, "Recreated %d directories removed by flatten\n", int(removed))
+					}
+				case "bucket":
+					if layout, ok := entry.Metadata["bucket"].(map[string]any); ok {
+						if size, ok := layout["size"].(float64); ok && size > 0 {
+							fmt.Fprintf(out, "Moved files back out of %d-file buckets\n", int(size))
+						}
+					}
+				case "pad":
+					if strip, ok := entry.Metadata["strip"].(bool); ok && strip {
+						fmt.Fprintln(out, "Restored leading zeros removed by pad")
+					} else {
+						fmt.Fprintln(out, "Removed zero padding added by pad")
+					}
+				case "fields":
+					if order, ok := entry.Metadata["order"].(string); ok && order != "" {
+						fmt.Fprintf(out, "Restored field order rearranged as %s\n", order)
+					} else {
+						fmt.Fprintln(out, "Restored names rejoined by fields")
+					}
 				case "regex":
 					if pattern, ok := entry.Metadata["pattern"].(string); ok && pattern != "" {
 						fmt.Fprintf(out, "Reverted regex pattern %q\n", pattern)
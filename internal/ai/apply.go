@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -20,6 +19,7 @@ type ApplyMetadata struct {
 	Notes             []string
 	Model             string
 	SequenceSeparator string
+	NameFilter        map[string]any
 }
 
 // toMap converts metadata into a ledger-friendly map.
@@ -28,6 +28,9 @@ func (m ApplyMetadata) toMap(warnings []string) map[string]any {
 	if m.SequenceSeparator != "" {
 		data["sequenceSeparator"] = m.SequenceSeparator
 	}
+	if len(m.NameFilter) > 0 {
+		data["nameFilter"] = m.NameFilter
+	}
 	return data
 }
 
@@ -47,21 +50,9 @@ func Apply(ctx context.Context, workingDir string, suggestions []flow.Suggestion
 
 	operations := make([]history.Operation, 0, len(suggestions))
 
-	revert := func() error {
-		for i := len(operations) - 1; i >= 0; i-- {
-			op := operations[i]
-			source := filepath.Join(workingDir, filepath.FromSlash(op.To))
-			destination := filepath.Join(workingDir, filepath.FromSlash(op.From))
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
-		}
-		return nil
-	}
-
 	for _, suggestion := range suggestions {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(workingDir, operations)
 			return history.Entry{}, err
 		}
 
@@ -76,28 +67,41 @@ func Apply(ctx context.Context, workingDir string, suggestions []flow.Suggestion
 		}
 
 		if err := ensureParentDir(toAbs); err != nil {
-			_ = revert()
+			_ = history.Rollback(workingDir, operations)
 			return history.Entry{}, err
 		}
 
 		if err := os.Rename(fromAbs, toAbs); err != nil {
-			_ = revert()
+			_ = history.Rollback(workingDir, operations)
 			return history.Entry{}, err
 		}
 
 		operations = append(operations, history.Operation{From: fromRel, To: toRel})
 		if err := reporter.Step(fromRel, toRel); err != nil {
-			_ = revert()
+			_ = history.Rollback(workingDir, operations)
 			return history.Entry{}, err
 		}
 	}
 
+	followers, err := validation.Sidecars.Apply(workingDir)
+	if err != nil {
+		_ = history.Rollback(workingDir, operations)
+		return history.Entry{}, err
+	}
+	operations = append(operations, followers...)
+	edits, err := validation.References.Apply(workingDir)
+	if err != nil {
+		_ = history.Rollback(workingDir, operations)
+		return history.Entry{}, err
+	}
+	operations = append(operations, edits...)
+
 	if len(operations) == 0 {
 		return entry, reporter.Complete()
 	}
 
 	if err := reporter.Complete(); err != nil {
-		_ = revert()
+		_ = history.Rollback(workingDir, operations)
 		return history.Entry{}, err
 	}
 
@@ -105,7 +109,7 @@ func Apply(ctx context.Context, workingDir string, suggestions []flow.Suggestion
 	entry.Metadata = meta.toMap(validation.Warnings)
 
 	if err := history.Append(workingDir, entry); err != nil {
-		_ = revert()
+		_ = history.Rollback(workingDir, operations)
 		return history.Entry{}, err
 	}
 
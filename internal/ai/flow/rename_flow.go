@@ -13,6 +13,8 @@ import (
 
 	"github.com/firebase/genkit/go/core"
 	"github.com/firebase/genkit/go/genkit"
+
+	"github.com/rogeecn/renamer/internal/fileext"
 )
 
 //go:embed prompt.tmpl
@@ -121,8 +123,7 @@ func deterministicSuggestion(rel string, promptPrefix string, dirCounters map[st
  }
 
 	base := path.Base(rel)
-	ext := path.Ext(base)
-	name := strings.TrimSuffix(base, ext)
+	name, ext := fileext.Model{}.Split(base)
 
 	sanitizedName := slugify(name)
 
@@ -29,6 +29,8 @@ func PrintPreview(w io.Writer, suggestions []flow.Suggestion, validation Validat
 	if err := table.End(w); err != nil {
 		return err
 	}
+	validation.Sidecars.WritePreview(w)
+	validation.References.WritePreview(w)
 
 	for _, warn := range validation.Warnings {
 		if _, err := fmt.Fprintf(w, "Warning: %s\n", warn); err != nil {
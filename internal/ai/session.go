@@ -5,6 +5,9 @@ import (
 	"strings"
 
 	flowpkg "github.com/rogeecn/renamer/internal/ai/flow"
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 const defaultModelID = "googleai/gemini-1.5-flash"
@@ -17,6 +20,10 @@ type Session struct {
 	notes             []string
 	model             string
 	sequenceSeparator string
+	extensions        fileext.Model
+	workingDir        string
+	sidecars          *sidecar.Groups
+	references        *references.Rewriter
 
 	lastOutput     *flowpkg.Output
 	lastValidation ValidationResult
@@ -48,6 +55,22 @@ func NewSession(files []string, initialPrompt string, sequenceSeparator string,
 	}
 }
 
+// SetExtensionModel sets the compound extensions suggestions must preserve.
+func (s *Session) SetExtensionModel(model fileext.Model) {
+	s.extensions = model
+}
+
+// SetSidecars sets the companion clusters whose followers move with the suggested renames.
+func (s *Session) SetSidecars(workingDir string, groups *sidecar.Groups) {
+	s.workingDir = workingDir
+	s.sidecars = groups
+}
+
+// SetReferences sets the documents whose references follow the suggested renames.
+func (s *Session) SetReferences(rewriter *references.Rewriter) {
+	s.references = rewriter
+}
+
 // Generate executes the flow and returns structured suggestions with validation.
 func (s *Session) Generate(ctx context.Context) (*flowpkg.Output, ValidationResult, error) {
 	prompt := s.CurrentPrompt()
@@ -62,7 +85,11 @@ func (s *Session) Generate(ctx context.Context) (*flowpkg.Output, ValidationResu
 		return nil, ValidationResult{}, err
 	}
 
-	validation := ValidateSuggestions(s.files, output.Suggestions)
+	validation := ValidateSuggestionsWithExtensions(s.files, output.Suggestions, s.extensions)
+	validation.expandSidecars(s.workingDir, s.sidecars, output.Suggestions)
+	if err := validation.planReferences(s.references, output.Suggestions); err != nil {
+		return nil, ValidationResult{}, err
+	}
 	s.lastOutput = output
 	s.lastValidation = validation
 	return output, validation, nil
@@ -6,6 +6,10 @@ import (
 	"strings"
 
 	"github.com/rogeecn/renamer/internal/ai/flow"
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 var invalidCharacters = []rune{'/', '\\', ':', '*', '?', '"', '<', '>', '|'}
@@ -21,10 +25,21 @@ type Conflict struct {
 type ValidationResult struct {
 	Conflicts []Conflict
 	Warnings  []string
+	// Sidecars holds the companion renames derived from the suggestions.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the suggestions.
+	References references.Plan
 }
 
-// ValidateSuggestions enforces rename safety rules before applying suggestions.
+// ValidateSuggestions enforces rename safety rules before applying suggestions, recognising the
+// default compound extensions.
 func ValidateSuggestions(expected []string, suggestions []flow.Suggestion) ValidationResult {
+	return ValidateSuggestionsWithExtensions(expected, suggestions, fileext.Model{})
+}
+
+// ValidateSuggestionsWithExtensions is ValidateSuggestions with the scope's extension model, so a
+// suggestion that turns "backup.tar.gz" into "backup.gz" counts as an extension change.
+func ValidateSuggestionsWithExtensions(expected []string, suggestions []flow.Suggestion, extensions fileext.Model) ValidationResult {
 	result := ValidationResult{}
 
 	expectedSet := make(map[string]struct{}, len(expected))
@@ -86,7 +101,18 @@ func ValidateSuggestions(expected []string, suggestions []flow.Suggestion) Valid
 			continue
 		}
 
-		if !extensionsMatch(suggestion.Original, cleaned) {
+		// The working directory is unknown here, so only the per-name limit is enforced.
+		nameLimit := pathlimit.Limits{MaxNameBytes: pathlimit.DefaultMaxNameBytes}
+		if violation, ok := nameLimit.CheckPath(normalizedSuggested); !ok {
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Original:  suggestion.Original,
+				Suggested: suggestion.Suggested,
+				Reason:    fmt.Sprintf("suggested name is too long (%d bytes > %d)", violation.Length, violation.Limit),
+			})
+			continue
+		}
+
+		if !extensionsMatch(suggestion.Original, cleaned, extensions) {
 			result.Conflicts = append(result.Conflicts, Conflict{
 				Original:  suggestion.Original,
 				Suggested: suggestion.Suggested,
@@ -122,6 +148,33 @@ func ValidateSuggestions(expected []string, suggestions []flow.Suggestion) Valid
 	return result
 }
 
+// expandSidecars derives the follower renames for the suggestions; a follower that cannot move
+// with its head is a conflict like any other.
+func (r *ValidationResult) expandSidecars(workingDir string, groups *sidecar.Groups, suggestions []flow.Suggestion) {
+	renames := make([]sidecar.Rename, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		renames = append(renames, sidecar.Rename{From: flowToKey(suggestion.Original), To: flowToKey(suggestion.Suggested)})
+	}
+	r.Sidecars = groups.Expand(workingDir, renames)
+	for _, conflict := range r.Sidecars.Conflicts {
+		r.Conflicts = append(r.Conflicts, Conflict{Original: conflict.From, Suggested: conflict.To, Reason: conflict.Message()})
+	}
+}
+
+// planReferences derives the document rewrites for the suggestions and their companions.
+func (r *ValidationResult) planReferences(rewriter *references.Rewriter, suggestions []flow.Suggestion) error {
+	renames := make([]sidecar.Rename, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		renames = append(renames, sidecar.Rename{From: flowToKey(suggestion.Original), To: flowToKey(suggestion.Suggested)})
+	}
+	refs, err := rewriter.Plan(renames, r.Sidecars.Renames)
+	if err != nil {
+		return err
+	}
+	r.References = refs
+	return nil
+}
+
 func flowToKey(value string) string {
 	return strings.ReplaceAll(strings.TrimSpace(value), "\\", "/")
 }
@@ -135,9 +188,9 @@ func containsInvalidCharacter(value string) bool {
 	return false
 }
 
-func extensionsMatch(original, proposed string) bool {
-	origExt := strings.ToLower(path.Ext(original))
-	propExt := strings.ToLower(path.Ext(proposed))
+func extensionsMatch(original, proposed string, extensions fileext.Model) bool {
+	origExt := strings.ToLower(extensions.Ext(path.Base(original)))
+	propExt := strings.ToLower(extensions.Ext(path.Base(proposed)))
 	return origExt == propExt
 }
 
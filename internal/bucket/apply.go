@@ -0,0 +1,88 @@
+package bucket
+
+import (
+	"context"
+	"path"
+
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+// Apply creates the bucket directories, moves the planned files, and records both in the ledger.
+// Directory creations are recorded before the moves into them, so undo moves files back first
+// and then removes the directories once they are empty.
+func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summary *Summary) (history.Entry, error) {
+	entry := history.Entry{Command: "bucket"}
+
+	if len(planned) == 0 {
+		return entry, nil
+	}
+
+	done := make([]history.Operation, 0, len(planned))
+
+	moved := 0
+	directories := 0
+	for _, op := range planned {
+		if err := ctx.Err(); err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		created, err := history.EnsureDir(req.WorkingDir, path.Dir(op.ProposedRelative))
+		done = append(done, created...)
+		directories += len(created)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		if err := history.RenamePath(op.OriginalAbsolute, op.ProposedAbsolute); err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		done = append(done, history.Operation{
+			From: op.OriginalRelative,
+			To:   op.ProposedRelative,
+		})
+		moved++
+	}
+
+	if summary != nil {
+		followers, err := summary.Sidecars.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, followers...)
+		edits, err := summary.References.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, edits...)
+	}
+
+	entry.Operations = done
+	meta := make(map[string]any)
+	if summary != nil {
+		for k, v := range summary.LedgerMetadata {
+			meta[k] = v
+		}
+		meta["totalCandidates"] = summary.TotalCandidates
+		meta["suffixed"] = summary.Suffixed
+		meta["skipped"] = summary.Skipped
+		if len(summary.Warnings) > 0 {
+			meta["warnings"] = append([]string(nil), summary.Warnings...)
+		}
+	}
+	meta["moved"] = moved
+	meta["createdDirectories"] = directories
+	entry.Metadata = meta
+
+	if err := history.Append(req.WorkingDir, entry); err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,88 @@
+package bucket
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxSuffixAttempts bounds the search for a free deduplication suffix.
+const maxSuffixAttempts = 10000
+
+// errSuffixesExhausted signals that every suffix up to maxSuffixAttempts is already taken.
+var errSuffixesExhausted = errors.New("suffixes_exhausted")
+
+// targetAllocator hands out collision-free targets inside bucket directories. Keys are
+// case-folded so the plan stays valid on case-insensitive filesystems. Candidates are visited in
+// name order, so under the suffix policy the first claimant keeps its name.
+type targetAllocator struct {
+	workingDir string
+	planned    map[string]string
+}
+
+func newTargetAllocator(workingDir string) *targetAllocator {
+	return &targetAllocator{workingDir: workingDir, planned: make(map[string]string)}
+}
+
+// allocate returns a free target for the candidate. Without suffixing, a taken name yields the
+// conflicting path and no target. The boolean reports whether a suffix was needed.
+func (a *targetAllocator) allocate(candidateRel, targetRel string, suffix bool) (string, bool, string, error) {
+	dir, name := path.Split(targetRel)
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if stem == "" {
+		stem, ext = ext, ""
+	}
+
+	originalAbs := filepath.Join(a.workingDir, filepath.FromSlash(candidateRel))
+	attempts := 1
+	if suffix {
+		attempts = maxSuffixAttempts
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
+		proposed := targetRel
+		if attempt > 0 {
+			proposed = fmt.Sprintf("%s%s_%d%s", dir, stem, attempt, ext)
+		}
+
+		holder, err := a.holder(candidateRel, proposed, originalAbs)
+		if err != nil {
+			return "", false, "", err
+		}
+		if holder == "" {
+			a.planned[strings.ToLower(proposed)] = candidateRel
+			return proposed, attempt > 0, "", nil
+		}
+		if !suffix {
+			return "", false, holder, nil
+		}
+	}
+
+	return "", false, "", errSuffixesExhausted
+}
+
+// holder returns the path already occupying proposedRel, or "" when it is free.
+func (a *targetAllocator) holder(candidateRel, proposedRel, originalAbs string) (string, error) {
+	if existing, ok := a.planned[strings.ToLower(proposedRel)]; ok && existing != candidateRel {
+		return existing, nil
+	}
+
+	info, err := os.Stat(filepath.Join(a.workingDir, filepath.FromSlash(proposedRel)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	origInfo, err := os.Stat(originalAbs)
+	if err != nil {
+		return "", err
+	}
+	if os.SameFile(info, origInfo) {
+		return "", nil
+	}
+	return proposedRel, nil
+}
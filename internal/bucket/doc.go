@@ -0,0 +1,4 @@
+// Package bucket splits the files of a directory into numbered subdirectories of a fixed size
+// (0001-1000/, 1001-2000/, …). Created directories are recorded in the ledger so undo can move
+// the files back and remove them.
+package bucket
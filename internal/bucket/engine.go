@@ -0,0 +1,181 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation moves one file into its bucket directory, which Apply creates on demand.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+}
+
+// BuildPlan groups candidate files by directory, sorts each group by name, and assigns every
+// run of Size files to a numbered range directory next to them.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("bucket request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	allocator := newTargetAllocator(req.WorkingDir)
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	groups := make(map[string][]string)
+	err := traversal.NewWalker().Walk(
+		req.WorkingDir,
+		req.Recursive,
+		false,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." || entry.IsDir() {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			if !req.ExtensionModel.Allows(entry.Name(), filterSet) {
+				return nil
+			}
+			if !req.NameFilter.Allows(relative, false) || req.Sidecars.IsFollower(relative) {
+				return nil
+			}
+			dir := path.Dir(relative)
+			groups[dir] = append(groups[dir], relative)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dirs := make([]string, 0, len(groups))
+	for dir := range groups {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	planned := make(map[string]bool)
+	for _, dir := range dirs {
+		files := groups[dir]
+		sort.Strings(files)
+
+		width := req.Width
+		if width == 0 {
+			last := req.Start + ((len(files)-1)/req.Size+1)*req.Size - 1
+			width = len(strconv.Itoa(last))
+		}
+
+		for i, relative := range files {
+			lower := req.Start + (i/req.Size)*req.Size
+			bucketName := fmt.Sprintf("%0*d%s%0*d", width, lower, req.Joiner, width, lower+req.Size-1)
+			bucketDir := path.Join(dir, bucketName)
+			target := path.Join(bucketDir, path.Base(relative))
+
+			preview := PreviewEntry{OriginalPath: relative, ProposedPath: target, Status: StatusChanged}
+
+			if reason := blockedBucket(req.WorkingDir, bucketDir); reason != "" {
+				summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: reason})
+				preview.Status = StatusSkipped
+				summary.RecordEntry(preview)
+				continue
+			}
+			if violation, ok := pathlimit.Check(req.WorkingDir, target); !ok {
+				summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: violation.Reason()})
+				preview.Status = StatusSkipped
+				summary.RecordEntry(preview)
+				continue
+			}
+
+			allocated, suffixed, holder, err := allocator.allocate(relative, target, req.Collision == CollisionSuffix)
+			if errors.Is(err, errSuffixesExhausted) {
+				summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: err.Error()})
+				preview.Status = StatusSkipped
+				summary.RecordEntry(preview)
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			if holder != "" {
+				preview.Status = StatusSkipped
+				reason := "collision with " + holder
+				if req.Collision == CollisionSkip {
+					preview.Reason = reason
+				} else {
+					summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: reason})
+				}
+				summary.RecordEntry(preview)
+				continue
+			}
+
+			preview.ProposedPath = allocated
+			preview.Suffixed = suffixed
+			if _, err := os.Stat(filepath.Join(req.WorkingDir, filepath.FromSlash(bucketDir))); err != nil && !planned[bucketDir] {
+				planned[bucketDir] = true
+				summary.Directories = append(summary.Directories, bucketDir)
+			}
+			operations = append(operations, PlannedOperation{
+				OriginalRelative: relative,
+				OriginalAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(relative)),
+				ProposedRelative: allocated,
+				ProposedAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(allocated)),
+			})
+			summary.RecordEntry(preview)
+		}
+	}
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+// blockedBucket reports a bucket name already taken by something other than a directory.
+func blockedBucket(workingDir, bucketDir string) string {
+	info, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(bucketDir)))
+	if err == nil && !info.IsDir() {
+		return fmt.Sprintf("parent_not_directory (%s)", bucketDir)
+	}
+	return ""
+}
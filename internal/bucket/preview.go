@@ -0,0 +1,93 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Preview plans the moves and prints each file with its bucket, then the directories to create,
+// to out.
+func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("bucket request cannot be nil")
+	}
+
+	summary, operations, err := BuildPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary.LedgerMetadata["bucket"] = map[string]any{
+		"size":      req.Size,
+		"start":     req.Start,
+		"width":     req.Width,
+		"joiner":    req.Joiner,
+		"collision": string(req.Collision),
+	}
+	scope := map[string]any{
+		"recursive":     req.Recursive,
+		"includeHidden": req.IncludeHidden,
+	}
+	if len(req.ExtensionFilter) > 0 {
+		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
+	summary.LedgerMetadata["scope"] = scope
+
+	if out != nil {
+		conflictReasons := make(map[string]string, len(summary.Conflicts))
+		for _, conflict := range summary.Conflicts {
+			conflictReasons[conflict.OriginalPath] = conflict.Reason
+		}
+
+		for _, entry := range summary.Entries {
+			switch entry.Status {
+			case StatusChanged:
+				if entry.Suffixed {
+					fmt.Fprintf(out, "%s -> %s (suffixed)\n", entry.OriginalPath, entry.ProposedPath)
+					continue
+				}
+				fmt.Fprintf(out, "%s -> %s\n", entry.OriginalPath, entry.ProposedPath)
+			case StatusSkipped:
+				if entry.Reason != "" {
+					fmt.Fprintf(out, "%s (skipped: %s)\n", entry.OriginalPath, entry.Reason)
+					continue
+				}
+				reason := conflictReasons[entry.OriginalPath]
+				if reason == "" {
+					reason = "skipped"
+				}
+				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			}
+		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
+
+		if len(summary.Directories) > 0 {
+			fmt.Fprintln(out, "\nDirectories to create:")
+			for _, dir := range summary.Directories {
+				fmt.Fprintf(out, "  %s/\n", dir)
+			}
+		}
+
+		if summary.TotalCandidates > 0 {
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will move, %d skipped, %d directories to create\n",
+				summary.TotalCandidates, summary.TotalChanged, summary.Skipped, len(summary.Directories))
+		} else {
+			fmt.Fprintln(out, "No candidates found.")
+		}
+
+		if len(summary.Warnings) > 0 {
+			fmt.Fprintln(out)
+			for _, warning := range summary.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	return summary, operations, nil
+}
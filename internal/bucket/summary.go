@@ -0,0 +1,106 @@
+package bucket
+
+import (
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Status reports whether a file moves into its bucket; files never stay where they are.
+type Status string
+
+const (
+	StatusChanged Status = "changed"
+	StatusSkipped Status = "skipped"
+)
+
+// PreviewEntry pairs a file with its path inside the bucket directory.
+type PreviewEntry struct {
+	OriginalPath string
+	ProposedPath string
+	Status       Status
+	// Suffixed marks targets that needed a _N suffix to avoid a collision.
+	Suffixed bool
+	// Reason explains a non-blocking skip under the skip collision policy.
+	Reason string
+}
+
+// Conflict records a bucket path that is already taken under the fail collision policy.
+type Conflict struct {
+	OriginalPath string
+	ProposedPath string
+	Reason       string
+}
+
+// Summary collects the bucket preview: the moves, the bucket directories they need, and how many
+// targets were suffixed or skipped on collision.
+type Summary struct {
+	TotalCandidates int
+	TotalChanged    int
+	Skipped         int
+	Suffixed        int
+
+	Entries   []PreviewEntry
+	Conflicts []Conflict
+	Warnings  []string
+	// Directories lists bucket directories apply will create, in creation order.
+	Directories []string
+
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
+
+	LedgerMetadata map[string]any
+}
+
+// NewSummary returns a Summary ready for BuildPlan to fill.
+func NewSummary() *Summary {
+	return &Summary{
+		Entries:        make([]PreviewEntry, 0),
+		Conflicts:      make([]Conflict, 0),
+		Warnings:       make([]string, 0),
+		Directories:    make([]string, 0),
+		LedgerMetadata: make(map[string]any),
+	}
+}
+
+// RecordEntry adds entry to the preview, counting suffixed moves and policy skips.
+func (s *Summary) RecordEntry(entry PreviewEntry) {
+	s.Entries = append(s.Entries, entry)
+	s.TotalCandidates++
+
+	switch entry.Status {
+	case StatusChanged:
+		s.TotalChanged++
+		if entry.Suffixed {
+			s.Suffixed++
+		}
+	case StatusSkipped:
+		if entry.Reason != "" {
+			s.Skipped++
+		}
+	}
+}
+
+// AddConflict records a collision that blocks apply.
+func (s *Summary) AddConflict(conflict Conflict) {
+	s.Conflicts = append(s.Conflicts, conflict)
+}
+
+// AddWarning records msg once, however many files raise it.
+func (s *Summary) AddWarning(msg string) {
+	if msg == "" {
+		return
+	}
+	for _, existing := range s.Warnings {
+		if existing == msg {
+			return
+		}
+	}
+	s.Warnings = append(s.Warnings, msg)
+}
+
+// HasConflicts reports whether any move collides under the fail policy.
+func (s *Summary) HasConflicts() bool {
+	return len(s.Conflicts) > 0
+}
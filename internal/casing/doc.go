@@ -0,0 +1,3 @@
+// Package casing implements the `renamer case` engine, converting file and directory names
+// between letter-case styles with Unicode-aware word splitting and case-only rename safety.
+package casing
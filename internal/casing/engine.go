@@ -0,0 +1,192 @@
+package casing
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation captures a filesystem rename to be applied.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	CaseOnly         bool
+	IsDir            bool
+	Depth            int
+}
+
+// BuildPlan enumerates candidates, converts their names, and prepares filesystem operations.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("case request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	detector := newConflictDetector()
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && (!req.ExtensionModel.Allows(name, filterSet) || req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) {
+				return nil
+			}
+
+			proposedName := convertName(name, isDir, req.Style, req.Part, req.ExtensionModel)
+			proposedRelative := joinRelative(relative, proposedName)
+			originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(relative))
+			proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative))
+
+			preview := PreviewEntry{
+				OriginalPath: relative,
+				ProposedPath: proposedRelative,
+				Status:       StatusChanged,
+				CaseOnly:     proposedRelative != relative && strings.EqualFold(proposedRelative, relative),
+			}
+
+			switch {
+			case proposedRelative == relative:
+				preview.Status = StatusNoChange
+				detector.reserve(relative)
+			case proposedName == "":
+				summary.AddConflict(Conflict{
+					OriginalPath: relative,
+					ProposedPath: proposedRelative,
+					Reason:       "empty_name",
+				})
+				preview.Status = StatusSkipped
+			default:
+				reason, err := detector.evaluate(relative, proposedRelative, originalAbsolute, proposedAbsolute)
+				if err != nil {
+					return err
+				}
+				if reason == "" {
+					if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+						reason = violation.Reason()
+					}
+				}
+				if reason != "" {
+					summary.AddConflict(Conflict{
+						OriginalPath: relative,
+						ProposedPath: proposedRelative,
+						Reason:       reason,
+					})
+					preview.Status = StatusSkipped
+					break
+				}
+				operations = append(operations, PlannedOperation{
+					OriginalRelative: relative,
+					OriginalAbsolute: originalAbsolute,
+					ProposedRelative: proposedRelative,
+					ProposedAbsolute: proposedAbsolute,
+					CaseOnly:         preview.CaseOnly,
+					IsDir:            isDir,
+					Depth:            depth,
+				})
+			}
+
+			summary.RecordEntry(preview)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+// convertName applies the style to the requested portion of name. Directories have no
+// extension, so the whole name is treated as the stem.
+func convertName(name string, isDir bool, style Style, part Part, extensions fileext.Model) string {
+	stem := name
+	ext := ""
+	if !isDir {
+		stem, ext = extensions.Split(name)
+	}
+
+	if part != PartExtension {
+		stem = Convert(stem, style)
+	}
+	if ext != "" && (part == PartExtension || part == PartBoth) {
+		// Each part of a compound extension is converted on its own: .TAR.GZ, not .TAR_GZ.
+		parts := strings.Split(strings.TrimPrefix(ext, "."), ".")
+		for i, segment := range parts {
+			parts[i] = Convert(segment, style)
+		}
+		ext = "." + strings.Join(parts, ".")
+	}
+	return stem + ext
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
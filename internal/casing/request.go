@@ -0,0 +1,133 @@
+package casing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Part selects which portion of a file name is converted.
+type Part string
+
+const (
+	// PartStem converts the name without its extension (default).
+	PartStem Part = "stem"
+	// PartExtension converts only the extension.
+	PartExtension Part = "extension"
+	// PartBoth converts the stem and the extension independently.
+	PartBoth Part = "both"
+)
+
+// ParsePart validates the --apply-to token.
+func ParsePart(value string) (Part, error) {
+	switch Part(strings.ToLower(strings.TrimSpace(value))) {
+	case "", PartStem:
+		return PartStem, nil
+	case PartExtension, "ext":
+		return PartExtension, nil
+	case PartBoth, "all":
+		return PartBoth, nil
+	default:
+		return "", fmt.Errorf("unsupported --apply-to value %q (use stem, extension, or both)", value)
+	}
+}
+
+// Request encapsulates the inputs required to run a case conversion.
+type Request struct {
+	WorkingDir      string
+	Style           Style
+	Part            Part
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest constructs a Request from shared listing scope.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{Part: PartStem}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		Part:            PartStem,
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode updates dry-run and auto-apply preferences.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetStyle stores the requested style and name portion.
+func (r *Request) SetStyle(style Style, part Part) {
+	r.Style = style
+	r.Part = part
+}
+
+// Normalize ensures working directory, style, and timestamp fields are ready for execution.
+func (r *Request) Normalize() error {
+	if _, err := ParseStyle(string(r.Style)); err != nil {
+		return err
+	}
+	part, err := ParsePart(string(r.Part))
+	if err != nil {
+		return err
+	}
+	r.Part = part
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package casing
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Style identifies a supported case conversion.
+type Style string
+
+const (
+	StyleLower    Style = "lower"
+	StyleUpper    Style = "upper"
+	StyleTitle    Style = "title"
+	StyleSentence Style = "sentence"
+	StyleSnake    Style = "snake"
+	StyleKebab    Style = "kebab"
+	StyleCamel    Style = "camel"
+	StylePascal   Style = "pascal"
+)
+
+// Styles lists every supported style in CLI display order.
+var Styles = []Style{StyleLower, StyleUpper, StyleTitle, StyleSentence, StyleSnake, StyleKebab, StyleCamel, StylePascal}
+
+// ParseStyle validates a user-supplied style token.
+func ParseStyle(value string) (Style, error) {
+	normalized := Style(strings.ToLower(strings.TrimSpace(value)))
+	for _, style := range Styles {
+		if style == normalized {
+			return style, nil
+		}
+	}
+	names := make([]string, len(Styles))
+	for i, style := range Styles {
+		names[i] = string(style)
+	}
+	return "", fmt.Errorf("unsupported case style %q (use %s)", value, strings.Join(names, ", "))
+}
+
+// Convert applies the style to value. Letter-case styles (lower, upper, title, sentence) keep the
+// original separators; naming-convention styles (snake, kebab, camel, pascal) rejoin the words.
+func Convert(value string, style Style) string {
+	switch style {
+	case StyleLower:
+		return strings.ToLower(value)
+	case StyleUpper:
+		return strings.ToUpper(value)
+	case StyleTitle:
+		return titleCase(value)
+	case StyleSentence:
+		return sentenceCase(value)
+	case StyleSnake:
+		return joinLower(SplitWords(value), "_")
+	case StyleKebab:
+		return joinLower(SplitWords(value), "-")
+	case StyleCamel:
+		words := SplitWords(value)
+		for i, word := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(word)
+				continue
+			}
+			words[i] = capitalize(word)
+		}
+		return strings.Join(words, "")
+	case StylePascal:
+		words := SplitWords(value)
+		for i, word := range words {
+			words[i] = capitalize(word)
+		}
+		return strings.Join(words, "")
+	default:
+		return value
+	}
+}
+
+// SplitWords breaks value into words on whitespace, underscores, and hyphens, as well as on
+// lower→upper transitions (fooBar), acronym endings (HTTPServer), and letter/digit boundaries
+// (file2name). Runes from scripts without case stay attached to their neighbours.
+func SplitWords(value string) []string {
+	runes := []rune(value)
+	words := make([]string, 0)
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		if isSeparator(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		switch {
+		case unicode.IsDigit(prev) != unicode.IsDigit(r):
+			flush(i)
+			start = i
+		case unicode.IsLower(prev) && unicode.IsUpper(r):
+			flush(i)
+			start = i
+		case unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == '_' || r == '-'
+}
+
+func joinLower(words []string, separator string) string {
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, separator)
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToTitle(runes[0])
+	return string(runes)
+}
+
+func titleCase(value string) string {
+	runes := []rune(strings.ToLower(value))
+	atWordStart := true
+	for i, r := range runes {
+		if isSeparator(r) {
+			atWordStart = true
+			continue
+		}
+		if atWordStart {
+			runes[i] = unicode.ToTitle(r)
+			atWordStart = false
+		}
+	}
+	return string(runes)
+}
+
+func sentenceCase(value string) string {
+	runes := []rune(strings.ToLower(value))
+	for i, r := range runes {
+		if unicode.IsLetter(r) {
+			runes[i] = unicode.ToTitle(r)
+			break
+		}
+	}
+	return string(runes)
+}
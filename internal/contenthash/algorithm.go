@@ -0,0 +1,61 @@
+package contenthash
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/zeebo/blake3"
+)
+
+// Algorithm names a supported digest.
+type Algorithm string
+
+const (
+	SHA256 Algorithm = "sha256"
+	XXHash Algorithm = "xxhash"
+	BLAKE3 Algorithm = "blake3"
+)
+
+// DefaultAlgorithm is used when none is specified.
+const DefaultAlgorithm = SHA256
+
+// Algorithms lists the supported digests in documentation order.
+var Algorithms = []Algorithm{SHA256, XXHash, BLAKE3}
+
+// ParseAlgorithm validates an algorithm name; empty selects DefaultAlgorithm.
+func ParseAlgorithm(value string) (Algorithm, error) {
+	switch alg := Algorithm(strings.ToLower(strings.TrimSpace(value))); alg {
+	case "":
+		return DefaultAlgorithm, nil
+	case SHA256, XXHash, BLAKE3:
+		return alg, nil
+	case "xxh64", "xxhash64":
+		return XXHash, nil
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm %q (use sha256, xxhash, or blake3)", value)
+	}
+}
+
+// HexLength is the length of the full hexadecimal digest.
+func (a Algorithm) HexLength() int {
+	switch a {
+	case XXHash:
+		return 16
+	default:
+		return 64
+	}
+}
+
+func (a Algorithm) newHash() hash.Hash {
+	switch a {
+	case XXHash:
+		return xxhash.New()
+	case BLAKE3:
+		return blake3.New()
+	default:
+		return sha256.New()
+	}
+}
@@ -0,0 +1,60 @@
+package contenthash
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+// SameContent reports whether two files hold identical bytes. Digest matches are confirmed this
+// way before files are reported as duplicates, so short digests such as xxHash can never merge
+// distinct files.
+func SameContent(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	if infoA.IsDir() || infoB.IsDir() || infoA.Size() != infoB.Size() {
+		return false, nil
+	}
+	if os.SameFile(infoA, infoB) {
+		return true, nil
+	}
+
+	fileA, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fileA.Close()
+	fileB, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fileB.Close()
+
+	bufA := make([]byte, bufferSize)
+	bufB := make([]byte, bufferSize)
+	for {
+		n, errA := io.ReadFull(fileA, bufA)
+		m, errB := io.ReadFull(fileB, bufB)
+		if n != m || !bytes.Equal(bufA[:n], bufB[:m]) {
+			return false, nil
+		}
+		doneA := errors.Is(errA, io.EOF) || errors.Is(errA, io.ErrUnexpectedEOF)
+		doneB := errors.Is(errB, io.EOF) || errors.Is(errB, io.ErrUnexpectedEOF)
+		if errA != nil && !doneA {
+			return false, errA
+		}
+		if errB != nil && !doneB {
+			return false, errB
+		}
+		if doneA || doneB {
+			return doneA && doneB, nil
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package contenthash computes streaming content digests (SHA-256, xxHash64, BLAKE3) for rename
+// tokens and duplicate detection, hashing many files in parallel with progress callbacks.
+package contenthash
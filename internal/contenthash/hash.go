@@ -0,0 +1,127 @@
+package contenthash
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// bufferSize is the read buffer used while streaming file content into a digest.
+const bufferSize = 256 << 10
+
+// File streams the file at path through the algorithm and returns the lowercase hex digest.
+func File(path string, alg Algorithm) (string, error) {
+	return fileWithCounter(path, alg, nil)
+}
+
+// Progress receives updates while files are hashed: files completed, files total, and bytes read
+// so far. It is called from worker goroutines but never concurrently.
+type Progress func(done, total int, bytes int64)
+
+// Options tune parallel hashing.
+type Options struct {
+	// Workers bounds concurrent reads; zero uses runtime.NumCPU().
+	Workers  int
+	Progress Progress
+}
+
+// Files hashes every path in parallel and returns digests keyed by path. The first read error
+// cancels the remaining work and is returned.
+func Files(ctx context.Context, paths []string, alg Algorithm, opts Options) (map[string]string, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		results  = make(map[string]string, len(paths))
+		firstErr error
+		done     int
+		bytes    atomic.Int64
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan string)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				digest, err := fileWithCounter(path, alg, &bytes)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					results[path] = digest
+					done++
+					if opts.Progress != nil {
+						opts.Progress(done, len(paths), bytes.Load())
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, path := range paths {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- path:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func fileWithCounter(path string, alg Algorithm, counter *atomic.Int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := alg.newHash()
+	var reader io.Reader = file
+	if counter != nil {
+		reader = &countingReader{reader: file, counter: counter}
+	}
+	if _, err := io.CopyBuffer(h, reader, make([]byte, bufferSize)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type countingReader struct {
+	reader  io.Reader
+	counter *atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.counter.Add(int64(n))
+	return n, err
+}
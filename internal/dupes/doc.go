@@ -0,0 +1,4 @@
+// Package dupes finds files with identical content within the active scope. Files are first
+// grouped by size, then only same-size files are hashed (in parallel) and confirmed byte for
+// byte, so large trees are read as little as possible.
+package dupes
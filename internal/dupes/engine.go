@@ -0,0 +1,176 @@
+package dupes
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+type file struct {
+	relative string
+	absolute string
+	size     int64
+}
+
+// Find scans the scope and returns groups of identical files, largest reclaimable space first.
+func Find(ctx context.Context, req *Request) (*Report, error) {
+	if req == nil {
+		return nil, errors.New("dupes request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, err
+	}
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	report := &Report{Algorithm: string(req.Algorithm)}
+	bySize := make(map[int64][]file)
+
+	err := traversal.NewWalker().Walk(
+		req.WorkingDir,
+		req.Recursive,
+		false,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." || entry.IsDir() {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			if !req.ExtensionModel.Allows(entry.Name(), filterSet) {
+				return nil
+			}
+			if !req.NameFilter.Allows(relative, false) {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() || info.Size() < req.MinSize {
+				return nil
+			}
+
+			report.Scanned++
+			report.ScannedBytes += info.Size()
+			bySize[info.Size()] = append(bySize[info.Size()], file{
+				relative: relative,
+				absolute: filepath.Join(req.WorkingDir, relPath),
+				size:     info.Size(),
+			})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only files sharing a size can be duplicates; everything else is never read.
+	candidates := make([]file, 0)
+	for _, files := range bySize {
+		if len(files) > 1 {
+			candidates = append(candidates, files...)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].relative < candidates[j].relative })
+	report.Hashed = len(candidates)
+
+	paths := make([]string, len(candidates))
+	for i, f := range candidates {
+		paths[i] = f.absolute
+	}
+	digests, err := contenthash.Files(ctx, paths, req.Algorithm, contenthash.Options{
+		Workers:  req.Workers,
+		Progress: req.Progress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byDigest := make(map[string][]file)
+	order := make([]string, 0)
+	for _, f := range candidates {
+		key := digests[f.absolute]
+		if _, seen := byDigest[key]; !seen {
+			order = append(order, key)
+		}
+		byDigest[key] = append(byDigest[key], f)
+	}
+
+	for _, key := range order {
+		files := byDigest[key]
+		if len(files) < 2 {
+			continue
+		}
+		groups, err := confirm(files)
+		if err != nil {
+			return nil, err
+		}
+		for _, members := range groups {
+			group := Group{Digest: key, Size: members[0].size, Paths: make([]string, len(members))}
+			for i, member := range members {
+				group.Paths[i] = member.relative
+			}
+			report.Groups = append(report.Groups, group)
+		}
+	}
+
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		wi := int64(report.Groups[i].Redundant()) * report.Groups[i].Size
+		wj := int64(report.Groups[j].Redundant()) * report.Groups[j].Size
+		if wi != wj {
+			return wi > wj
+		}
+		return report.Groups[i].Paths[0] < report.Groups[j].Paths[0]
+	})
+
+	return report, nil
+}
+
+// confirm splits files with equal digests into groups of byte-identical content, guarding
+// against collisions in short digests.
+func confirm(files []file) ([][]file, error) {
+	groups := make([][]file, 0, 1)
+	for _, f := range files {
+		placed := false
+		for i, group := range groups {
+			same, err := contenthash.SameContent(group[0].absolute, f.absolute)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				groups[i] = append(groups[i], f)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []file{f})
+		}
+	}
+
+	confirmed := make([][]file, 0, len(groups))
+	for _, group := range groups {
+		if len(group) > 1 {
+			confirmed = append(confirmed, group)
+		}
+	}
+	return confirmed, nil
+}
@@ -0,0 +1,39 @@
+package dupes
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rogeecn/renamer/internal/output"
+)
+
+// digestPreview is the number of digest characters shown per group.
+const digestPreview = 12
+
+// Render writes the duplicate groups and a summary line.
+func Render(out io.Writer, report *Report) {
+	if report.Scanned == 0 {
+		fmt.Fprintln(out, "No candidates found.")
+		return
+	}
+	if len(report.Groups) == 0 {
+		fmt.Fprintf(out, "No duplicate content found (%d files scanned).\n", report.Scanned)
+		return
+	}
+
+	for i, group := range report.Groups {
+		digest := group.Digest
+		if len(digest) > digestPreview {
+			digest = digest[:digestPreview]
+		}
+		fmt.Fprintf(out, "Group %d: %d identical files, %s each (%s %s)\n",
+			i+1, len(group.Paths), output.FormatBytes(group.Size), report.Algorithm, digest)
+		for _, path := range group.Paths {
+			fmt.Fprintf(out, "  %s\n", path)
+		}
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintf(out, "Summary: %d files scanned, %d hashed, %d duplicate groups, %d redundant copies (%s reclaimable)\n",
+		report.Scanned, report.Hashed, len(report.Groups), report.Redundant(), output.FormatBytes(report.Reclaimable()))
+}
@@ -0,0 +1,42 @@
+package dupes
+
+// Group is a set of files with identical content.
+type Group struct {
+	Digest string
+	Size   int64
+	// Paths are slash-separated and relative to the working directory, sorted.
+	Paths []string
+}
+
+// Redundant is the number of copies beyond the first.
+func (g Group) Redundant() int {
+	return len(g.Paths) - 1
+}
+
+// Report summarizes a duplicate scan.
+type Report struct {
+	Algorithm    string
+	Scanned      int
+	ScannedBytes int64
+	// Hashed counts files that shared a size with another file and were therefore read.
+	Hashed int
+	Groups []Group
+}
+
+// Redundant totals the redundant copies across groups.
+func (r *Report) Redundant() int {
+	total := 0
+	for _, group := range r.Groups {
+		total += group.Redundant()
+	}
+	return total
+}
+
+// Reclaimable totals the bytes that removing redundant copies would free.
+func (r *Report) Reclaimable() int64 {
+	var total int64
+	for _, group := range r.Groups {
+		total += int64(group.Redundant()) * group.Size
+	}
+	return total
+}
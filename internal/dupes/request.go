@@ -0,0 +1,86 @@
+package dupes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+)
+
+// Request captures the scope and hashing preferences for a duplicate scan.
+type Request struct {
+	WorkingDir      string
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Algorithm       contenthash.Algorithm
+	Workers         int
+	// MinSize skips files smaller than this many bytes; empty files are skipped by default.
+	MinSize  int64
+	Progress contenthash.Progress
+}
+
+// NewRequest constructs a Request from shared listing scope.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{}
+	}
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		MinSize:         1,
+	}
+}
+
+// SetHashing configures the digest algorithm, worker count, and progress callback.
+func (r *Request) SetHashing(alg contenthash.Algorithm, workers int, progress contenthash.Progress) {
+	r.Algorithm = alg
+	r.Workers = workers
+	r.Progress = progress
+}
+
+// Normalize resolves the working directory and fills defaults.
+func (r *Request) Normalize() error {
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Algorithm == "" {
+		r.Algorithm = contenthash.DefaultAlgorithm
+	}
+	if r.Workers < 0 {
+		return fmt.Errorf("workers must be zero (auto) or positive")
+	}
+	if r.MinSize < 0 {
+		return fmt.Errorf("min-size cannot be negative")
+	}
+	return nil
+}
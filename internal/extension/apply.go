@@ -2,7 +2,6 @@ package extension
 
 import (
 	"context"
-	"errors"
 	"os"
 	"path/filepath"
 	"sort"
@@ -24,21 +23,9 @@ func Apply(ctx context.Context, req *ExtensionRequest, planned []PlannedRename,
 
 	done := make([]history.Operation, 0, len(planned))
 
-	revert := func() error {
-		for i := len(done) - 1; i >= 0; i-- {
-			op := done[i]
-			source := filepath.Join(req.WorkingDir, filepath.FromSlash(op.To))
-			destination := filepath.Join(req.WorkingDir, filepath.FromSlash(op.From))
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
-		}
-		return nil
-	}
-
 	for _, op := range planned {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -47,7 +34,7 @@ func Apply(ctx context.Context, req *ExtensionRequest, planned []PlannedRename,
 		}
 
 		if err := os.Rename(op.OriginalAbsolute, op.ProposedAbsolute); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -57,6 +44,21 @@ func Apply(ctx context.Context, req *ExtensionRequest, planned []PlannedRename,
 		})
 	}
 
+	if summary != nil {
+		followers, err := summary.Sidecars.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, followers...)
+		edits, err := summary.References.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, edits...)
+	}
+
 	if len(done) == 0 {
 		return entry, nil
 	}
@@ -71,6 +73,15 @@ func Apply(ctx context.Context, req *ExtensionRequest, planned []PlannedRename,
 		if req.TargetExtension != "" {
 			meta["targetExtension"] = req.TargetExtension
 		}
+		if req.ByContent {
+			meta["byContent"] = true
+			meta["onlyMismatched"] = req.OnlyMismatched
+			verdicts := make(map[string]int, len(summary.Verdicts))
+			for verdict, count := range summary.Verdicts {
+				verdicts[string(verdict)] = count
+			}
+			meta["verdicts"] = verdicts
+		}
 		meta["totalCandidates"] = summary.TotalCandidates
 		meta["totalChanged"] = summary.TotalChanged
 		meta["noChange"] = summary.NoChange
@@ -91,6 +102,9 @@ func Apply(ctx context.Context, req *ExtensionRequest, planned []PlannedRename,
 		if len(req.ExtensionFilter) > 0 {
 			scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
 		}
+		if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+			scope["nameFilter"] = nameFilter
+		}
 		meta["scope"] = scope
 
 		if len(summary.Warnings) > 0 {
@@ -101,7 +115,7 @@ func Apply(ctx context.Context, req *ExtensionRequest, planned []PlannedRename,
 	}
 
 	if err := history.Append(req.WorkingDir, entry); err != nil {
-		_ = revert()
+		_ = history.Rollback(req.WorkingDir, done)
 		return history.Entry{}, err
 	}
 
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
 )
 
 type conflictDetector struct {
@@ -27,6 +29,16 @@ func (d *conflictDetector) evaluateTarget(summary *ExtensionSummary, candidateRe
 		return false, nil
 	}
 
+	if violation, ok := pathlimit.CheckPath(targetAbs); !ok {
+		summary.AddConflict(Conflict{
+			OriginalPath: candidateRel,
+			ProposedPath: targetRel,
+			Reason:       violation.Reason(),
+		})
+		summary.AddWarning(fmt.Sprintf("skipped %s because %s exceeds filesystem length limits", candidateRel, targetRel))
+		return false, nil
+	}
+
 	origInfo, origErr := os.Stat(originalAbs)
 	if origErr != nil {
 		return false, origErr
@@ -0,0 +1,181 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// placeholderExtensions are generic suffixes left by downloads and exports; they are replaced
+// rather than kept in front of the detected extension.
+var placeholderExtensions = map[string]struct{}{
+	".bin": {}, ".dat": {}, ".tmp": {}, ".download": {},
+}
+
+// buildContentPlan proposes an extension for every file from the type its magic bytes identify.
+func buildContentPlan(ctx context.Context, req *ExtensionRequest) (*PlanResult, error) {
+	summary := NewSummary()
+	operations := make([]PlannedRename, 0)
+	detector := newConflictDetector()
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, filter := range req.ExtensionFilter {
+		filterSet[CanonicalExtension(filter)] = struct{}{}
+	}
+
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		false,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." || entry.IsDir() {
+				return nil
+			}
+
+			name := entry.Name()
+			rawExt := strings.TrimSpace(filepath.Ext(name))
+			if rawExt == name {
+				// Dotfiles such as .env have a stem, not an extension.
+				rawExt = ""
+			}
+
+			if len(filterSet) > 0 {
+				if _, ok := filterSet[CanonicalExtension(rawExt)]; !ok {
+					return nil
+				}
+			}
+
+			if !req.NameFilter.Allows(relPath, false) {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(relative))
+
+			detected, ok, err := metadata.DetectType(originalAbsolute)
+			if err != nil {
+				summary.AddWarning(fmt.Sprintf("could not read %s: %v", relative, err))
+				return nil
+			}
+
+			verdict, stem := classify(name, rawExt, detected, ok)
+			if req.OnlyMismatched && verdict != VerdictMismatched {
+				return nil
+			}
+
+			previewEntry := PreviewEntry{
+				OriginalPath:    relative,
+				ProposedPath:    relative,
+				Status:          PreviewStatusNoChange,
+				SourceExtension: rawExt,
+				Verdict:         verdict,
+			}
+			if ok {
+				previewEntry.DetectedType = detected.Name
+				previewEntry.Confidence = detected.Confidence
+			}
+
+			switch verdict {
+			case VerdictAlias, VerdictMismatched, VerdictMissing:
+				targetRelative := stem + matchExtensionCase(detected.Canonical(), rawExt)
+				if dir := filepath.Dir(relative); dir != "." {
+					targetRelative = filepath.ToSlash(filepath.Join(dir, targetRelative))
+				}
+				targetAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(targetRelative))
+				previewEntry.ProposedPath = targetRelative
+
+				allowed, err := detector.evaluateTarget(summary, relative, targetRelative, originalAbsolute, targetAbsolute)
+				if err != nil {
+					return err
+				}
+				if allowed {
+					previewEntry.Status = PreviewStatusChanged
+					operations = append(operations, PlannedRename{
+						OriginalRelative: relative,
+						OriginalAbsolute: originalAbsolute,
+						ProposedRelative: targetRelative,
+						ProposedAbsolute: targetAbsolute,
+						SourceExtension:  rawExt,
+						Depth:            depth,
+					})
+				} else {
+					previewEntry.Status = PreviewStatusSkipped
+				}
+			}
+
+			summary.RecordEntry(previewEntry)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, err
+	}
+	summary.References = refs
+
+	return &PlanResult{
+		Summary:    summary,
+		Operations: operations,
+	}, nil
+}
+
+// classify compares a file's extension with its detected type, returning the verdict and the
+// part of the name the proposed extension is appended to.
+func classify(name, rawExt string, detected metadata.FileType, ok bool) (Verdict, string) {
+	stem := strings.TrimSuffix(name, rawExt)
+	switch {
+	case !ok:
+		return VerdictUnknown, name
+	case detected.IsAlias(rawExt):
+		return VerdictAlias, stem
+	case detected.Accepts(rawExt):
+		return VerdictMatched, name
+	case detected.Confidence == metadata.ConfidenceLow:
+		// Weak signatures are reported but never acted on.
+		return VerdictUncertain, name
+	case rawExt == "":
+		return VerdictMissing, name
+	}
+	if _, placeholder := placeholderExtensions[CanonicalExtension(rawExt)]; placeholder || metadata.KnownExtension(rawExt) {
+		return VerdictMismatched, stem
+	}
+	// "report.final" ends in a dotted word, not an extension, so the real one is appended.
+	return VerdictMissing, name
+}
+
+// matchExtensionCase upper-cases the proposal when the current extension is written in capitals,
+// so IMG_0001.JPEG becomes IMG_0001.JPG rather than IMG_0001.jpg.
+func matchExtensionCase(proposed, current string) string {
+	if current != "" && current == strings.ToUpper(current) && current != strings.ToLower(current) {
+		return strings.ToUpper(proposed)
+	}
+	return proposed
+}
@@ -3,10 +3,12 @@ package extension
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io/fs"
 	"path/filepath"
 	"strings"
 
+	"github.com/rogeecn/renamer/internal/sidecar"
 	"github.com/rogeecn/renamer/internal/traversal"
 )
 
@@ -35,6 +37,9 @@ func BuildPlan(ctx context.Context, req *ExtensionRequest) (*PlanResult, error)
 	if err := req.Normalize(); err != nil {
 		return nil, err
 	}
+	if req.ByContent {
+		return buildContentPlan(ctx, req)
+	}
 
 	summary := NewSummary()
 	operations := make([]PlannedRename, 0)
@@ -78,19 +83,28 @@ func BuildPlan(ctx context.Context, req *ExtensionRequest) (*PlanResult, error)
 			}
 
 			name := entry.Name()
-			rawExt := strings.TrimSpace(filepath.Ext(name))
+			// Compound extensions are one unit: ".gz" does not match "backup.tar.gz", whose
+			// extension is ".tar.gz".
+			rawExt := strings.TrimSpace(req.ExtensionModel.Ext(name))
 			canonicalExt := CanonicalExtension(rawExt)
 
-			if !isDir && len(filterSet) > 0 {
-				if _, ok := filterSet[canonicalExt]; !ok {
-					return nil
-				}
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relPath, isDir) {
+				return nil
 			}
 
 			_, sourceMatch := sourceSet[canonicalExt]
 			targetMatch := canonicalExt == targetCanonical && rawExt == targetExt
 
 			if !sourceMatch && !targetMatch {
+				if last := filepath.Ext(name); !isDir && last != rawExt {
+					if _, partial := sourceSet[CanonicalExtension(last)]; partial {
+						summary.AddWarning(fmt.Sprintf("%s keeps its compound extension %s; list %s as a source to change it", filepath.ToSlash(relPath), rawExt, rawExt))
+					}
+				}
 				return nil
 			}
 
@@ -153,6 +167,22 @@ func BuildPlan(ctx context.Context, req *ExtensionRequest) (*PlanResult, error)
 		return nil, err
 	}
 
+	// Followers stay candidates here because their own extension may be the one being changed;
+	// the sidecar plan only carries tails such as ".CR2" in IMG_0001.CR2.dop along with their head.
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, err
+	}
+	summary.References = refs
+
 	return &PlanResult{
 		Summary:    summary,
 		Operations: operations,
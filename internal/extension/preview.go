@@ -30,7 +30,14 @@ func Preview(ctx context.Context, req *ExtensionRequest, out io.Writer) (*Extens
 	}
 
 	if summary.TotalCandidates == 0 {
-		summary.AddWarning("no candidates found for provided extensions")
+		switch {
+		case req.OnlyMismatched:
+			summary.AddWarning("no files with mismatched extensions found")
+		case req.ByContent:
+			summary.AddWarning("no files found to inspect")
+		default:
+			summary.AddWarning("no candidates found for provided extensions")
+		}
 	}
 
 	if out != nil {
@@ -49,6 +56,10 @@ func Preview(ctx context.Context, req *ExtensionRequest, out io.Writer) (*Extens
 		}
 
 		for _, entry := range summary.Entries {
+			if req.ByContent {
+				writeContentEntry(out, entry, conflictReasons)
+				continue
+			}
 			switch entry.Status {
 			case PreviewStatusChanged:
 				if entry.ProposedPath == entry.OriginalPath {
@@ -68,8 +79,14 @@ func Preview(ctx context.Context, req *ExtensionRequest, out io.Writer) (*Extens
 				fmt.Fprintf(out, "%s (status: %s)\n", entry.OriginalPath, entry.Status)
 			}
 		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
 
-		if summary.TotalCandidates > 0 {
+		if summary.TotalCandidates > 0 && req.ByContent {
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d mismatched, %d missing extension, %d aliases, %d unknown type\n",
+				summary.TotalCandidates, summary.TotalChanged, summary.Verdicts[VerdictMismatched],
+				summary.Verdicts[VerdictMissing], summary.Verdicts[VerdictAlias], summary.Verdicts[VerdictUnknown])
+		} else if summary.TotalCandidates > 0 {
 			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d already target extension\n",
 				summary.TotalCandidates, summary.TotalChanged, summary.NoChange)
 		} else {
@@ -90,3 +107,28 @@ func Preview(ctx context.Context, req *ExtensionRequest, out io.Writer) (*Extens
 
 	return summary, plan.Operations, nil
 }
+
+// writeContentEntry prints a content-mode row, tagging it with the detected type and confidence.
+func writeContentEntry(out io.Writer, entry PreviewEntry, conflictReasons map[string]string) {
+	detection := ""
+	if entry.DetectedType != "" {
+		detection = fmt.Sprintf(" [%s, %s confidence]", entry.DetectedType, entry.Confidence)
+	}
+
+	switch {
+	case entry.Verdict == VerdictUnknown:
+		fmt.Fprintf(out, "%s (unknown type)\n", entry.OriginalPath)
+	case entry.Verdict == VerdictUncertain:
+		fmt.Fprintf(out, "%s (kept: low confidence)%s\n", entry.OriginalPath, detection)
+	case entry.Status == PreviewStatusSkipped:
+		reason := conflictReasons[fmt.Sprintf("%s->%s", entry.OriginalPath, entry.ProposedPath)]
+		if reason == "" {
+			reason = "skipped"
+		}
+		fmt.Fprintf(out, "%s -> %s (skipped: %s)%s\n", entry.OriginalPath, entry.ProposedPath, reason, detection)
+	case entry.Status == PreviewStatusChanged:
+		fmt.Fprintf(out, "%s -> %s%s\n", entry.OriginalPath, entry.ProposedPath, detection)
+	default:
+		fmt.Fprintf(out, "%s (no change)%s\n", entry.OriginalPath, detection)
+	}
+}
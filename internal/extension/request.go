@@ -1,12 +1,17 @@
 package extension
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
 	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // ExtensionRequest captures all inputs required to evaluate an extension normalization run.
@@ -18,11 +23,20 @@ type ExtensionRequest struct {
 	DuplicateSources        []string
 	NoOpSources             []string
 
+	// ByContent ignores source/target extensions and names each file after the type detected from
+	// its magic bytes; OnlyMismatched then limits the run to files whose extension names another type.
+	ByContent      bool
+	OnlyMismatched bool
+
 	IncludeDirs   bool
 	Recursive     bool
 	IncludeHidden bool
 
 	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
 
 	DryRun      bool
 	AutoConfirm bool
@@ -44,6 +58,10 @@ func NewRequest(scope *listing.ListingRequest) *ExtensionRequest {
 		Recursive:       scope.Recursive,
 		IncludeHidden:   scope.IncludeHidden,
 		ExtensionFilter: filterCopy,
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
 	}
 }
 
@@ -66,6 +84,12 @@ func (r *ExtensionRequest) SetWarnings(dupes, noOps []string) {
 	r.NoOpSources = append(r.NoOpSources[:0], noOps...)
 }
 
+// SetContentMode switches the request to magic-byte detection.
+func (r *ExtensionRequest) SetContentMode(byContent, onlyMismatched bool) {
+	r.ByContent = byContent
+	r.OnlyMismatched = onlyMismatched
+}
+
 // Normalize ensures working directory and timestamp fields are ready for execution.
 func (r *ExtensionRequest) Normalize() error {
 	if r.WorkingDir == "" {
@@ -92,6 +116,10 @@ func (r *ExtensionRequest) Normalize() error {
 		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
 	}
 
+	if r.OnlyMismatched && !r.ByContent {
+		return errors.New("only-mismatched requires by-content detection")
+	}
+
 	if r.Timestamp.IsZero() {
 		r.Timestamp = time.Now().UTC()
 	}
@@ -2,6 +2,10 @@ package extension
 
 import (
 	"strings"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // PreviewStatus represents the outcome for a single preview entry.
@@ -13,12 +17,35 @@ const (
 	PreviewStatusSkipped  PreviewStatus = "skipped"
 )
 
+// Verdict classifies a file's extension against the type detected from its content.
+type Verdict string
+
+const (
+	// VerdictMatched means the extension already names the detected type.
+	VerdictMatched Verdict = "matched"
+	// VerdictAlias means the extension is an alternative spelling, such as .jpeg for .jpg.
+	VerdictAlias Verdict = "alias"
+	// VerdictMismatched means the extension names a different type: a .jpg that is really PNG.
+	VerdictMismatched Verdict = "mismatched"
+	// VerdictMissing means the name carries no recognised extension at all.
+	VerdictMissing Verdict = "missing"
+	// VerdictUncertain means the detection is too weak to justify a rename.
+	VerdictUncertain Verdict = "uncertain"
+	// VerdictUnknown means no signature matched the content.
+	VerdictUnknown Verdict = "unknown"
+)
+
 // PreviewEntry captures a single original → proposed path mapping for preview output.
 type PreviewEntry struct {
 	OriginalPath    string
 	ProposedPath    string
 	Status          PreviewStatus
 	SourceExtension string
+
+	// Content-mode detection details; empty unless the request sets ByContent.
+	DetectedType string
+	Confidence   metadata.Confidence
+	Verdict      Verdict
 }
 
 // Conflict describes a proposed rename that cannot be applied safely.
@@ -34,11 +61,19 @@ type ExtensionSummary struct {
 	TotalChanged    int
 	NoChange        int
 
+	// Verdicts counts content-mode classifications.
+	Verdicts map[Verdict]int
+
 	PerExtensionCounts map[string]int
 	Conflicts          []Conflict
 	Warnings           []string
 	Entries            []PreviewEntry
 
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
+
 	LedgerMetadata map[string]any
 }
 
@@ -46,6 +81,7 @@ type ExtensionSummary struct {
 func NewSummary() *ExtensionSummary {
 	return &ExtensionSummary{
 		PerExtensionCounts: make(map[string]int),
+		Verdicts:           make(map[Verdict]int),
 		LedgerMetadata:     make(map[string]any),
 	}
 }
@@ -62,6 +98,10 @@ func (s *ExtensionSummary) RecordEntry(entry PreviewEntry) {
 		s.NoChange++
 	}
 
+	if entry.Verdict != "" {
+		s.Verdicts[entry.Verdict]++
+	}
+
 	if entry.SourceExtension != "" {
 		key := strings.ToLower(entry.SourceExtension)
 		s.PerExtensionCounts[key]++
@@ -0,0 +1,87 @@
+package fields
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+// Apply renames the planned entries deepest first, then moves sidecars and rewrites references,
+// and records the batch with the layout and mismatch count in the ledger.
+func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summary *Summary) (history.Entry, error) {
+	entry := history.Entry{Command: "fields"}
+
+	if len(planned) == 0 {
+		return entry, nil
+	}
+
+	sort.SliceStable(planned, func(i, j int) bool {
+		return planned[i].Depth > planned[j].Depth
+	})
+
+	done := make([]history.Operation, 0, len(planned))
+
+	for _, op := range planned {
+		if err := ctx.Err(); err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		if op.OriginalAbsolute == op.ProposedAbsolute {
+			continue
+		}
+
+		if err := history.RenamePath(op.OriginalAbsolute, op.ProposedAbsolute); err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		done = append(done, history.Operation{
+			From: op.OriginalRelative,
+			To:   op.ProposedRelative,
+		})
+	}
+
+	if summary != nil {
+		followers, err := summary.Sidecars.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, followers...)
+		edits, err := summary.References.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, edits...)
+	}
+
+	if len(done) == 0 {
+		return entry, nil
+	}
+
+	entry.Operations = done
+	if summary != nil {
+		meta := make(map[string]any, len(summary.LedgerMetadata))
+		for k, v := range summary.LedgerMetadata {
+			meta[k] = v
+		}
+		meta["totalCandidates"] = summary.TotalCandidates
+		meta["totalChanged"] = summary.TotalChanged
+		meta["noChange"] = summary.NoChange
+		meta["mismatched"] = summary.Mismatched
+		if len(summary.Warnings) > 0 {
+			meta["warnings"] = append([]string(nil), summary.Warnings...)
+		}
+		entry.Metadata = meta
+	}
+
+	if err := history.Append(req.WorkingDir, entry); err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+
+	return entry, nil
+}
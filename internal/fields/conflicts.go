@@ -0,0 +1,59 @@
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// conflictDetector tracks proposed targets using both exact and case-folded keys, so two names
+// that rearrange to the same result (dropping a field that differs) or differ only by case are
+// reported as conflicts.
+type conflictDetector struct {
+	planned     map[string]string
+	plannedFold map[string]string
+}
+
+func newConflictDetector() *conflictDetector {
+	return &conflictDetector{
+		planned:     make(map[string]string),
+		plannedFold: make(map[string]string),
+	}
+}
+
+// reserve records a path that keeps its current name so later targets cannot claim it.
+func (d *conflictDetector) reserve(relative string) {
+	d.planned[relative] = relative
+	d.plannedFold[strings.ToLower(relative)] = relative
+}
+
+// evaluate returns an empty reason when the rename may proceed, or a conflict reason otherwise.
+func (d *conflictDetector) evaluate(candidateRel, targetRel, originalAbs, targetAbs string) (string, error) {
+	if existing, ok := d.planned[targetRel]; ok && existing != candidateRel {
+		return fmt.Sprintf("duplicate_target with %s", existing), nil
+	}
+	if existing, ok := d.plannedFold[strings.ToLower(targetRel)]; ok && existing != candidateRel {
+		return fmt.Sprintf("case_fold_collision with %s", existing), nil
+	}
+
+	if info, err := os.Stat(targetAbs); err == nil {
+		origInfo, origErr := os.Stat(originalAbs)
+		if origErr != nil {
+			return "", origErr
+		}
+		// On case-insensitive filesystems the target resolves to the source itself.
+		if !os.SameFile(info, origInfo) {
+			if info.IsDir() {
+				return "existing_directory", nil
+			}
+			return "existing_file", nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	d.planned[targetRel] = candidateRel
+	d.plannedFold[strings.ToLower(targetRel)] = candidateRel
+	return "", nil
+}
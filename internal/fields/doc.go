@@ -0,0 +1,6 @@
+// Package fields treats a stem as a record: it is cut at literal delimiters (--split ", | - "),
+// the pieces are picked, reordered, or repeated by index with optional trim and case transforms
+// (--order 3,2:upper,1), and joined back with their own separators. "Doe, Jane - Report" becomes
+// "Report - Jane Doe" without a regular expression; names with the wrong number of fields are
+// reported and left alone.
+package fields
@@ -0,0 +1,185 @@
+package fields
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation is one rearranging rename, with paths both relative to and joined with the
+// working directory.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	IsDir            bool
+	Depth            int
+}
+
+// BuildPlan enumerates candidates, rearranges the fields of their stems, and prepares filesystem
+// operations. Names that split into the wrong number of fields are reported with StatusMismatch
+// and left alone.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("fields request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	summary.Expected = req.ExpectedCount()
+	operations := make([]PlannedOperation, 0)
+	detector := newConflictDetector()
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && (!req.ExtensionModel.Allows(name, filterSet) || req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) {
+				return nil
+			}
+
+			// Directories have no extension, so the whole name is split.
+			stem, ext := name, ""
+			if !isDir {
+				stem, ext = req.ExtensionModel.Split(name)
+			}
+			parts := Split(stem, req.Delimiters)
+
+			preview := PreviewEntry{
+				OriginalPath: relative,
+				ProposedPath: relative,
+				Status:       StatusChanged,
+				Fields:       len(parts),
+			}
+
+			if (summary.Expected > 0 && len(parts) != summary.Expected) || (summary.Expected == 0 && len(parts) < 2) {
+				preview.Status = StatusMismatch
+				detector.reserve(relative)
+				summary.RecordEntry(preview)
+				return nil
+			}
+
+			proposedStem := Join(parts, req.Order, req.Joiners, req.Trim)
+			proposedRelative := joinRelative(relative, proposedStem+ext)
+			originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(relative))
+			proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative))
+			preview.ProposedPath = proposedRelative
+
+			switch {
+			case proposedRelative == relative:
+				preview.Status = StatusNoChange
+				detector.reserve(relative)
+			case strings.TrimSpace(proposedStem) == "":
+				summary.AddConflict(Conflict{
+					OriginalPath: relative,
+					ProposedPath: proposedRelative,
+					Reason:       "empty_name",
+				})
+				preview.Status = StatusSkipped
+			default:
+				reason, err := detector.evaluate(relative, proposedRelative, originalAbsolute, proposedAbsolute)
+				if err != nil {
+					return err
+				}
+				if reason == "" {
+					if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+						reason = violation.Reason()
+					}
+				}
+				if reason != "" {
+					summary.AddConflict(Conflict{
+						OriginalPath: relative,
+						ProposedPath: proposedRelative,
+						Reason:       reason,
+					})
+					preview.Status = StatusSkipped
+					break
+				}
+				operations = append(operations, PlannedOperation{
+					OriginalRelative: relative,
+					OriginalAbsolute: originalAbsolute,
+					ProposedRelative: proposedRelative,
+					ProposedAbsolute: proposedAbsolute,
+					IsDir:            isDir,
+					Depth:            depth,
+				})
+			}
+
+			summary.RecordEntry(preview)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
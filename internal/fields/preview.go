@@ -0,0 +1,93 @@
+package fields
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Preview plans the rearrangements and prints each change, every field mismatch with the count it
+// found, and any conflicts to out.
+func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("fields request cannot be nil")
+	}
+
+	summary, operations, err := BuildPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary.LedgerMetadata["split"] = append([]string(nil), req.Delimiters...)
+	summary.LedgerMetadata["order"] = FormatOrder(req.Order)
+	summary.LedgerMetadata["join"] = append([]string(nil), req.Joiners...)
+	summary.LedgerMetadata["trim"] = req.Trim
+	summary.LedgerMetadata["count"] = summary.Expected
+	scope := map[string]any{
+		"includeDirs":   req.IncludeDirs,
+		"recursive":     req.Recursive,
+		"includeHidden": req.IncludeHidden,
+	}
+	if len(req.ExtensionFilter) > 0 {
+		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
+	summary.LedgerMetadata["scope"] = scope
+
+	if out != nil {
+		conflictReasons := make(map[string]string, len(summary.Conflicts))
+		for _, conflict := range summary.Conflicts {
+			conflictReasons[conflict.OriginalPath+"->"+conflict.ProposedPath] = conflict.Reason
+		}
+
+		expected := "at least 2"
+		if summary.Expected > 0 {
+			expected = fmt.Sprint(summary.Expected)
+		}
+
+		for _, entry := range summary.Entries {
+			switch entry.Status {
+			case StatusChanged:
+				fmt.Fprintf(out, "%s -> %s\n", entry.OriginalPath, entry.ProposedPath)
+			case StatusNoChange:
+				fmt.Fprintf(out, "%s (no change)\n", entry.OriginalPath)
+			case StatusMismatch:
+				fmt.Fprintf(out, "%s (field mismatch: %d %s, expected %s)\n", entry.OriginalPath, entry.Fields, plural(entry.Fields, "field", "fields"), expected)
+			case StatusSkipped:
+				reason := conflictReasons[entry.OriginalPath+"->"+entry.ProposedPath]
+				if reason == "" {
+					reason = "skipped"
+				}
+				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			}
+		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
+
+		if summary.TotalCandidates > 0 {
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d unchanged, %d field %s\n",
+				summary.TotalCandidates, summary.TotalChanged, summary.NoChange, summary.Mismatched, plural(summary.Mismatched, "mismatch", "mismatches"))
+		} else {
+			fmt.Fprintln(out, "No candidates found.")
+		}
+
+		if len(summary.Warnings) > 0 {
+			fmt.Fprintln(out)
+			for _, warning := range summary.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	return summary, operations, nil
+}
+
+func plural(count int, singular, many string) string {
+	if count == 1 {
+		return singular
+	}
+	return many
+}
@@ -0,0 +1,137 @@
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Request holds the scope and the split, order, and join layout of a fields run.
+type Request struct {
+	WorkingDir string
+	Delimiters []string
+	Order      []FieldSpec
+	Joiners    []string
+	// Trim strips surrounding whitespace from every field.
+	Trim bool
+	// Count is the field count a name must have; zero requires the highest position in Order,
+	// or at least two fields when Order is empty.
+	Count           int
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest copies the shared scope flags into a fields Request.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{Trim: true}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		Trim:            true,
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode records whether the run only previews or applies without prompting.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetLayout stores how names are split, which fields are kept in what order, and how they are
+// rejoined.
+func (r *Request) SetLayout(delimiters []string, order []FieldSpec, joiners []string, trim bool, count int) {
+	r.Delimiters = delimiters
+	r.Order = order
+	r.Joiners = joiners
+	r.Trim = trim
+	r.Count = count
+}
+
+// ExpectedCount returns the field count a name must split into, or zero when any count of at
+// least two is accepted.
+func (r *Request) ExpectedCount() int {
+	if r.Count > 0 {
+		return r.Count
+	}
+	highest := 0
+	for _, spec := range r.Order {
+		highest = max(highest, spec.Index)
+	}
+	return highest
+}
+
+// Normalize requires a delimiter, defaults the joiners to the first delimiter, checks that the
+// order fits the field count, and resolves the working directory.
+func (r *Request) Normalize() error {
+	if len(r.Delimiters) == 0 {
+		return errors.New("at least one split delimiter is required")
+	}
+	if r.Count < 0 {
+		return errors.New("field count cannot be negative")
+	}
+	if len(r.Joiners) == 0 {
+		r.Joiners = append([]string(nil), r.Delimiters[0])
+	}
+	for _, spec := range r.Order {
+		if r.Count > 0 && spec.Index > r.Count {
+			return fmt.Errorf("order references field %d but --count is %d", spec.Index, r.Count)
+		}
+	}
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
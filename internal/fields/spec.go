@@ -0,0 +1,138 @@
+package fields
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/casing"
+)
+
+// FieldSpec selects one input field for the output. Index is 1-based; Styles run in order after
+// the optional trim.
+type FieldSpec struct {
+	Index  int
+	Trim   bool
+	Styles []casing.Style
+}
+
+// ParseDelimiters splits a pipe-delimited --split value into literal delimiters.
+func ParseDelimiters(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, errors.New("--split requires at least one delimiter")
+	}
+	delimiters := make([]string, 0)
+	for _, part := range strings.Split(raw, "|") {
+		if part == "" {
+			return nil, fmt.Errorf("empty delimiter in %q", raw)
+		}
+		delimiters = append(delimiters, part)
+	}
+	return delimiters, nil
+}
+
+// ParseJoiners splits a pipe-delimited --join value. The n-th joiner goes between output fields
+// n and n+1 and the last one repeats, so a single value joins every field the same way.
+func ParseJoiners(raw string) ([]string, error) {
+	joiners := strings.Split(raw, "|")
+	for _, joiner := range joiners {
+		if strings.ContainsAny(joiner, `/\`) || strings.ContainsRune(joiner, 0) {
+			return nil, fmt.Errorf("joiner %q cannot contain path separators", joiner)
+		}
+	}
+	return joiners, nil
+}
+
+// ParseOrder parses an --order value such as "3,1:upper,2:trim:title". Omitted fields are
+// dropped and repeated ones duplicated. Modifiers are "trim" or any case style.
+func ParseOrder(raw string) ([]FieldSpec, error) {
+	specs := make([]FieldSpec, 0)
+	for _, token := range strings.Split(raw, ",") {
+		parts := strings.Split(strings.TrimSpace(token), ":")
+		index, err := strconv.Atoi(parts[0])
+		if err != nil || index < 1 {
+			return nil, fmt.Errorf("invalid field %q in order (use 1-based positions such as 3,1,2)", token)
+		}
+		spec := FieldSpec{Index: index}
+		for _, modifier := range parts[1:] {
+			if strings.EqualFold(strings.TrimSpace(modifier), "trim") {
+				spec.Trim = true
+				continue
+			}
+			style, err := casing.ParseStyle(modifier)
+			if err != nil {
+				return nil, fmt.Errorf("field %d: %w", index, err)
+			}
+			spec.Styles = append(spec.Styles, style)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// Split cuts value at every occurrence of any delimiter, scanning left to right and preferring
+// the longest delimiter at a position.
+func Split(value string, delimiters []string) []string {
+	fields := make([]string, 0, 4)
+	start := 0
+	for i := 0; i < len(value); {
+		matched := ""
+		for _, delimiter := range delimiters {
+			if len(delimiter) > len(matched) && strings.HasPrefix(value[i:], delimiter) {
+				matched = delimiter
+			}
+		}
+		if matched == "" {
+			i++
+			continue
+		}
+		fields = append(fields, value[start:i])
+		i += len(matched)
+		start = i
+	}
+	return append(fields, value[start:])
+}
+
+// Join arranges fields according to specs, applying each spec's transforms. Without specs the
+// fields keep their order.
+func Join(fields []string, specs []FieldSpec, joiners []string, trim bool) string {
+	if len(specs) == 0 {
+		specs = make([]FieldSpec, len(fields))
+		for i := range fields {
+			specs[i] = FieldSpec{Index: i + 1}
+		}
+	}
+
+	var builder strings.Builder
+	for i, spec := range specs {
+		if i > 0 && len(joiners) > 0 {
+			builder.WriteString(joiners[min(i-1, len(joiners)-1)])
+		}
+		value := fields[spec.Index-1]
+		if trim || spec.Trim {
+			value = strings.TrimSpace(value)
+		}
+		for _, style := range spec.Styles {
+			value = casing.Convert(value, style)
+		}
+		builder.WriteString(value)
+	}
+	return builder.String()
+}
+
+// FormatOrder renders specs back into --order syntax for ledger metadata.
+func FormatOrder(specs []FieldSpec) string {
+	tokens := make([]string, len(specs))
+	for i, spec := range specs {
+		token := strconv.Itoa(spec.Index)
+		if spec.Trim {
+			token += ":trim"
+		}
+		for _, style := range spec.Styles {
+			token += ":" + string(style)
+		}
+		tokens[i] = token
+	}
+	return strings.Join(tokens, ",")
+}
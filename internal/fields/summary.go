@@ -0,0 +1,106 @@
+package fields
+
+import (
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Status reports what rearranging the fields does to a candidate's name.
+type Status string
+
+const (
+	StatusChanged  Status = "changed"
+	StatusNoChange Status = "no_change"
+	StatusSkipped  Status = "skipped"
+	// StatusMismatch marks a name that does not split into the expected number of fields; it is
+	// left alone without blocking the rest of the batch.
+	StatusMismatch Status = "field_mismatch"
+)
+
+// PreviewEntry pairs a candidate with its rearranged name and the number of fields it held.
+type PreviewEntry struct {
+	OriginalPath string
+	ProposedPath string
+	Status       Status
+	// Fields is the number of fields the name split into.
+	Fields int
+}
+
+// Conflict records a rearranged name that collides with another target or an existing entry,
+// as when dropping a field leaves two names identical.
+type Conflict struct {
+	OriginalPath string
+	ProposedPath string
+	Reason       string
+}
+
+// Summary collects the fields preview, including how many names failed to split into the
+// expected number of fields.
+type Summary struct {
+	TotalCandidates int
+	TotalChanged    int
+	NoChange        int
+	// Mismatched counts names whose field count differs from the expected one.
+	Mismatched int
+	// Expected is the required field count, or zero when any count of at least two is accepted.
+	Expected int
+
+	Entries   []PreviewEntry
+	Conflicts []Conflict
+	Warnings  []string
+
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
+
+	LedgerMetadata map[string]any
+}
+
+// NewSummary returns a Summary ready for BuildPlan to fill.
+func NewSummary() *Summary {
+	return &Summary{
+		Entries:        make([]PreviewEntry, 0),
+		Conflicts:      make([]Conflict, 0),
+		Warnings:       make([]string, 0),
+		LedgerMetadata: make(map[string]any),
+	}
+}
+
+// RecordEntry adds entry to the preview and counts it as changed, unchanged, or mismatched.
+func (s *Summary) RecordEntry(entry PreviewEntry) {
+	s.Entries = append(s.Entries, entry)
+	s.TotalCandidates++
+
+	switch entry.Status {
+	case StatusChanged:
+		s.TotalChanged++
+	case StatusNoChange:
+		s.NoChange++
+	case StatusMismatch:
+		s.Mismatched++
+	}
+}
+
+// AddConflict records a collision; mismatched names are entries, not conflicts.
+func (s *Summary) AddConflict(conflict Conflict) {
+	s.Conflicts = append(s.Conflicts, conflict)
+}
+
+// AddWarning records msg once, however many candidates raise it.
+func (s *Summary) AddWarning(msg string) {
+	if msg == "" {
+		return
+	}
+	for _, existing := range s.Warnings {
+		if existing == msg {
+			return
+		}
+	}
+	s.Warnings = append(s.Warnings, msg)
+}
+
+// HasConflicts reports whether any rearranged name collides.
+func (s *Summary) HasConflicts() bool {
+	return len(s.Conflicts) > 0
+}
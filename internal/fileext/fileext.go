@@ -0,0 +1,95 @@
+// Package fileext splits file names into stem and extension with awareness of multi-part
+// extensions such as ".tar.gz" or ".d.ts", so engines that edit the stem never cut an archive or
+// declaration suffix in half.
+package fileext
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCompound lists the multi-part extensions recognised without configuration.
+var DefaultCompound = []string{
+	".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst", ".tar.lz4",
+	".d.ts", ".d.mts", ".d.cts", ".min.js", ".min.css",
+}
+
+// Model splits names using DefaultCompound plus any configured extras. The zero value recognises
+// the defaults only, so engines built without a scope still treat archives correctly.
+type Model struct {
+	extra []string
+}
+
+// New builds a model recognising the default compound extensions plus extra. Every extra entry
+// must start with a dot and contain at least two parts (".tar.lz", ".spec.ts").
+func New(extra []string) (Model, error) {
+	model := Model{}
+	seen := make(map[string]struct{}, len(extra))
+	for _, raw := range extra {
+		ext := strings.ToLower(strings.TrimSpace(raw))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") || strings.Count(ext, ".") < 2 || strings.Contains(ext, "..") || strings.HasSuffix(ext, ".") {
+			return Model{}, fmt.Errorf("compound extension %q must look like .part.part", raw)
+		}
+		if strings.ContainsAny(ext, "/\\") {
+			return Model{}, fmt.Errorf("compound extension %q cannot contain path separators", raw)
+		}
+		if _, dup := seen[ext]; dup {
+			continue
+		}
+		seen[ext] = struct{}{}
+		model.extra = append(model.extra, ext)
+	}
+	return model, nil
+}
+
+// Extras returns the configured extensions beyond DefaultCompound.
+func (m Model) Extras() []string {
+	return append([]string(nil), m.extra...)
+}
+
+// Split separates name into stem and extension. A configured compound suffix wins (the longest
+// when several match); otherwise the last dot starts the extension, as with filepath.Ext. The stem
+// is never empty: dotfiles such as ".env" are all stem, and ".env.local" has extension ".local".
+func (m Model) Split(name string) (stem, ext string) {
+	lower := strings.ToLower(name)
+	best := ""
+	for _, list := range [][]string{DefaultCompound, m.extra} {
+		for _, compound := range list {
+			if len(compound) > len(best) && len(name) > len(compound) && strings.HasSuffix(lower, compound) {
+				best = compound
+			}
+		}
+	}
+	if best != "" {
+		cut := len(name) - len(best)
+		return name[:cut], name[cut:]
+	}
+	ext = filepath.Ext(name)
+	if ext == name {
+		return name, ""
+	}
+	return strings.TrimSuffix(name, ext), ext
+}
+
+// Ext returns the extension Split would report for name.
+func (m Model) Ext(name string) string {
+	_, ext := m.Split(name)
+	return ext
+}
+
+// Allows reports whether name passes an --extensions filter of lower-cased extensions. Both the
+// full compound extension and its final part are accepted, so ".gz" still selects "a.tar.gz".
+func (m Model) Allows(name string, filter map[string]struct{}) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	if _, ok := filter[strings.ToLower(m.Ext(name))]; ok {
+		return true
+	}
+	_, ok := filter[strings.ToLower(filepath.Ext(name))]
+	return ok
+}
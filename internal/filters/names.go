@@ -0,0 +1,131 @@
+package filters
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+)
+
+// NameMatchTarget selects which portion of a candidate path the name filter evaluates.
+type NameMatchTarget string
+
+const (
+	// MatchTargetStem matches against the base name without its extension (default).
+	MatchTargetStem NameMatchTarget = "stem"
+	// MatchTargetBasename matches against the base name including its extension.
+	MatchTargetBasename NameMatchTarget = "basename"
+	// MatchTargetPath matches against the slash-separated path relative to the working directory.
+	MatchTargetPath NameMatchTarget = "path"
+)
+
+// NameFilter limits candidates to names matching --match and not matching --not-match.
+// Extensions decides where the stem ends for MatchTargetStem. The zero value accepts every candidate.
+type NameFilter struct {
+	Match      *regexp.Regexp
+	NotMatch   *regexp.Regexp
+	Target     NameMatchTarget
+	Extensions fileext.Model
+}
+
+// ParseNameMatchTarget validates a stem, basename, or path target; empty selects stem.
+func ParseNameMatchTarget(value string) (NameMatchTarget, error) {
+	switch target := NameMatchTarget(strings.ToLower(strings.TrimSpace(value))); target {
+	case "":
+		return MatchTargetStem, nil
+	case MatchTargetStem, MatchTargetBasename, MatchTargetPath:
+		return target, nil
+	default:
+		return "", fmt.Errorf("unsupported match target %q (use stem, basename, or path)", value)
+	}
+}
+
+// ParseNameFilter compiles the include/exclude expressions and validates the match target.
+// Empty expressions are ignored so callers can pass raw flag values directly.
+func ParseNameFilter(match, notMatch, target string) (NameFilter, error) {
+	parsedTarget, err := ParseNameMatchTarget(target)
+	if err != nil {
+		return NameFilter{}, err
+	}
+	filter := NameFilter{Target: parsedTarget}
+
+	if match != "" {
+		re, err := regexp.Compile(match)
+		if err != nil {
+			return NameFilter{}, fmt.Errorf("invalid --match expression: %w", err)
+		}
+		filter.Match = re
+	}
+
+	if notMatch != "" {
+		re, err := regexp.Compile(notMatch)
+		if err != nil {
+			return NameFilter{}, fmt.Errorf("invalid --not-match expression: %w", err)
+		}
+		filter.NotMatch = re
+	}
+
+	return filter, nil
+}
+
+// Active reports whether the filter excludes anything.
+func (f NameFilter) Active() bool {
+	return f.Match != nil || f.NotMatch != nil
+}
+
+// Allows reports whether the candidate at relPath passes both expressions.
+func (f NameFilter) Allows(relPath string, isDir bool) bool {
+	if !f.Active() {
+		return true
+	}
+
+	subject := f.subject(relPath, isDir)
+	if f.Match != nil && !f.Match.MatchString(subject) {
+		return false
+	}
+	if f.NotMatch != nil && f.NotMatch.MatchString(subject) {
+		return false
+	}
+	return true
+}
+
+// Metadata returns a ledger-friendly description of the filter, or nil when inactive.
+func (f NameFilter) Metadata() map[string]any {
+	if !f.Active() {
+		return nil
+	}
+
+	target := f.Target
+	if target == "" {
+		target = MatchTargetStem
+	}
+
+	meta := map[string]any{"target": string(target)}
+	if f.Match != nil {
+		meta["match"] = f.Match.String()
+	}
+	if f.NotMatch != nil {
+		meta["notMatch"] = f.NotMatch.String()
+	}
+	return meta
+}
+
+func (f NameFilter) subject(relPath string, isDir bool) string {
+	rel := filepath.ToSlash(relPath)
+	switch f.Target {
+	case MatchTargetPath:
+		return rel
+	case MatchTargetBasename:
+		return path.Base(rel)
+	default:
+		base := path.Base(rel)
+		if isDir {
+			return base
+		}
+		stem, _ := f.Extensions.Split(base)
+		return stem
+	}
+}
@@ -0,0 +1,87 @@
+package flatten
+
+import (
+	"context"
+
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+// Apply moves the planned files to the top level, removes the directories left empty, and records
+// both in the ledger. Removals are recorded after the moves, so undo recreates the directories
+// before moving files back into them.
+func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summary *Summary) (history.Entry, error) {
+	entry := history.Entry{Command: "flatten"}
+
+	if len(planned) == 0 {
+		return entry, nil
+	}
+
+	done := make([]history.Operation, 0, len(planned))
+
+	for _, op := range planned {
+		if err := ctx.Err(); err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		if err := history.RenamePath(op.OriginalAbsolute, op.ProposedAbsolute); err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+
+		done = append(done, history.Operation{
+			From: op.OriginalRelative,
+			To:   op.ProposedRelative,
+		})
+	}
+
+	if summary != nil {
+		followers, err := summary.Sidecars.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, followers...)
+		edits, err := summary.References.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, edits...)
+	}
+
+	removed := 0
+	if summary != nil && len(summary.EmptiedDirectories) > 0 {
+		ops, err := history.RemoveEmptyDirs(req.WorkingDir, summary.EmptiedDirectories)
+		done = append(done, ops...)
+		removed = len(ops)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+	}
+
+	entry.Operations = done
+	meta := make(map[string]any)
+	if summary != nil {
+		for k, v := range summary.LedgerMetadata {
+			meta[k] = v
+		}
+		meta["totalCandidates"] = summary.TotalCandidates
+		meta["suffixed"] = summary.Suffixed
+		meta["skipped"] = summary.Skipped
+		if len(summary.Warnings) > 0 {
+			meta["warnings"] = append([]string(nil), summary.Warnings...)
+		}
+	}
+	meta["moved"] = len(planned)
+	meta["removedDirectories"] = removed
+	entry.Metadata = meta
+
+	if err := history.Append(req.WorkingDir, entry); err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,92 @@
+package flatten
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxSuffixAttempts bounds the search for a free deduplication suffix.
+const maxSuffixAttempts = 10000
+
+// errSuffixesExhausted signals that every suffix up to maxSuffixAttempts is already taken.
+var errSuffixesExhausted = errors.New("suffixes_exhausted")
+
+// targetAllocator hands out collision-free target names in the working directory. Keys are
+// case-folded so the plan stays valid on case-insensitive filesystems. Candidates are visited in
+// lexical walk order, so under the suffix policy the first claimant keeps the flattened name.
+type targetAllocator struct {
+	workingDir string
+	planned    map[string]string
+}
+
+func newTargetAllocator(workingDir string) *targetAllocator {
+	return &targetAllocator{workingDir: workingDir, planned: make(map[string]string)}
+}
+
+// reserve records a top-level file that stays so later targets cannot claim it.
+func (a *targetAllocator) reserve(relative string) {
+	a.planned[strings.ToLower(relative)] = relative
+}
+
+// allocate returns a free target for the candidate. Without suffixing, a taken name yields the
+// conflicting path and no target. The boolean reports whether a suffix was needed.
+func (a *targetAllocator) allocate(candidateRel, targetRel string, suffix bool) (string, bool, string, error) {
+	ext := path.Ext(targetRel)
+	stem := strings.TrimSuffix(targetRel, ext)
+	if stem == "" {
+		stem, ext = ext, ""
+	}
+
+	originalAbs := filepath.Join(a.workingDir, filepath.FromSlash(candidateRel))
+	attempts := 1
+	if suffix {
+		attempts = maxSuffixAttempts
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
+		proposed := targetRel
+		if attempt > 0 {
+			proposed = fmt.Sprintf("%s_%d%s", stem, attempt, ext)
+		}
+
+		holder, err := a.holder(candidateRel, proposed, originalAbs)
+		if err != nil {
+			return "", false, "", err
+		}
+		if holder == "" {
+			a.planned[strings.ToLower(proposed)] = candidateRel
+			return proposed, attempt > 0, "", nil
+		}
+		if !suffix {
+			return "", false, holder, nil
+		}
+	}
+
+	return "", false, "", errSuffixesExhausted
+}
+
+// holder returns the path already occupying proposedRel, or "" when it is free.
+func (a *targetAllocator) holder(candidateRel, proposedRel, originalAbs string) (string, error) {
+	if existing, ok := a.planned[strings.ToLower(proposedRel)]; ok && existing != candidateRel {
+		return existing, nil
+	}
+
+	info, err := os.Stat(filepath.Join(a.workingDir, filepath.FromSlash(proposedRel)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	origInfo, err := os.Stat(originalAbs)
+	if err != nil {
+		return "", err
+	}
+	if os.SameFile(info, origInfo) {
+		return "", nil
+	}
+	return proposedRel, nil
+}
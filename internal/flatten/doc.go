@@ -0,0 +1,4 @@
+// Package flatten moves every file of a nested tree into the working directory, folding the
+// directory segments into the name (a/b/c.jpg -> a_b_c.jpg), and removes the directories the
+// move leaves empty. Both the moves and the removals are recorded for undo.
+package flatten
@@ -0,0 +1,223 @@
+package flatten
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation moves one nested file to the top level under its folded name.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+}
+
+// BuildPlan walks the whole tree, folds each nested file's directory segments into its name, and
+// predicts which directories the moves leave empty.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("flatten request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	allocator := newTargetAllocator(req.WorkingDir)
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	candidates := make([]string, 0)
+	err := traversal.NewWalker().Walk(
+		req.WorkingDir,
+		true,
+		false,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." || entry.IsDir() {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			if !req.ExtensionModel.Allows(entry.Name(), filterSet) {
+				return nil
+			}
+			if !req.NameFilter.Allows(relative, false) || req.Sidecars.IsFollower(relative) {
+				return nil
+			}
+			candidates = append(candidates, relative)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(candidates)
+
+	// Top-level files keep their names; reserve them before any nested file is placed.
+	for _, relative := range candidates {
+		if !strings.Contains(relative, "/") {
+			allocator.reserve(relative)
+		}
+	}
+
+	moved := make(map[string]bool)
+	for _, relative := range candidates {
+		preview := PreviewEntry{OriginalPath: relative, ProposedPath: relative, Status: StatusNoChange}
+		if !strings.Contains(relative, "/") {
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		target := strings.ReplaceAll(relative, "/", req.Joiner)
+		preview.ProposedPath = target
+		preview.Status = StatusChanged
+
+		if violation, ok := pathlimit.Check(req.WorkingDir, target); !ok {
+			summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: violation.Reason()})
+			preview.Status = StatusSkipped
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		allocated, suffixed, holder, err := allocator.allocate(relative, target, req.Collision == CollisionSuffix)
+		if errors.Is(err, errSuffixesExhausted) {
+			summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: err.Error()})
+			preview.Status = StatusSkipped
+			summary.RecordEntry(preview)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if holder != "" {
+			preview.Status = StatusSkipped
+			reason := "collision with " + holder
+			if req.Collision == CollisionSkip {
+				preview.Reason = reason
+			} else {
+				summary.AddConflict(Conflict{OriginalPath: relative, ProposedPath: target, Reason: reason})
+			}
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		preview.ProposedPath = allocated
+		preview.Suffixed = suffixed
+		moved[relative] = true
+		operations = append(operations, PlannedOperation{
+			OriginalRelative: relative,
+			OriginalAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(relative)),
+			ProposedRelative: allocated,
+			ProposedAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(allocated)),
+		})
+		summary.RecordEntry(preview)
+	}
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+	for _, rename := range summary.Sidecars.Renames {
+		moved[rename.From] = true
+	}
+
+	if !req.KeepEmpty {
+		emptied, err := emptiedDirectories(req.WorkingDir, moved)
+		if err != nil {
+			return nil, nil, err
+		}
+		summary.EmptiedDirectories = emptied
+	}
+
+	return summary, operations, nil
+}
+
+// emptiedDirectories returns the ancestors of moved files that will hold nothing once the moves
+// are done: every child is either a moved file or another emptied directory. Hidden and
+// out-of-scope entries keep their directory alive.
+func emptiedDirectories(workingDir string, moved map[string]bool) ([]string, error) {
+	ancestors := make(map[string]bool)
+	for relative := range moved {
+		for dir := path.Dir(relative); dir != "."; dir = path.Dir(dir) {
+			ancestors[dir] = true
+		}
+	}
+
+	memo := make(map[string]bool)
+	var empties func(dir string) (bool, error)
+	empties = func(dir string) (bool, error) {
+		if result, ok := memo[dir]; ok {
+			return result, nil
+		}
+		entries, err := os.ReadDir(filepath.Join(workingDir, filepath.FromSlash(dir)))
+		if err != nil {
+			return false, err
+		}
+		result := true
+		for _, entry := range entries {
+			child := dir + "/" + entry.Name()
+			if entry.IsDir() && ancestors[child] {
+				childEmpty, err := empties(child)
+				if err != nil {
+					return false, err
+				}
+				if !childEmpty {
+					result = false
+					break
+				}
+				continue
+			}
+			if !moved[child] {
+				result = false
+				break
+			}
+		}
+		memo[dir] = result
+		return result, nil
+	}
+
+	emptied := make([]string, 0)
+	for dir := range ancestors {
+		empty, err := empties(dir)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			emptied = append(emptied, dir)
+		}
+	}
+	sort.Strings(emptied)
+	return emptied, nil
+}
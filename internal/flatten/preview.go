@@ -0,0 +1,92 @@
+package flatten
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Preview plans the moves and prints each file with its folded name, then the directories that
+// will be removed, to out.
+func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("flatten request cannot be nil")
+	}
+
+	summary, operations, err := BuildPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary.LedgerMetadata["flatten"] = map[string]any{
+		"joiner":    req.Joiner,
+		"collision": string(req.Collision),
+		"keepEmpty": req.KeepEmpty,
+	}
+	scope := map[string]any{
+		"includeHidden": req.IncludeHidden,
+	}
+	if len(req.ExtensionFilter) > 0 {
+		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
+	summary.LedgerMetadata["scope"] = scope
+
+	if out != nil {
+		conflictReasons := make(map[string]string, len(summary.Conflicts))
+		for _, conflict := range summary.Conflicts {
+			conflictReasons[conflict.OriginalPath] = conflict.Reason
+		}
+
+		for _, entry := range summary.Entries {
+			switch entry.Status {
+			case StatusChanged:
+				if entry.Suffixed {
+					fmt.Fprintf(out, "%s -> %s (suffixed)\n", entry.OriginalPath, entry.ProposedPath)
+					continue
+				}
+				fmt.Fprintf(out, "%s -> %s\n", entry.OriginalPath, entry.ProposedPath)
+			case StatusNoChange:
+				fmt.Fprintf(out, "%s (no change)\n", entry.OriginalPath)
+			case StatusSkipped:
+				if entry.Reason != "" {
+					fmt.Fprintf(out, "%s (skipped: %s)\n", entry.OriginalPath, entry.Reason)
+					continue
+				}
+				reason := conflictReasons[entry.OriginalPath]
+				if reason == "" {
+					reason = "skipped"
+				}
+				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			}
+		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
+
+		if len(summary.EmptiedDirectories) > 0 {
+			fmt.Fprintln(out, "\nDirectories to remove:")
+			for _, dir := range summary.EmptiedDirectories {
+				fmt.Fprintf(out, "  %s/\n", dir)
+			}
+		}
+
+		if summary.TotalCandidates > 0 {
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will move, %d already at the top level, %d skipped, %d directories to remove\n",
+				summary.TotalCandidates, summary.TotalChanged, summary.NoChange, summary.Skipped, len(summary.EmptiedDirectories))
+		} else {
+			fmt.Fprintln(out, "No candidates found.")
+		}
+
+		if len(summary.Warnings) > 0 {
+			fmt.Fprintln(out)
+			for _, warning := range summary.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	return summary, operations, nil
+}
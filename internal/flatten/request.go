@@ -0,0 +1,127 @@
+package flatten
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// CollisionPolicy decides what happens when a flattened name is already taken.
+type CollisionPolicy string
+
+const (
+	// CollisionFail reports the collision as a conflict, blocking apply.
+	CollisionFail CollisionPolicy = "fail"
+	// CollisionSuffix appends _1, _2, … to the stem until the name is free.
+	CollisionSuffix CollisionPolicy = "suffix"
+	// CollisionSkip leaves the colliding file where it is.
+	CollisionSkip CollisionPolicy = "skip"
+)
+
+// ParseCollisionPolicy validates an --on-collision value.
+func ParseCollisionPolicy(value string) (CollisionPolicy, error) {
+	switch policy := CollisionPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return CollisionFail, nil
+	case CollisionFail, CollisionSuffix, CollisionSkip:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported collision policy %q (use fail, suffix, or skip)", value)
+	}
+}
+
+// Request encapsulates the inputs required to flatten a tree. Flatten always walks the whole
+// tree, so --recursive is implied and directories are never candidates themselves.
+type Request struct {
+	WorkingDir      string
+	Joiner          string
+	Collision       CollisionPolicy
+	KeepEmpty       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest copies the shared scope flags into a flatten Request.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	req := &Request{Joiner: "_", Collision: CollisionFail}
+	if scope == nil {
+		return req
+	}
+	req.WorkingDir = scope.WorkingDir
+	req.IncludeHidden = scope.IncludeHidden
+	req.ExtensionFilter = append([]string(nil), scope.Extensions...)
+	req.ExtensionModel = scope.ExtensionModel
+	req.NameFilter = scope.NameFilter
+	req.Sidecars = scope.Sidecars
+	req.References = scope.References
+	return req
+}
+
+// SetExecutionMode records whether the run only previews or applies without prompting.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetLayout stores the segment joiner, collision policy, and whether emptied directories stay.
+func (r *Request) SetLayout(joiner string, collision CollisionPolicy, keepEmpty bool) {
+	r.Joiner = joiner
+	r.Collision = collision
+	r.KeepEmpty = keepEmpty
+}
+
+// Normalize checks the joiner, defaults the collision policy, and resolves the working directory.
+func (r *Request) Normalize() error {
+	if strings.ContainsAny(r.Joiner, "/\\") {
+		return errors.New("joiner cannot contain path separators")
+	}
+	if r.Collision == "" {
+		r.Collision = CollisionFail
+	}
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
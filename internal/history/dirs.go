@@ -0,0 +1,106 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OperationMkdir marks a ledger operation that created a directory. From is empty and To holds
+// the new directory relative to the working directory. Undo removes the directory again once
+// the entries moved into it have been moved back, leaving it in place if anything else was
+// added since.
+const OperationMkdir = "mkdir"
+
+// OperationRmdir marks a ledger operation that removed an emptied directory. From holds the
+// removed directory and To is empty; undo recreates it before moving entries back into it.
+const OperationRmdir = "rmdir"
+
+// EnsureDir creates the slash-separated directory relDir under workingDir, including missing
+// parents, and returns one mkdir operation per directory it created, outermost first.
+func EnsureDir(workingDir, relDir string) ([]Operation, error) {
+	relDir = path.Clean(filepath.ToSlash(relDir))
+	if relDir == "." || relDir == "" {
+		return nil, nil
+	}
+
+	created := make([]Operation, 0)
+	current := ""
+	for _, part := range strings.Split(relDir, "/") {
+		current = path.Join(current, part)
+		absolute := filepath.Join(workingDir, filepath.FromSlash(current))
+		info, err := os.Stat(absolute)
+		if err == nil {
+			if !info.IsDir() {
+				return created, &os.PathError{Op: "mkdir", Path: absolute, Err: errors.New("not a directory")}
+			}
+			continue
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return created, err
+		}
+		if err := os.Mkdir(absolute, 0o755); err != nil {
+			return created, err
+		}
+		created = append(created, Operation{To: current, Kind: OperationMkdir})
+	}
+	return created, nil
+}
+
+// RemoveCreatedDir deletes a directory recorded by EnsureDir if it is empty. Missing and
+// non-empty directories are left alone.
+func RemoveCreatedDir(workingDir, relDir string) error {
+	absolute := filepath.Join(workingDir, filepath.FromSlash(relDir))
+	entries, err := os.ReadDir(absolute)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return nil
+	}
+	if err := os.Remove(absolute); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// RemoveEmptyDirs removes each slash-separated directory in dirs that is empty, deepest first, and
+// returns one rmdir operation per directory removed. Directories that still hold entries are
+// kept.
+func RemoveEmptyDirs(workingDir string, dirs []string) ([]Operation, error) {
+	ordered := append([]string(nil), dirs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return strings.Count(ordered[i], "/") > strings.Count(ordered[j], "/")
+	})
+
+	removed := make([]Operation, 0, len(ordered))
+	for _, dir := range ordered {
+		absolute := filepath.Join(workingDir, filepath.FromSlash(dir))
+		entries, err := os.ReadDir(absolute)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		if len(entries) > 0 {
+			continue
+		}
+		if err := os.Remove(absolute); err != nil {
+			return removed, err
+		}
+		removed = append(removed, Operation{From: dir, Kind: OperationRmdir})
+	}
+	return removed, nil
+}
+
+// RestoreDir recreates a directory recorded by RemoveEmptyDirs.
+func RestoreDir(workingDir, relDir string) error {
+	return os.MkdirAll(filepath.Join(workingDir, filepath.FromSlash(relDir)), 0o755)
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// OperationEdit marks a ledger operation that rewrote a file's contents in place. To holds the
+// file relative to the working directory once the batch's renames have run, Backup the copy of
+// its previous contents, and Digest the SHA-256 of what was written. Undo restores the backup
+// only while the file still matches Digest, so later edits are never overwritten.
+const OperationEdit = "edit"
+
+// backupDirName holds the previous contents of edited files next to the ledger, one file per
+// SHA-256 digest, so documents of any size and encoding survive byte for byte.
+const backupDirName = ".renamer-backups"
+
+// WriteContent replaces the contents of the slash-separated file rel under workingDir, keeping
+// its permissions, and returns the edit operation that restores before. The write is refused
+// when the file no longer holds before.
+func WriteContent(workingDir, rel string, before, after []byte) (Operation, error) {
+	absolute := filepath.Join(workingDir, filepath.FromSlash(rel))
+	info, err := os.Stat(absolute)
+	if err != nil {
+		return Operation{}, err
+	}
+	current, err := os.ReadFile(absolute)
+	if err != nil {
+		return Operation{}, err
+	}
+	if string(current) != string(before) {
+		return Operation{}, fmt.Errorf("%s changed since the preview", rel)
+	}
+	backup, created, err := saveBackup(workingDir, before)
+	if err != nil {
+		return Operation{}, fmt.Errorf("back up %s: %w", rel, err)
+	}
+	if err := os.WriteFile(absolute, after, info.Mode().Perm()); err != nil {
+		if created {
+			_ = os.Remove(filepath.Join(workingDir, filepath.FromSlash(backup)))
+			_ = os.Remove(filepath.Join(workingDir, backupDirName))
+		}
+		return Operation{}, err
+	}
+	return Operation{To: rel, Kind: OperationEdit, Backup: backup, Digest: digest(after)}, nil
+}
+
+// CheckContent reports an error when the file recorded by an edit operation was changed after
+// the edit, which would make restoring it lose data.
+func CheckContent(workingDir string, op Operation) error {
+	current, err := os.ReadFile(filepath.Join(workingDir, filepath.FromSlash(op.To)))
+	if err != nil {
+		return err
+	}
+	if digest(current) != op.Digest {
+		return fmt.Errorf("%s was modified after renamer edited it; restore it manually", op.To)
+	}
+	return nil
+}
+
+// RestoreContent writes back the contents recorded by an edit operation. The backup itself is
+// left in place until Undo or Rollback finds that no ledger entry needs it.
+func RestoreContent(workingDir string, op Operation) error {
+	if err := CheckContent(workingDir, op); err != nil {
+		return err
+	}
+	previous, err := os.ReadFile(filepath.Join(workingDir, filepath.FromSlash(op.Backup)))
+	if err != nil {
+		return fmt.Errorf("read backup of %s: %w", op.To, err)
+	}
+	if digest(previous) != path.Base(op.Backup) {
+		return fmt.Errorf("backup of %s (%s) is damaged; restore it manually", op.To, op.Backup)
+	}
+	absolute := filepath.Join(workingDir, filepath.FromSlash(op.To))
+	info, err := os.Stat(absolute)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(absolute, previous, info.Mode().Perm())
+}
+
+// discardUnrecorded removes the backups of ops that no ledger entry references, and the backup
+// directory once it is empty, after a batch that never reached the ledger was reverted. Call it
+// once for the whole batch, as edits with identical previous contents share a backup.
+func discardUnrecorded(workingDir string, ops []Operation) error {
+	entries, err := readEntries(workingDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return discardBackups(workingDir, ops, entries)
+}
+
+func discardBackups(workingDir string, ops []Operation, remaining []Entry) error {
+	referenced := make(map[string]struct{})
+	for _, entry := range remaining {
+		for _, op := range entry.Operations {
+			if op.Kind == OperationEdit {
+				referenced[op.Backup] = struct{}{}
+			}
+		}
+	}
+	var errs []error
+	for _, op := range ops {
+		if op.Kind != OperationEdit || op.Backup == "" {
+			continue
+		}
+		if _, ok := referenced[op.Backup]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(workingDir, filepath.FromSlash(op.Backup))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	// Fails harmlessly while other backups remain.
+	_ = os.Remove(filepath.Join(workingDir, backupDirName))
+	return errors.Join(errs...)
+}
+
+// saveBackup stores data under the backup directory and returns its slash-separated path
+// relative to workingDir, and whether this call created it. Identical contents share one backup.
+func saveBackup(workingDir string, data []byte) (string, bool, error) {
+	rel := path.Join(backupDirName, digest(data))
+	absolute := filepath.Join(workingDir, filepath.FromSlash(rel))
+	if _, err := os.Stat(absolute); err == nil {
+		return rel, false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(absolute), 0o755); err != nil {
+		return "", false, err
+	}
+	tmp := absolute + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", false, err
+	}
+	if err := os.Rename(tmp, absolute); err != nil {
+		_ = os.Remove(tmp)
+		return "", false, err
+	}
+	return rel, true, nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
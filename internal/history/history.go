@@ -6,15 +6,24 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 const ledgerFileName = ".renamer"
 
-// Operation records a single rename from source to target.
+// maxLedgerLine bounds a single ledger entry; large batches record one operation per path.
+const maxLedgerLine = 64 << 20
+
+// Operation records a single rename from source to target. Kind is empty for renames; see
+// OperationMkdir and OperationRmdir for directory creations and removals, and OperationEdit for
+// content rewrites.
 type Operation struct {
-	From string `json:"from"`
-	To   string `json:"to"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Kind   string `json:"kind,omitempty"`
+	Backup string `json:"backup,omitempty"`
+	Digest string `json:"digest,omitempty"`
 }
 
 // Entry represents a batch of operations appended to the ledger.
@@ -55,42 +64,30 @@ func Append(workingDir string, entry Entry) error {
 func Undo(workingDir string) (Entry, error) {
 	path := ledgerPath(workingDir)
 
-	file, err := os.Open(path)
+	entries, err := readEntries(workingDir)
 	if errors.Is(err, os.ErrNotExist) {
 		return Entry{}, errors.New("no ledger entries available")
 	} else if err != nil {
 		return Entry{}, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	entries := make([]Entry, 0)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-		var e Entry
-		if err := json.Unmarshal(append([]byte(nil), line...), &e); err != nil {
-			return Entry{}, err
-		}
-		entries = append(entries, e)
-	}
-	if err := scanner.Err(); err != nil {
-		return Entry{}, err
-	}
 	if len(entries) == 0 {
 		return Entry{}, errors.New("no ledger entries available")
 	}
 
 	last := entries[len(entries)-1]
 
+	// Refuse before touching anything when an edited file changed since.
+	for _, op := range last.Operations {
+		if op.Kind == OperationEdit {
+			if err := CheckContent(workingDir, op); err != nil {
+				return Entry{}, err
+			}
+		}
+	}
+
 	// Revert operations in reverse order.
 	for i := len(last.Operations) - 1; i >= 0; i-- {
-		op := last.Operations[i]
-		source := filepath.Join(workingDir, op.To)
-		destination := filepath.Join(workingDir, op.From)
-		if err := os.Rename(source, destination); err != nil {
+		if err := revertOperation(workingDir, last.Operations[i]); err != nil {
 			return Entry{}, err
 		}
 	}
@@ -121,9 +118,47 @@ func Undo(workingDir string) (Entry, error) {
 		}
 	}
 
+	if err := discardBackups(workingDir, last.Operations, entries[:len(entries)-1]); err != nil {
+		return Entry{}, err
+	}
+
 	return last, nil
 }
 
+// readEntries parses every entry in the ledger under workingDir, oldest first.
+func readEntries(workingDir string) ([]Entry, error) {
+	file, err := os.Open(ledgerPath(workingDir))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLedgerLine)
+	entries := make([]Entry, 0)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(append([]byte(nil), line...), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// IsLedgerPath reports whether the slash-separated path rel, relative to a working directory,
+// is the ledger or one of the edit backups it references.
+func IsLedgerPath(rel string) bool {
+	return rel == ledgerFileName || rel == backupDirName || strings.HasPrefix(rel, backupDirName+"/")
+}
+
 // ledgerPath returns the absolute path to the ledger file under workingDir.
 func ledgerPath(workingDir string) string {
 	return filepath.Join(workingDir, ledgerFileName)
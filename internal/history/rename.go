@@ -0,0 +1,105 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// RenamePath renames source to destination. When the two paths differ only by letter case or
+// Unicode normalization the rename is routed through a temporary sibling name, because
+// case- and normalization-insensitive filesystems (and some network shares) treat a direct
+// rename as a no-op or reject it outright.
+func RenamePath(source, destination string) error {
+	if source == destination || !equivalentPaths(source, destination) {
+		return os.Rename(source, destination)
+	}
+
+	temp, err := temporarySibling(source)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(source, temp); err != nil {
+		return err
+	}
+	if err := os.Rename(temp, destination); err != nil {
+		_ = os.Rename(temp, source)
+		return err
+	}
+	return nil
+}
+
+func equivalentPaths(a, b string) bool {
+	return strings.EqualFold(norm.NFC.String(a), norm.NFC.String(b))
+}
+
+func temporarySibling(path string) (string, error) {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	for i := 0; i < 1000; i++ {
+		candidate := filepath.Join(dir, fmt.Sprintf(".%s.renamer-tmp-%d", base, i))
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("unable to allocate temporary name for %s", path)
+}
+
+// OrderRenames sequences renames whose targets may be other renames' sources, as happens when
+// files are renumbered in place. A rename runs only after the rename vacating its target; cycles
+// (a swap, say) are broken by first moving one source to a temporary sibling. The returned
+// operations include those temporary hops, so recording them in the ledger keeps undo exact.
+func OrderRenames(workingDir string, ops []Operation) ([]Operation, error) {
+	bySource := make(map[string]int, len(ops))
+	for i, op := range ops {
+		bySource[op.From] = i
+	}
+
+	const (
+		pending = iota
+		visiting
+		emitted
+	)
+	state := make([]int, len(ops))
+	current := append([]Operation(nil), ops...)
+	ordered := make([]Operation, 0, len(ops))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		state[i] = visiting
+		if j, ok := bySource[current[i].To]; ok && j != i {
+			switch state[j] {
+			case pending:
+				if err := visit(j); err != nil {
+					return err
+				}
+			case visiting:
+				// The target's occupant is waiting on this rename: park it under a temporary name.
+				temp, err := temporarySibling(filepath.Join(workingDir, filepath.FromSlash(current[j].From)))
+				if err != nil {
+					return err
+				}
+				tempRel := filepath.ToSlash(filepath.Join(filepath.Dir(filepath.FromSlash(current[j].From)), filepath.Base(temp)))
+				ordered = append(ordered, Operation{From: current[j].From, To: tempRel})
+				delete(bySource, current[j].From)
+				current[j].From = tempRel
+			}
+		}
+		ordered = append(ordered, current[i])
+		delete(bySource, current[i].From)
+		state[i] = emitted
+		return nil
+	}
+
+	for i := range current {
+		if state[i] == pending {
+			if err := visit(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,38 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Rollback reverts ops, the steps an apply completed before it failed, newest first, and discards
+// the backups of edits that will never reach the ledger. Renames whose target is already gone are
+// skipped, so a rollback can finish after another process moved a file away.
+func Rollback(workingDir string, ops []Operation) error {
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		if err := revertOperation(workingDir, op); err != nil {
+			if op.Kind == "" && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+	}
+	return discardUnrecorded(workingDir, ops)
+}
+
+// revertOperation undoes a single ledger operation.
+func revertOperation(workingDir string, op Operation) error {
+	switch op.Kind {
+	case OperationMkdir:
+		return RemoveCreatedDir(workingDir, op.To)
+	case OperationRmdir:
+		return RestoreDir(workingDir, op.From)
+	case OperationEdit:
+		return RestoreContent(workingDir, op)
+	}
+	source := filepath.Join(workingDir, filepath.FromSlash(op.To))
+	destination := filepath.Join(workingDir, filepath.FromSlash(op.From))
+	return RenamePath(source, destination)
+}
@@ -0,0 +1,224 @@
+package insert
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/template"
+)
+
+// AnchorKind identifies how an insertion point is located in the stem.
+type AnchorKind string
+
+const (
+	// AnchorPosition resolves a position token (`^`, `$`, `N`, `N$`) with ResolvePosition.
+	AnchorPosition AnchorKind = "position"
+	// AnchorBefore and AnchorAfter locate a literal substring.
+	AnchorBefore AnchorKind = "before"
+	AnchorAfter  AnchorKind = "after"
+	// AnchorBeforeRegex and AnchorAfterRegex locate an RE2 match.
+	AnchorBeforeRegex AnchorKind = "before-regex"
+	AnchorAfterRegex  AnchorKind = "after-regex"
+)
+
+// Anchor locates an insertion point. Content anchors pick the Nth non-overlapping occurrence
+// (1-based); negative values count from the end, so -1 is the last occurrence.
+type Anchor struct {
+	Kind       AnchorKind
+	Value      string
+	Occurrence int
+
+	re *regexp.Regexp
+}
+
+// PositionAnchor builds an anchor from a position token.
+func PositionAnchor(token string) Anchor {
+	return Anchor{Kind: AnchorPosition, Value: token}
+}
+
+// NewContentAnchor builds a literal or regex anchor, compiling regex values up front.
+func NewContentAnchor(kind AnchorKind, value string, occurrence int) (Anchor, error) {
+	if value == "" {
+		return Anchor{}, fmt.Errorf("--%s value cannot be empty", kind)
+	}
+	if occurrence == 0 {
+		return Anchor{}, errors.New("occurrence must be a positive index or a negative offset from the end")
+	}
+	anchor := Anchor{Kind: kind, Value: value, Occurrence: occurrence}
+	switch kind {
+	case AnchorBefore, AnchorAfter:
+	case AnchorBeforeRegex, AnchorAfterRegex:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return Anchor{}, fmt.Errorf("invalid --%s expression: %w", kind, err)
+		}
+		anchor.re = re
+	default:
+		return Anchor{}, fmt.Errorf("unsupported anchor kind %q", kind)
+	}
+	return anchor, nil
+}
+
+// String renders the anchor for previews and ledger metadata, e.g. "before:_v" or "before:_v#2".
+func (a Anchor) String() string {
+	if a.Kind == AnchorPosition {
+		return a.Value
+	}
+	if a.Occurrence == 1 {
+		return string(a.Kind) + ":" + a.Value
+	}
+	return fmt.Sprintf("%s:%s#%d", a.Kind, a.Value, a.Occurrence)
+}
+
+// resolve returns the byte offset in stem where text is inserted. found is false when a content
+// anchor does not occur often enough; position tokens out of range return an error instead.
+func (a Anchor) resolve(stem string) (offset int, found bool, err error) {
+	if a.Kind == AnchorPosition {
+		position, err := ResolvePosition(a.Value, utf8.RuneCountInString(stem))
+		if err != nil {
+			return 0, false, err
+		}
+		return runeOffset(stem, position.Index), true, nil
+	}
+
+	spans := make([][2]int, 0)
+	switch a.Kind {
+	case AnchorBefore, AnchorAfter:
+		for offset := 0; ; {
+			index := strings.Index(stem[offset:], a.Value)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			spans = append(spans, [2]int{start, start + len(a.Value)})
+			offset = start + len(a.Value)
+		}
+	default:
+		for _, loc := range a.re.FindAllStringIndex(stem, -1) {
+			spans = append(spans, [2]int{loc[0], loc[1]})
+		}
+	}
+
+	index := a.Occurrence - 1
+	if a.Occurrence < 0 {
+		index = len(spans) + a.Occurrence
+	}
+	if index < 0 || index >= len(spans) {
+		return 0, false, nil
+	}
+	if a.Kind == AnchorBefore || a.Kind == AnchorBeforeRegex {
+		return spans[index][0], true, nil
+	}
+	return spans[index][1], true, nil
+}
+
+func runeOffset(value string, runes int) int {
+	offset := 0
+	for i := 0; i < runes && offset < len(value); i++ {
+		_, size := utf8.DecodeRuneInString(value[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// Insertion pairs an anchor with the text inserted there. Text is literal unless tokens are
+// enabled, in which case it may use template tokens such as {parent}, {n:03}, or
+// {mtime:2006-01-02} and literal braces are written as {{ and }}.
+type Insertion struct {
+	Anchor Anchor
+	Text   string
+
+	tmpl *template.Template
+}
+
+// NewInsertion validates text and, when tokens is set, compiles its template tokens.
+func NewInsertion(anchor Anchor, text string, tokens bool) (Insertion, error) {
+	if err := validateInsertText(text); err != nil {
+		return Insertion{}, err
+	}
+	if !tokens {
+		return Insertion{Anchor: anchor, Text: text}, nil
+	}
+	tmpl, err := template.Parse(text)
+	if err != nil {
+		return Insertion{}, fmt.Errorf("insert text: %w", err)
+	}
+	return Insertion{Anchor: anchor, Text: text, tmpl: tmpl}, nil
+}
+
+// ParseAt parses a POSITION=TEXT pair used for additional insertions; tokens is passed to
+// NewInsertion.
+func ParseAt(value string, tokens bool) (Insertion, error) {
+	token, text, ok := strings.Cut(value, "=")
+	if !ok || token == "" {
+		return Insertion{}, fmt.Errorf("invalid --at value %q (use POSITION=TEXT, e.g. 3$=_v2)", value)
+	}
+	if _, err := ResolvePosition(token, 1<<20); err != nil {
+		return Insertion{}, fmt.Errorf("invalid --at value %q: %w", value, err)
+	}
+	return NewInsertion(PositionAnchor(token), text, tokens)
+}
+
+// errAnchorAbsent reports the first content anchor missing from a stem.
+type errAnchorAbsent struct {
+	anchor Anchor
+}
+
+func (e errAnchorAbsent) Error() string {
+	return "anchor not found: " + e.anchor.String()
+}
+
+// applyInsertions resolves every insertion against the original stem, so earlier insertions do
+// not shift later indexes, and splices the rendered texts in. Insertions at the same offset keep
+// their command-line order.
+func applyInsertions(stem string, insertions []Insertion, ctx *template.Context) (string, string, error) {
+	type point struct {
+		offset int
+		text   string
+	}
+	points := make([]point, 0, len(insertions))
+	inserted := make([]string, 0, len(insertions))
+	for _, insertion := range insertions {
+		offset, found, err := insertion.Anchor.resolve(stem)
+		if err != nil {
+			return "", "", err
+		}
+		if !found {
+			return "", "", errAnchorAbsent{anchor: insertion.Anchor}
+		}
+		text := insertion.Text
+		if insertion.tmpl != nil {
+			rendered, _, err := insertion.tmpl.Render(ctx)
+			if err != nil {
+				return "", "", err
+			}
+			if strings.ContainsAny(rendered, "/\\") {
+				return "", "", fmt.Errorf("insert text %q rendered %q, which contains a path separator", insertion.Text, rendered)
+			}
+			text = rendered
+		}
+		points = append(points, point{offset: offset, text: text})
+		inserted = append(inserted, text)
+	}
+
+	var builder strings.Builder
+	last := 0
+	for len(points) > 0 {
+		next := 0
+		for i, p := range points {
+			if p.offset < points[next].offset {
+				next = i
+			}
+		}
+		builder.WriteString(stem[last:points[next].offset])
+		builder.WriteString(points[next].text)
+		last = points[next].offset
+		points = append(points[:next], points[next+1:]...)
+	}
+	builder.WriteString(stem[last:])
+
+	return builder.String(), strings.Join(inserted, ""), nil
+}
@@ -2,9 +2,7 @@ package insert
 
 import (
 	"context"
-	"errors"
 	"os"
-	"path/filepath"
 	"sort"
 
 	"github.com/rogeecn/renamer/internal/history"
@@ -24,21 +22,9 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 
 	done := make([]history.Operation, 0, len(planned))
 
-	revert := func() error {
-		for i := len(done) - 1; i >= 0; i-- {
-			op := done[i]
-			source := filepath.Join(req.WorkingDir, filepath.FromSlash(op.To))
-			destination := filepath.Join(req.WorkingDir, filepath.FromSlash(op.From))
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
-		}
-		return nil
-	}
-
 	for _, op := range planned {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -47,7 +33,7 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 		}
 
 		if err := os.Rename(op.OriginalAbsolute, op.ProposedAbsolute); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -57,6 +43,21 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 		})
 	}
 
+	if summary != nil {
+		followers, err := summary.Sidecars.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, followers...)
+		edits, err := summary.References.Apply(req.WorkingDir)
+		if err != nil {
+			_ = history.Rollback(req.WorkingDir, done)
+			return history.Entry{}, err
+		}
+		done = append(done, edits...)
+	}
+
 	if len(done) == 0 {
 		return entry, nil
 	}
@@ -70,6 +71,9 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 		meta["totalCandidates"] = summary.TotalCandidates
 		meta["totalChanged"] = summary.TotalChanged
 		meta["noChange"] = summary.NoChange
+		if summary.AnchorAbsent > 0 {
+			meta["anchorAbsent"] = summary.AnchorAbsent
+		}
 		if len(summary.Warnings) > 0 {
 			meta["warnings"] = append([]string(nil), summary.Warnings...)
 		}
@@ -77,7 +81,7 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 	}
 
 	if err := history.Append(req.WorkingDir, entry); err != nil {
-		_ = revert()
+		_ = history.Rollback(req.WorkingDir, done)
 		return history.Entry{}, err
 	}
 
@@ -9,6 +9,9 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/template"
 	"github.com/rogeecn/renamer/internal/traversal"
 )
 
@@ -42,6 +45,7 @@ func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation,
 	}
 
 	walker := traversal.NewWalker()
+	index := 0
 
 	err := walker.Walk(
 		req.WorkingDir,
@@ -71,36 +75,43 @@ func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation,
 			ext := ""
 			stem := name
 			if !isDir {
-				ext = filepath.Ext(name)
-				stem = strings.TrimSuffix(name, ext)
+				stem, ext = req.ExtensionModel.Split(name)
 			}
 
-			if !isDir && len(filterSet) > 0 {
-				lowerExt := strings.ToLower(ext)
-				if _, ok := filterSet[lowerExt]; !ok {
-					return nil
-				}
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
 			}
 
-			stemRunes := []rune(stem)
-			if err := ParseInputs(req.PositionToken, req.InsertText, len(stemRunes)); err != nil {
-				return err
+			if !req.NameFilter.Allows(relative, isDir) || (!isDir && req.Sidecars.IsFollower(relative)) {
+				return nil
 			}
 
-			position, err := ResolvePosition(req.PositionToken, len(stemRunes))
+			info, err := entry.Info()
 			if err != nil {
 				return err
 			}
+			ctx := template.NewContext(req.WorkingDir, relative, info, index, req.ExtensionModel)
+			index++
 
 			status := StatusChanged
 			proposedRelative := relative
 			proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(relative))
 
-			var builder strings.Builder
-			builder.WriteString(string(stemRunes[:position.Index]))
-			builder.WriteString(req.InsertText)
-			builder.WriteString(string(stemRunes[position.Index:]))
-			proposedName := builder.String() + ext
+			newStem, insertedText, err := applyInsertions(stem, req.Insertions, ctx)
+			var absent errAnchorAbsent
+			if errors.As(err, &absent) {
+				summary.RecordEntry(PreviewEntry{
+					OriginalPath: relative,
+					ProposedPath: relative,
+					Status:       StatusAnchorAbsent,
+					Reason:       absent.Error(),
+				})
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			proposedName := newStem + ext
 
 			dir := filepath.Dir(relative)
 			if dir == "." {
@@ -126,6 +137,14 @@ func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation,
 					})
 					summary.AddWarning(reason)
 					status = StatusSkipped
+				} else if violation, ok := pathlimit.CheckPath(proposedAbsolute); !ok {
+					summary.AddConflict(Conflict{
+						OriginalPath: relative,
+						ProposedPath: proposedRelative,
+						Reason:       violation.Reason(),
+					})
+					detector.Forget(proposedRelative)
+					status = StatusSkipped
 				} else if info, err := os.Stat(proposedAbsolute); err == nil {
 					origInfo, origErr := os.Stat(filepath.Join(req.WorkingDir, filepath.FromSlash(relative)))
 					if origErr != nil {
@@ -155,7 +174,7 @@ func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation,
 						OriginalAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(relative)),
 						ProposedRelative: proposedRelative,
 						ProposedAbsolute: proposedAbsolute,
-						InsertedText:     req.InsertText,
+						InsertedText:     insertedText,
 						IsDir:            isDir,
 						Depth:            depth,
 					})
@@ -168,7 +187,7 @@ func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation,
 				Status:       status,
 			}
 			if status == StatusChanged {
-				entrySummary.InsertedText = req.InsertText
+				entrySummary.InsertedText = insertedText
 			}
 
 			summary.RecordEntry(entrySummary)
@@ -183,5 +202,19 @@ func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation,
 		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
 	})
 
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
 	return summary, operations, nil
 }
@@ -11,19 +11,8 @@ func ParseInputs(positionToken, insertText string, stemLength int) error {
 	if positionToken == "" {
 		return errors.New("position token cannot be empty")
 	}
-	if insertText == "" {
-		return errors.New("insert text cannot be empty")
-	}
-	if !utf8.ValidString(insertText) {
-		return errors.New("insert text must be valid UTF-8")
-	}
-	for _, r := range insertText {
-		if r == '/' || r == '\\' {
-			return errors.New("insert text must not contain path separators")
-		}
-		if r < 0x20 {
-			return errors.New("insert text must not contain control characters")
-		}
+	if err := validateInsertText(insertText); err != nil {
+		return err
 	}
 	if stemLength >= 0 {
 		if _, err := ResolvePosition(positionToken, stemLength); err != nil {
@@ -44,3 +33,21 @@ func ResolvePositionWithValidation(positionToken string, stemLength int) (Positi
 	}
 	return pos, nil
 }
+
+func validateInsertText(text string) error {
+	if text == "" {
+		return errors.New("insert text cannot be empty")
+	}
+	if !utf8.ValidString(text) {
+		return errors.New("insert text must be valid UTF-8")
+	}
+	for _, r := range text {
+		if r == '/' || r == '\\' {
+			return errors.New("insert text must not contain path separators")
+		}
+		if r < 0x20 {
+			return errors.New("insert text must not contain control characters")
+		}
+	}
+	return nil
+}
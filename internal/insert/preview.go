@@ -21,6 +21,19 @@ func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []Plan
 
 	summary.LedgerMetadata["positionToken"] = req.PositionToken
 	summary.LedgerMetadata["insertText"] = req.InsertText
+	if req.Tokens {
+		summary.LedgerMetadata["tokens"] = true
+	}
+	if len(req.Insertions) > 1 || req.Anchor != nil {
+		insertions := make([]map[string]any, 0, len(req.Insertions))
+		for _, insertion := range req.Insertions {
+			insertions = append(insertions, map[string]any{
+				"anchor": insertion.Anchor.String(),
+				"text":   insertion.Text,
+			})
+		}
+		summary.LedgerMetadata["insertions"] = insertions
+	}
 	scope := map[string]any{
 		"includeDirs":   req.IncludeDirs,
 		"recursive":     req.Recursive,
@@ -29,6 +42,9 @@ func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []Plan
 	if len(req.ExtensionFilter) > 0 {
 		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
 	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
 	summary.LedgerMetadata["scope"] = scope
 
 	if out != nil {
@@ -55,12 +71,20 @@ func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []Plan
 					reason = "skipped"
 				}
 				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			case StatusAnchorAbsent:
+				fmt.Fprintf(out, "%s (skipped: %s)\n", entry.OriginalPath, entry.Reason)
 			}
 		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
 
 		if summary.TotalCandidates > 0 {
-			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d already target position\n",
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d already target position",
 				summary.TotalCandidates, summary.TotalChanged, summary.NoChange)
+			if summary.AnchorAbsent > 0 {
+				fmt.Fprintf(out, ", %d missing anchor", summary.AnchorAbsent)
+			}
+			fmt.Fprintln(out)
 		} else {
 			fmt.Fprintln(out, "No candidates found.")
 		}
@@ -1,23 +1,40 @@
 package insert
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
 	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // Request encapsulates the inputs required to run an insert operation.
 type Request struct {
-	WorkingDir      string
-	PositionToken   string
-	InsertText      string
+	WorkingDir    string
+	PositionToken string
+	InsertText    string
+	// Tokens expands template tokens in the insert text instead of inserting it literally.
+	Tokens bool
+	// Anchor, when set, replaces PositionToken as the primary insertion point.
+	Anchor *Anchor
+	// Extra lists additional insertions; all are resolved against the original stem.
+	Extra []Insertion
+	// Insertions is the full ordered list built by Normalize.
+	Insertions      []Insertion
 	IncludeDirs     bool
 	Recursive       bool
 	IncludeHidden   bool
 	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
 	DryRun          bool
 	AutoConfirm     bool
 	Timestamp       time.Time
@@ -37,6 +54,10 @@ func NewRequest(scope *listing.ListingRequest) *Request {
 		Recursive:       scope.Recursive,
 		IncludeHidden:   scope.IncludeHidden,
 		ExtensionFilter: extensions,
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
 	}
 }
 
@@ -52,8 +73,37 @@ func (r *Request) SetPositionAndText(positionToken, insertText string) {
 	r.InsertText = insertText
 }
 
-// Normalize ensures working directory and timestamp fields are ready for execution.
+// SetTokens enables template token expansion in the primary insert text.
+func (r *Request) SetTokens(enabled bool) {
+	r.Tokens = enabled
+}
+
+// SetAnchor makes a content anchor the primary insertion point instead of a position token.
+func (r *Request) SetAnchor(anchor Anchor) {
+	r.Anchor = &anchor
+	r.PositionToken = anchor.String()
+}
+
+// AddInsertion appends an insertion evaluated alongside the primary one.
+func (r *Request) AddInsertion(insertion Insertion) {
+	r.Extra = append(r.Extra, insertion)
+}
+
+// Normalize ensures working directory, insertions, and timestamp fields are ready for execution.
 func (r *Request) Normalize() error {
+	if r.PositionToken == "" {
+		return errors.New("position token cannot be empty")
+	}
+	anchor := PositionAnchor(r.PositionToken)
+	if r.Anchor != nil {
+		anchor = *r.Anchor
+	}
+	primary, err := NewInsertion(anchor, r.InsertText, r.Tokens)
+	if err != nil {
+		return err
+	}
+	r.Insertions = append([]Insertion{primary}, r.Extra...)
+
 	if r.WorkingDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -1,5 +1,10 @@
 package insert
 
+import (
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
 // Status represents the preview outcome for a candidate entry.
 type Status string
 
@@ -7,6 +12,8 @@ const (
 	StatusChanged  Status = "changed"
 	StatusNoChange Status = "no_change"
 	StatusSkipped  Status = "skipped"
+	// StatusAnchorAbsent marks candidates left alone because a content anchor does not occur.
+	StatusAnchorAbsent Status = "anchor_absent"
 )
 
 // PreviewEntry describes a single original → proposed mapping.
@@ -15,6 +22,8 @@ type PreviewEntry struct {
 	ProposedPath string
 	Status       Status
 	InsertedText string
+	// Reason explains an anchor_absent entry.
+	Reason string
 }
 
 // Conflict captures a conflicting rename outcome.
@@ -29,11 +38,17 @@ type Summary struct {
 	TotalCandidates int
 	TotalChanged    int
 	NoChange        int
+	AnchorAbsent    int
 
 	Entries   []PreviewEntry
 	Conflicts []Conflict
 	Warnings  []string
 
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
+
 	LedgerMetadata map[string]any
 }
 
@@ -57,6 +72,8 @@ func (s *Summary) RecordEntry(entry PreviewEntry) {
 		s.TotalChanged++
 	case StatusNoChange:
 		s.NoChange++
+	case StatusAnchorAbsent:
+		s.AnchorAbsent++
 	}
 }
 
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -15,6 +17,13 @@ const (
 	flagIncludeDirs = "include-dirs"
 	flagHidden      = "hidden"
 	flagExtensions  = "extensions"
+	flagMatch       = "match"
+	flagNotMatch    = "not-match"
+	flagMatchTarget = "match-target"
+	flagCompoundExt = "compound-ext"
+	flagGroupSide   = "group-sidecars"
+	flagSidecarExt  = "sidecar-ext"
+	flagUpdateRefs  = "update-references"
 	flagYes         = "yes"
 	flagDryRun      = "dry-run"
 )
@@ -26,6 +35,13 @@ func RegisterScopeFlags(flags *pflag.FlagSet) {
 	flags.BoolP(flagIncludeDirs, "d", false, "Include directories in results")
 	flags.Bool(flagHidden, false, "Include hidden files and directories")
 	flags.StringP(flagExtensions, "e", "", "Pipe-delimited list of extensions to include (e.g. .jpg|.png)")
+	flags.String(flagMatch, "", "Only include entries whose name matches this regular expression")
+	flags.String(flagNotMatch, "", "Exclude entries whose name matches this regular expression")
+	flags.String(flagMatchTarget, string(filters.MatchTargetStem), "Name portion used by --match/--not-match: stem, basename, or path")
+	flags.String(flagCompoundExt, "", "Pipe-delimited multi-part extensions kept whole, added to .tar.gz, .d.ts, .min.js, ... (e.g. .tar.lz|.spec.ts)")
+	flags.Bool(flagGroupSide, false, "Rename companion files (IMG_0001.xmp, IMG_0001.JPG, movie.en.srt) together with the file sharing their stem")
+	flags.String(flagSidecarExt, "", "Pipe-delimited companion extensions for --group-sidecars, strongest first (default .jpg|.jpeg|.heic|.xmp|.dop|...|.srt|.nfo)")
+	flags.String(flagUpdateRefs, "", "Pipe-delimited globs of text files (e.g. *.md|*.html|*.m3u) whose relative references to renamed paths are rewritten in the same batch")
 	flags.Bool(flagYes, false, "Apply changes without interactive confirmation (mutating commands)")
 	flags.Bool(flagDryRun, false, "Force preview-only output without applying changes")
 }
@@ -69,12 +85,41 @@ func ScopeFromCmd(cmd *cobra.Command) (*ListingRequest, error) {
 		return nil, err
 	}
 
+	matchPattern, err := getStringFlag(cmd, flagMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	notMatchPattern, err := getStringFlag(cmd, flagNotMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	matchTarget, err := getStringFlag(cmd, flagMatchTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	compoundRaw, err := getStringFlag(cmd, flagCompoundExt)
+	if err != nil {
+		return nil, err
+	}
+
+	compound, err := filters.ParseExtensions(compoundRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", flagCompoundExt, err)
+	}
+
 	req := &ListingRequest{
 		WorkingDir:         path,
 		IncludeDirectories: includeDirs,
 		Recursive:          recursive,
 		IncludeHidden:      includeHidden,
 		Extensions:         extensions,
+		MatchPattern:       matchPattern,
+		NotMatchPattern:    notMatchPattern,
+		MatchTarget:        matchTarget,
+		CompoundExtensions: compound,
 		Format:             FormatTable,
 	}
 
@@ -82,6 +127,39 @@ func ScopeFromCmd(cmd *cobra.Command) (*ListingRequest, error) {
 		return nil, err
 	}
 
+	groupSidecars, err := getBoolFlag(cmd, flagGroupSide)
+	if err != nil {
+		return nil, err
+	}
+	sidecarRaw, err := getStringFlag(cmd, flagSidecarExt)
+	if err != nil {
+		return nil, err
+	}
+	if sidecarRaw != "" && !groupSidecars {
+		return nil, fmt.Errorf("--%s requires --%s", flagSidecarExt, flagGroupSide)
+	}
+	if groupSidecars {
+		rules, err := sidecar.ParseRules(sidecarRaw)
+		if err != nil {
+			return nil, err
+		}
+		if err := req.GroupSidecars(rules); err != nil {
+			return nil, err
+		}
+	}
+
+	refsRaw, err := getStringFlag(cmd, flagUpdateRefs)
+	if err != nil {
+		return nil, err
+	}
+	if refsRaw != "" {
+		patterns, err := references.ParsePatterns(refsRaw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", flagUpdateRefs, err)
+		}
+		req.References = references.New(req.WorkingDir, patterns, req.IncludeHidden)
+	}
+
 	return req, nil
 }
 
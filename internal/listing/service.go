@@ -93,12 +93,19 @@ func (s *Service) List(ctx context.Context, req *ListingRequest, formatter outpu
 				return nil
 			}
 
+			if !req.NameFilter.Allows(relPath, listingEntry.Type == EntryTypeDir) {
+				return nil
+			}
+
 			// Apply extension filtering to files only.
 			if listingEntry.Type == EntryTypeFile && len(extensions) > 0 {
-				ext := strings.ToLower(filepath.Ext(entry.Name()))
-				if _, match := extensions[ext]; !match {
+				if !req.ExtensionModel.Allows(entry.Name(), extensions) {
 					return nil
 				}
+				ext := strings.ToLower(req.ExtensionModel.Ext(entry.Name()))
+				if _, match := extensions[ext]; !match {
+					ext = strings.ToLower(filepath.Ext(entry.Name()))
+				}
 				listingEntry.MatchedExtension = ext
 			}
 
@@ -12,6 +12,10 @@ func EmptyResultMessage(req *ListingRequest) string {
 		return "No entries matched extensions: " + strings.Join(req.Extensions, ", ")
 	}
 
+	if req.NameFilter.Active() {
+		return "No entries matched the provided name filters."
+	}
+
 	if req.IncludeHidden {
 		return "No entries matched the provided filters (including hidden files)."
 	}
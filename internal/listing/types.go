@@ -5,6 +5,11 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 const (
@@ -21,6 +26,15 @@ type ListingRequest struct {
 	Recursive          bool
 	IncludeHidden      bool
 	Extensions         []string
+	MatchPattern       string
+	NotMatchPattern    string
+	MatchTarget        string
+	NameFilter         filters.NameFilter
+	CompoundExtensions []string
+	ExtensionModel     fileext.Model
+	Sidecars           *sidecar.Groups
+	SidecarRules       sidecar.Rules
+	References         *references.Rewriter
 	Format             string
 	MaxDepth           int
 }
@@ -93,5 +107,38 @@ func (r *ListingRequest) Validate() error {
 	}
 	r.Extensions = filtered
 
+	model, err := fileext.New(r.CompoundExtensions)
+	if err != nil {
+		return err
+	}
+	r.ExtensionModel = model
+
+	nameFilter, err := filters.ParseNameFilter(r.MatchPattern, r.NotMatchPattern, r.MatchTarget)
+	if err != nil {
+		return err
+	}
+	nameFilter.Extensions = model
+	r.NameFilter = nameFilter
+	r.MatchTarget = string(nameFilter.Target)
+
+	return nil
+}
+
+// GroupSidecars clusters companion files in the validated scope so engines rename each cluster
+// as one unit.
+func (r *ListingRequest) GroupSidecars(rules sidecar.Rules) error {
+	groups, err := sidecar.Build(sidecar.Scope{
+		WorkingDir:    r.WorkingDir,
+		Recursive:     r.Recursive,
+		IncludeHidden: r.IncludeHidden,
+		Extensions:    r.Extensions,
+		NameFilter:    r.NameFilter,
+		Model:         r.ExtensionModel,
+	}, rules)
+	if err != nil {
+		return err
+	}
+	r.Sidecars = groups
+	r.SidecarRules = rules
 	return nil
 }
@@ -0,0 +1,92 @@
+package metadata
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Tag field names shared by every audio format.
+const (
+	TagTitle       = "title"
+	TagArtist      = "artist"
+	TagAlbum       = "album"
+	TagAlbumArtist = "albumartist"
+	TagTrack       = "track"
+	TagDisc        = "disc"
+	TagYear        = "year"
+	TagGenre       = "genre"
+)
+
+// TagFields lists the normalised field names in display order.
+var TagFields = []string{TagArtist, TagAlbum, TagAlbumArtist, TagTitle, TagTrack, TagDisc, TagYear, TagGenre}
+
+// TagValue is a single tag and the native frame or field it was read from, e.g. "ID3v2 TPE1".
+type TagValue struct {
+	Value  string
+	Source string
+}
+
+// Tags maps normalised field names to values.
+type Tags map[string]TagValue
+
+func (t Tags) set(field, value, source string) {
+	value = strings.TrimSpace(strings.TrimRight(value, "\x00"))
+	if value == "" {
+		return
+	}
+	if _, exists := t[field]; exists {
+		return
+	}
+	if field == TagTrack || field == TagDisc {
+		// "3/12" -> "3"
+		if slash := strings.IndexByte(value, '/'); slash >= 0 {
+			value = strings.TrimSpace(value[:slash])
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			value = strconv.Itoa(n)
+		}
+	}
+	if field == TagYear && len(value) > 4 {
+		// ID3v2.4 TDRC and Vorbis DATE may carry full timestamps.
+		value = value[:4]
+	}
+	t[field] = TagValue{Value: value, Source: source}
+}
+
+// AudioTags reads ID3v2 (MP3), FLAC and Ogg Vorbis/Opus comments, or MP4/M4A ilst atoms from the
+// file at path. The boolean is false when the format is unrecognised or carries no tags.
+func AudioTags(path string) (Tags, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	if info.IsDir() {
+		return nil, false, nil
+	}
+
+	magic := make([]byte, 12)
+	n, _ := file.ReadAt(magic, 0)
+	magic = magic[:n]
+
+	tags := make(Tags)
+	switch {
+	case bytes.HasPrefix(magic, []byte("ID3")):
+		readID3v2(file, tags)
+	case bytes.HasPrefix(magic, []byte("fLaC")):
+		readFLAC(file, tags)
+	case bytes.HasPrefix(magic, []byte("OggS")):
+		readOgg(file, tags)
+	case len(magic) >= 8 && isISOBMFF(string(magic[4:8])):
+		readMP4Tags(file, info.Size(), tags)
+	}
+
+	return tags, len(tags) > 0, nil
+}
@@ -0,0 +1,97 @@
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Source identifies where a capture timestamp came from.
+type Source string
+
+const (
+	SourceEXIF      Source = "exif"
+	SourceQuickTime Source = "quicktime"
+	SourcePNG       Source = "png"
+	SourceModTime   Source = "mtime"
+)
+
+// EmbeddedTime returns the capture timestamp embedded in the file at path. The boolean is false
+// when the format is unrecognised or carries no timestamp; errors are reserved for I/O failures.
+func EmbeddedTime(path string) (time.Time, Source, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, "", false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return time.Time{}, "", false, err
+	}
+	if info.IsDir() {
+		return time.Time{}, "", false, nil
+	}
+	size := info.Size()
+
+	magic := make([]byte, 12)
+	n, _ := file.ReadAt(magic, 0)
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0xFF, 0xD8, 0xFF}):
+		if ts, ok := jpegCaptureTime(file); ok {
+			return ts, SourceEXIF, true, nil
+		}
+	case bytes.HasPrefix(magic, []byte("II*\x00")), bytes.HasPrefix(magic, []byte("MM\x00*")):
+		if ts, ok := exifCaptureTime(file); ok {
+			return ts, SourceEXIF, true, nil
+		}
+	case bytes.HasPrefix(magic, pngSignature[:8]):
+		if ts, ok := pngCaptureTime(file); ok {
+			return ts, SourcePNG, true, nil
+		}
+	case len(magic) >= 8 && isISOBMFF(string(magic[4:8])):
+		if ts, ok := heifCaptureTime(file, size); ok {
+			return ts, SourceEXIF, true, nil
+		}
+		if ts, ok := movieCaptureTime(file, size); ok {
+			return ts, SourceQuickTime, true, nil
+		}
+	}
+
+	return time.Time{}, "", false, nil
+}
+
+// CaptureTime returns the embedded capture timestamp, falling back to the modification time from
+// info (or a fresh stat when info is nil).
+func CaptureTime(path string, info fs.FileInfo) (time.Time, Source, error) {
+	ts, source, ok, err := EmbeddedTime(path)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("read media metadata for %s: %w", path, err)
+	}
+	if ok {
+		return ts, source, nil
+	}
+
+	if info == nil {
+		info, err = os.Stat(path)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+	}
+	return info.ModTime(), SourceModTime, nil
+}
+
+// isISOBMFF reports whether the first box type belongs to an ISO base media file (MP4, MOV,
+// HEIC). Older QuickTime files may start with moov, wide, mdat, or free instead of ftyp.
+func isISOBMFF(boxType string) bool {
+	switch boxType {
+	case "ftyp", "moov", "wide", "mdat", "free", "skip":
+		return true
+	default:
+		return false
+	}
+}
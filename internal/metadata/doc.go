@@ -0,0 +1,6 @@
+// Package metadata extracts embedded media attributes without cgo or external tools. It reads
+// capture timestamps from EXIF (JPEG, TIFF, HEIC), QuickTime/MP4 movie headers, and PNG text
+// chunks so rename engines can order and name files by when they were taken, and audio tags
+// from ID3v2, FLAC/Ogg Vorbis comments, and MP4 ilst atoms. DetectType identifies a file's real
+// format from its magic bytes, regardless of the name it carries.
+package metadata
@@ -0,0 +1,198 @@
+package metadata
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// Confidence grades how strongly a file's leading bytes identify its type.
+type Confidence string
+
+const (
+	// ConfidenceHigh marks a signature specific to one format (PNG, PDF, FLAC, ...).
+	ConfidenceHigh Confidence = "high"
+	// ConfidenceMedium marks a container shared by several formats (ZIP, Matroska, generic MP4).
+	ConfidenceMedium Confidence = "medium"
+	// ConfidenceLow marks a weak signature that ordinary data can match by chance.
+	ConfidenceLow Confidence = "low"
+)
+
+// FileType describes a format recognised from content. Extensions lists every extension that
+// correctly names the format, canonical spelling first; Aliases are alternative spellings of the
+// canonical extension (.jpeg for .jpg) that are worth normalizing.
+type FileType struct {
+	Name       string
+	MIME       string
+	Extensions []string
+	Aliases    []string
+	Confidence Confidence
+}
+
+// Canonical returns the preferred extension for the type, including the leading dot.
+func (t FileType) Canonical() string {
+	if len(t.Extensions) == 0 {
+		return ""
+	}
+	return t.Extensions[0]
+}
+
+// Accepts reports whether ext (with its dot, any case) is a correct extension for the type.
+func (t FileType) Accepts(ext string) bool {
+	return containsFold(t.Extensions, ext) || containsFold(t.Aliases, ext)
+}
+
+// IsAlias reports whether ext is an alternative spelling of the canonical extension.
+func (t FileType) IsAlias(ext string) bool {
+	return containsFold(t.Aliases, ext)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// KnownExtension reports whether ext (with its dot, any case) names a format this package knows,
+// which tells a wrong extension apart from a dotted word such as "report.final".
+func KnownExtension(ext string) bool {
+	_, ok := mimeByExtension[strings.ToLower(ext)]
+	return ok
+}
+
+// sniffLength covers the deepest fixed-offset signature (the tar magic at offset 257).
+const sniffLength = 512
+
+// DetectType identifies a file from its leading bytes, ignoring its name. The boolean is false
+// when no known signature matches; errors are reserved for I/O failures.
+func DetectType(path string) (FileType, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return FileType{}, false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return FileType{}, false, err
+	}
+	fileType, ok := sniffType(head[:n])
+	return fileType, ok, nil
+}
+
+func sniffType(head []byte) (FileType, bool) {
+	has := func(offset int, signature string) bool {
+		return len(head) >= offset+len(signature) && string(head[offset:offset+len(signature)]) == signature
+	}
+	high := func(name, mime string, extensions ...string) (FileType, bool) {
+		return FileType{Name: name, MIME: mime, Extensions: extensions, Confidence: ConfidenceHigh}, true
+	}
+	medium := func(name, mime string, extensions ...string) (FileType, bool) {
+		return FileType{Name: name, MIME: mime, Extensions: extensions, Confidence: ConfidenceMedium}, true
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return FileType{Name: "jpeg", MIME: "image/jpeg", Extensions: []string{".jpg"}, Aliases: []string{".jpeg", ".jpe", ".jfif"}, Confidence: ConfidenceHigh}, true
+	case bytes.HasPrefix(head, pngSignature):
+		return high("png", "image/png", ".png", ".apng")
+	case has(0, "GIF87a"), has(0, "GIF89a"):
+		return high("gif", "image/gif", ".gif")
+	case has(0, "RIFF") && has(8, "WEBP"):
+		return high("webp", "image/webp", ".webp")
+	case has(0, "RIFF") && has(8, "WAVE"):
+		return high("wav", "audio/wav", ".wav")
+	case has(0, "RIFF") && has(8, "AVI "):
+		return high("avi", "video/x-msvideo", ".avi")
+	case has(0, "II*\x00"), has(0, "MM\x00*"):
+		// Most camera raw formats are TIFF containers, so they count as correct names too.
+		return high("tiff", "image/tiff", ".tif", ".tiff", ".dng", ".cr2", ".nef", ".arw", ".orf", ".pef", ".rw2")
+	case has(0, "BM") && len(head) >= 14:
+		return medium("bmp", "image/bmp", ".bmp", ".dib")
+	case has(0, "8BPS"):
+		return high("psd", "image/vnd.adobe.photoshop", ".psd")
+	case has(0, "%PDF-"):
+		return high("pdf", "application/pdf", ".pdf", ".ai")
+	case has(0, "PK\x03\x04"), has(0, "PK\x05\x06"):
+		return sniffZip(head)
+	case bytes.HasPrefix(head, []byte{0x1F, 0x8B}):
+		return high("gzip", "application/gzip", ".gz", ".tgz")
+	case has(0, "BZh"):
+		return medium("bzip2", "application/x-bzip2", ".bz2", ".tbz2")
+	case bytes.HasPrefix(head, []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}):
+		return high("xz", "application/x-xz", ".xz", ".txz")
+	case bytes.HasPrefix(head, []byte{0x28, 0xB5, 0x2F, 0xFD}):
+		return high("zstd", "application/zstd", ".zst", ".tzst")
+	case bytes.HasPrefix(head, []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}):
+		return high("7z", "application/x-7z-compressed", ".7z")
+	case has(0, "Rar!\x1a\x07"):
+		return high("rar", "application/vnd.rar", ".rar")
+	case has(257, "ustar"):
+		return high("tar", "application/x-tar", ".tar")
+	case has(0, "SQLite format 3\x00"):
+		return high("sqlite", "application/vnd.sqlite3", ".sqlite", ".sqlite3", ".db")
+	case has(0, "fLaC"):
+		return high("flac", "audio/flac", ".flac")
+	case has(0, "OggS"):
+		return medium("ogg", "audio/ogg", ".ogg", ".oga", ".ogv", ".opus", ".spx")
+	case has(0, "ID3"):
+		return high("mp3", "audio/mpeg", ".mp3")
+	case bytes.HasPrefix(head, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		if bytes.Contains(head, []byte("webm")) {
+			return high("webm", "video/webm", ".webm")
+		}
+		return medium("matroska", "video/x-matroska", ".mkv", ".mka", ".mks", ".webm")
+	case len(head) >= 12 && has(4, "ftyp"):
+		return sniffISOBMFF(string(head[8:12]))
+	case len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0:
+		// An MPEG audio frame header without an ID3 tag; eleven set bits also occur in plain data.
+		return FileType{Name: "mp3", MIME: "audio/mpeg", Extensions: []string{".mp3"}, Confidence: ConfidenceLow}, true
+	}
+	return FileType{}, false
+}
+
+// sniffZip separates the ZIP-based document formats, which name their type in the first entry.
+func sniffZip(head []byte) (FileType, bool) {
+	switch {
+	case bytes.Contains(head, []byte("mimetypeapplication/epub+zip")):
+		return FileType{Name: "epub", MIME: "application/epub+zip", Extensions: []string{".epub"}, Confidence: ConfidenceHigh}, true
+	case bytes.Contains(head, []byte("mimetypeapplication/vnd.oasis.opendocument.text")):
+		return FileType{Name: "odt", MIME: "application/vnd.oasis.opendocument.text", Extensions: []string{".odt"}, Confidence: ConfidenceHigh}, true
+	}
+	return FileType{
+		Name: "zip",
+		MIME: "application/zip",
+		Extensions: []string{
+			".zip", ".docx", ".xlsx", ".pptx", ".odt", ".ods", ".odp", ".epub",
+			".jar", ".apk", ".cbz", ".whl", ".xpi", ".ipa", ".kmz",
+		},
+		Confidence: ConfidenceMedium,
+	}, true
+}
+
+// sniffISOBMFF maps the major brand of an ISO base media file to its format.
+func sniffISOBMFF(brand string) (FileType, bool) {
+	switch brand {
+	case "qt  ":
+		return FileType{Name: "quicktime", MIME: "video/quicktime", Extensions: []string{".mov"}, Aliases: []string{".qt"}, Confidence: ConfidenceHigh}, true
+	case "heic", "heix", "heim", "heis", "mif1", "msf1":
+		return FileType{Name: "heic", MIME: "image/heic", Extensions: []string{".heic", ".heif"}, Confidence: ConfidenceHigh}, true
+	case "avif", "avis":
+		return FileType{Name: "avif", MIME: "image/avif", Extensions: []string{".avif"}, Confidence: ConfidenceHigh}, true
+	case "M4A ", "M4B ":
+		return FileType{Name: "m4a", MIME: "audio/mp4", Extensions: []string{".m4a", ".m4b", ".mp4"}, Confidence: ConfidenceHigh}, true
+	case "crx ":
+		return FileType{Name: "cr3", MIME: "image/x-canon-cr3", Extensions: []string{".cr3"}, Confidence: ConfidenceHigh}, true
+	}
+	return FileType{
+		Name:       "mp4",
+		MIME:       "video/mp4",
+		Extensions: []string{".mp4", ".m4v", ".m4a", ".3gp", ".3g2", ".mov"},
+		Confidence: ConfidenceMedium,
+	}, true
+}
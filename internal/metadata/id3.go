@@ -0,0 +1,131 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+)
+
+// maxID3Size bounds the tag size read into memory.
+const maxID3Size = 16 << 20
+
+var id3Frames = map[string]string{
+	"TIT2": TagTitle, "TPE1": TagArtist, "TALB": TagAlbum, "TPE2": TagAlbumArtist,
+	"TRCK": TagTrack, "TPOS": TagDisc, "TYER": TagYear, "TDRC": TagYear, "TCON": TagGenre,
+	// ID3v2.2 three-character identifiers.
+	"TT2": TagTitle, "TP1": TagArtist, "TAL": TagAlbum, "TP2": TagAlbumArtist,
+	"TRK": TagTrack, "TPA": TagDisc, "TYE": TagYear, "TCO": TagGenre,
+}
+
+func synchsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// readID3v2 parses the text frames of an ID3v2.2, 2.3, or 2.4 tag at the start of the file.
+func readID3v2(r io.ReaderAt, tags Tags) {
+	header := make([]byte, 10)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return
+	}
+	version := header[3]
+	flags := header[5]
+	size := synchsafe(header[6:10])
+	if version < 2 || version > 4 || size <= 0 || size > maxID3Size {
+		return
+	}
+
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 10); err != nil && err != io.EOF {
+		return
+	}
+	if flags&0x80 != 0 && version < 4 {
+		// Tag-level unsynchronisation: 0xFF 0x00 sequences encode 0xFF.
+		data = bytes.ReplaceAll(data, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+
+	pos := 0
+	if flags&0x40 != 0 && version >= 3 && len(data) >= 4 {
+		// Skip the extended header.
+		if version == 4 {
+			pos = synchsafe(data[:4])
+		} else {
+			pos = int(binary.BigEndian.Uint32(data[:4])) + 4
+		}
+	}
+
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+	label := map[byte]string{2: "ID3v2.2", 3: "ID3v2.3", 4: "ID3v2.4"}[version]
+
+	for pos+headerLen <= len(data) {
+		id := string(data[pos : pos+idLen])
+		if id[0] == 0 {
+			break // padding
+		}
+		var frameSize int
+		switch version {
+		case 2:
+			frameSize = int(data[pos+3])<<16 | int(data[pos+4])<<8 | int(data[pos+5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		default:
+			frameSize = synchsafe(data[pos+4 : pos+8])
+		}
+		start := pos + headerLen
+		end := start + frameSize
+		if frameSize <= 0 || end > len(data) {
+			break
+		}
+		if field, ok := id3Frames[id]; ok {
+			tags.set(field, decodeID3Text(data[start:end]), label+" "+id)
+		}
+		pos = end
+	}
+}
+
+// decodeID3Text decodes a text frame body. Multiple values are separated by NUL; only the first is
+// kept.
+func decodeID3Text(body []byte) string {
+	if len(body) < 2 {
+		return ""
+	}
+	encoding, text := body[0], body[1:]
+	switch encoding {
+	case 0: // ISO-8859-1
+		runes := make([]rune, 0, len(text))
+		for _, b := range text {
+			if b == 0 {
+				break
+			}
+			runes = append(runes, rune(b))
+		}
+		return string(runes)
+	case 1, 2: // UTF-16 with BOM, UTF-16BE
+		var order binary.ByteOrder = binary.BigEndian
+		if encoding == 1 && len(text) >= 2 {
+			if text[0] == 0xFF && text[1] == 0xFE {
+				order = binary.LittleEndian
+			}
+			if (text[0] == 0xFF && text[1] == 0xFE) || (text[0] == 0xFE && text[1] == 0xFF) {
+				text = text[2:]
+			}
+		}
+		units := make([]uint16, 0, len(text)/2)
+		for i := 0; i+1 < len(text); i += 2 {
+			unit := order.Uint16(text[i : i+2])
+			if unit == 0 {
+				break
+			}
+			units = append(units, unit)
+		}
+		return string(utf16.Decode(units))
+	default: // UTF-8
+		if nul := bytes.IndexByte(text, 0); nul >= 0 {
+			text = text[:nul]
+		}
+		return string(text)
+	}
+}
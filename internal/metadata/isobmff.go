@@ -0,0 +1,231 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// quickTimeEpochOffset is the number of seconds between 1904-01-01 and 1970-01-01.
+const quickTimeEpochOffset = 2082844800
+
+// maxBoxPayload bounds the iinf/iloc payloads read into memory.
+const maxBoxPayload = 1 << 20
+
+type box struct {
+	typ    string
+	offset int64 // payload start
+	size   int64 // payload size
+}
+
+// findBox scans sibling boxes in [start, end) for the first box of the given type.
+func findBox(r io.ReaderAt, start, end int64, typ string) (box, bool) {
+	header := make([]byte, 16)
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return box{}, false
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		name := string(header[4:8])
+		headerLen := int64(8)
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return box{}, false
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerLen = 16
+		}
+		if size < headerLen || offset+size > end {
+			return box{}, false
+		}
+		if name == typ {
+			return box{typ: name, offset: offset + headerLen, size: size - headerLen}, true
+		}
+		offset += size
+	}
+	return box{}, false
+}
+
+// movieCaptureTime reads the creation time from moov/mvhd. A zero creation time means the
+// encoder did not record one.
+func movieCaptureTime(r io.ReaderAt, size int64) (time.Time, bool) {
+	moov, ok := findBox(r, 0, size, "moov")
+	if !ok {
+		return time.Time{}, false
+	}
+	mvhd, ok := findBox(r, moov.offset, moov.offset+moov.size, "mvhd")
+	if !ok || mvhd.size < 12 {
+		return time.Time{}, false
+	}
+
+	buf := make([]byte, 12)
+	if _, err := r.ReadAt(buf, mvhd.offset); err != nil {
+		return time.Time{}, false
+	}
+	var seconds uint64
+	if buf[0] == 1 {
+		seconds = binary.BigEndian.Uint64(buf[4:12])
+	} else {
+		seconds = uint64(binary.BigEndian.Uint32(buf[4:8]))
+	}
+	if seconds <= quickTimeEpochOffset {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds-quickTimeEpochOffset), 0).UTC(), true
+}
+
+// heifCaptureTime locates the "Exif" item of a HEIF/HEIC file through meta/iinf and meta/iloc
+// and parses its TIFF payload.
+func heifCaptureTime(r io.ReaderAt, size int64) (time.Time, bool) {
+	meta, ok := findBox(r, 0, size, "meta")
+	if !ok || meta.size < 4 {
+		return time.Time{}, false
+	}
+	childStart, childEnd := meta.offset+4, meta.offset+meta.size
+
+	iinf, ok := readPayload(r, childStart, childEnd, "iinf")
+	if !ok {
+		return time.Time{}, false
+	}
+	itemID, ok := exifItemID(iinf)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	iloc, ok := readPayload(r, childStart, childEnd, "iloc")
+	if !ok {
+		return time.Time{}, false
+	}
+	start, length, ok := itemLocation(iloc, itemID)
+	if !ok || length < 8 {
+		return time.Time{}, false
+	}
+
+	prefix := make([]byte, 4)
+	if _, err := r.ReadAt(prefix, start); err != nil {
+		return time.Time{}, false
+	}
+	tiffOffset := int64(binary.BigEndian.Uint32(prefix))
+	if 4+tiffOffset >= length {
+		return time.Time{}, false
+	}
+	return exifCaptureTime(io.NewSectionReader(r, start+4+tiffOffset, length-4-tiffOffset))
+}
+
+func readPayload(r io.ReaderAt, start, end int64, typ string) ([]byte, bool) {
+	b, ok := findBox(r, start, end, typ)
+	if !ok || b.size > maxBoxPayload {
+		return nil, false
+	}
+	data := make([]byte, b.size)
+	if _, err := r.ReadAt(data, b.offset); err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// cursor reads big-endian integers from an in-memory box payload.
+type cursor struct {
+	data []byte
+	pos  int
+	bad  bool
+}
+
+func (c *cursor) uint(size int) uint64 {
+	if c.bad || c.pos+size > len(c.data) {
+		c.bad = true
+		return 0
+	}
+	var value uint64
+	for i := 0; i < size; i++ {
+		value = value<<8 | uint64(c.data[c.pos+i])
+	}
+	c.pos += size
+	return value
+}
+
+func exifItemID(iinf []byte) (uint32, bool) {
+	c := &cursor{data: iinf}
+	version := c.uint(1)
+	c.uint(3)
+	countSize := 2
+	if version > 0 {
+		countSize = 4
+	}
+	count := int(c.uint(countSize))
+
+	for i := 0; i < count && !c.bad; i++ {
+		boxStart := c.pos
+		boxSize := int(c.uint(4))
+		if c.uint(4) != 0x696e6665 || boxSize < 8 { // "infe"
+			return 0, false
+		}
+		infeVersion := c.uint(1)
+		c.uint(3)
+		if infeVersion >= 2 {
+			idSize := 2
+			if infeVersion == 3 {
+				idSize = 4
+			}
+			id := uint32(c.uint(idSize))
+			c.uint(2) // item_protection_index
+			itemType := c.uint(4)
+			if !c.bad && itemType == 0x45786966 { // "Exif"
+				return id, true
+			}
+		}
+		c.pos = boxStart + boxSize
+	}
+	return 0, false
+}
+
+func itemLocation(iloc []byte, itemID uint32) (int64, int64, bool) {
+	c := &cursor{data: iloc}
+	version := c.uint(1)
+	c.uint(3)
+	sizes := c.uint(1)
+	offsetSize, lengthSize := int(sizes>>4), int(sizes&0x0f)
+	sizes = c.uint(1)
+	baseOffsetSize, indexSize := int(sizes>>4), int(sizes&0x0f)
+	if version == 0 {
+		indexSize = 0
+	}
+
+	idSize, countSize := 2, 2
+	if version == 2 {
+		idSize, countSize = 4, 4
+	}
+	count := int(c.uint(countSize))
+
+	for i := 0; i < count && !c.bad; i++ {
+		id := uint32(c.uint(idSize))
+		constructionMethod := uint64(0)
+		if version == 1 || version == 2 {
+			constructionMethod = c.uint(2) & 0x0f
+		}
+		c.uint(2) // data_reference_index
+		base := int64(c.uint(baseOffsetSize))
+		extents := int(c.uint(2))
+
+		var first struct{ offset, length int64 }
+		for e := 0; e < extents && !c.bad; e++ {
+			c.uint(indexSize)
+			offset := int64(c.uint(offsetSize))
+			length := int64(c.uint(lengthSize))
+			if e == 0 {
+				first.offset, first.length = offset, length
+			}
+		}
+
+		if id == itemID && !c.bad {
+			if constructionMethod != 0 || extents == 0 {
+				return 0, 0, false
+			}
+			return base + first.offset, first.length, true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+const (
+	jpegMarkerAPP1 = 0xE1
+	jpegMarkerSOS  = 0xDA
+	jpegMarkerEOI  = 0xD9
+)
+
+// jpegCaptureTime walks the JPEG marker segments up to the start of scan and reads the EXIF
+// payload of the first APP1 "Exif" segment.
+func jpegCaptureTime(r io.ReaderAt) (time.Time, bool) {
+	offset := int64(2)
+	header := make([]byte, 4)
+	for {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return time.Time{}, false
+		}
+		if header[0] != 0xFF {
+			return time.Time{}, false
+		}
+		marker := header[1]
+		if marker == 0xFF {
+			// Fill bytes may precede a marker.
+			offset++
+			continue
+		}
+		if marker == jpegMarkerSOS || marker == jpegMarkerEOI {
+			return time.Time{}, false
+		}
+		length := int64(binary.BigEndian.Uint16(header[2:4]))
+		if length < 2 {
+			return time.Time{}, false
+		}
+
+		if marker == jpegMarkerAPP1 && length > 8 {
+			ident := make([]byte, 6)
+			if _, err := r.ReadAt(ident, offset+4); err == nil && string(ident) == "Exif\x00\x00" {
+				return exifCaptureTime(io.NewSectionReader(r, offset+10, length-8))
+			}
+		}
+
+		offset += 2 + length
+	}
+}
@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// octetStream is reported when neither the extension nor the content identifies a type.
+const octetStream = "application/octet-stream"
+
+// mimeByExtension is a fixed table so results do not depend on the host's mime.types files.
+var mimeByExtension = map[string]string{
+	".jpg": "image/jpeg", ".jpeg": "image/jpeg", ".png": "image/png", ".gif": "image/gif",
+	".webp": "image/webp", ".heic": "image/heic", ".heif": "image/heif", ".avif": "image/avif",
+	".tif": "image/tiff", ".tiff": "image/tiff", ".bmp": "image/bmp", ".svg": "image/svg+xml",
+	".dng": "image/x-adobe-dng", ".cr2": "image/x-canon-cr2", ".nef": "image/x-nikon-nef",
+	".mp4": "video/mp4", ".m4v": "video/mp4", ".mov": "video/quicktime", ".mkv": "video/x-matroska",
+	".webm": "video/webm", ".avi": "video/x-msvideo",
+	".mp3": "audio/mpeg", ".flac": "audio/flac", ".ogg": "audio/ogg", ".opus": "audio/opus",
+	".m4a": "audio/mp4", ".wav": "audio/wav", ".aac": "audio/aac",
+	".txt": "text/plain", ".md": "text/markdown", ".csv": "text/csv", ".html": "text/html",
+	".htm": "text/html", ".css": "text/css", ".xml": "text/xml",
+	".json": "application/json", ".pdf": "application/pdf", ".zip": "application/zip",
+	".gz": "application/gzip", ".tar": "application/x-tar", ".js": "text/javascript",
+	".bz2": "application/x-bzip2", ".xz": "application/x-xz", ".zst": "application/zstd",
+	".7z": "application/x-7z-compressed", ".rar": "application/vnd.rar",
+	".psd": "image/vnd.adobe.photoshop", ".sqlite": "application/vnd.sqlite3",
+	".doc": "application/msword", ".epub": "application/epub+zip",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// MIMEType identifies a file from its extension, falling back to sniffing the first 512 bytes.
+// Parameters such as "; charset=utf-8" are dropped.
+func MIMEType(path string) (string, error) {
+	if known, ok := mimeByExtension[strings.ToLower(filepath.Ext(path))]; ok {
+		return known, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, _ := file.Read(head)
+	if n == 0 {
+		return octetStream, nil
+	}
+	detected := http.DetectContentType(head[:n])
+	if semi := strings.IndexByte(detected, ';'); semi >= 0 {
+		detected = detected[:semi]
+	}
+	return strings.TrimSpace(detected), nil
+}
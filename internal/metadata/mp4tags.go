@@ -0,0 +1,84 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// mp4Atoms maps iTunes-style ilst atom names to tag fields. "\xa9" is the © byte used by the
+// classic QuickTime text atoms.
+var mp4Atoms = map[string]string{
+	"\xa9nam": TagTitle, "\xa9ART": TagArtist, "\xa9alb": TagAlbum, "aART": TagAlbumArtist,
+	"trkn": TagTrack, "disk": TagDisc, "\xa9day": TagYear, "\xa9gen": TagGenre,
+}
+
+// readMP4Tags walks moov/udta/meta/ilst and decodes the data box of each known atom.
+func readMP4Tags(r io.ReaderAt, size int64, tags Tags) {
+	moov, ok := findBox(r, 0, size, "moov")
+	if !ok {
+		return
+	}
+	udta, ok := findBox(r, moov.offset, moov.offset+moov.size, "udta")
+	if !ok {
+		return
+	}
+	meta, ok := findBox(r, udta.offset, udta.offset+udta.size, "meta")
+	if !ok || meta.size < 4 {
+		return
+	}
+	// meta is a full box; some writers omit the version/flags word, so try both layouts.
+	ilst, ok := findBox(r, meta.offset+4, meta.offset+meta.size, "ilst")
+	if !ok {
+		if ilst, ok = findBox(r, meta.offset, meta.offset+meta.size, "ilst"); !ok {
+			return
+		}
+	}
+
+	header := make([]byte, 8)
+	end := ilst.offset + ilst.size
+	for offset := ilst.offset; offset+8 <= end; {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return
+		}
+		atomSize := int64(binary.BigEndian.Uint32(header[:4]))
+		name := string(header[4:8])
+		if atomSize < 8 || offset+atomSize > end {
+			return
+		}
+		if field, known := mp4Atoms[name]; known {
+			if value, ok := mp4AtomValue(r, offset+8, offset+atomSize, name); ok {
+				tags.set(field, value, "MP4 "+printableAtom(name))
+			}
+		}
+		offset += atomSize
+	}
+}
+
+func mp4AtomValue(r io.ReaderAt, start, end int64, name string) (string, bool) {
+	data, ok := findBox(r, start, end, "data")
+	if !ok || data.size < 8 || data.size > maxBoxPayload {
+		return "", false
+	}
+	payload := make([]byte, data.size)
+	if _, err := r.ReadAt(payload, data.offset); err != nil {
+		return "", false
+	}
+	// payload: type indicator (4), locale (4), value.
+	value := payload[8:]
+	if name == "trkn" || name == "disk" {
+		if len(value) < 4 {
+			return "", false
+		}
+		number := binary.BigEndian.Uint16(value[2:4])
+		return strconv.Itoa(int(number)), number > 0
+	}
+	return string(value), true
+}
+
+func printableAtom(name string) string {
+	if len(name) > 0 && name[0] == 0xa9 {
+		return "©" + name[1:]
+	}
+	return name
+}
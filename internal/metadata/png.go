@@ -0,0 +1,80 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngTextKeywords lists tEXt keywords that carry a creation timestamp, in preference order.
+var pngTextKeywords = []string{"Creation Time", "date:create", "CreationTime"}
+
+var pngTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"2006:01:02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"02 Jan 2006 15:04:05 -0700",
+}
+
+// maxPNGTextChunk bounds the tEXt chunk size read into memory.
+const maxPNGTextChunk = 64 * 1024
+
+// pngCaptureTime reads an eXIf chunk when present, otherwise the first recognised tEXt timestamp.
+func pngCaptureTime(r io.ReaderAt) (time.Time, bool) {
+	offset := int64(len(pngSignature))
+	header := make([]byte, 8)
+	texts := make(map[string]string)
+
+	for {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			break
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		chunkType := string(header[4:8])
+		dataOffset := offset + 8
+
+		switch chunkType {
+		case "eXIf":
+			if ts, ok := exifCaptureTime(io.NewSectionReader(r, dataOffset, length)); ok {
+				return ts, true
+			}
+		case "tEXt":
+			if length <= maxPNGTextChunk {
+				data := make([]byte, length)
+				if _, err := r.ReadAt(data, dataOffset); err == nil {
+					if sep := bytes.IndexByte(data, 0); sep > 0 {
+						texts[string(data[:sep])] = strings.TrimSpace(string(data[sep+1:]))
+					}
+				}
+			}
+		case "IEND":
+			return pngTextTime(texts)
+		}
+
+		offset = dataOffset + length + 4 // skip CRC
+	}
+
+	return pngTextTime(texts)
+}
+
+func pngTextTime(texts map[string]string) (time.Time, bool) {
+	for _, keyword := range pngTextKeywords {
+		value, ok := texts[keyword]
+		if !ok {
+			continue
+		}
+		for _, layout := range pngTimeLayouts {
+			if ts, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return ts, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,162 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	tagDateTime          = 0x0132
+	tagExifIFD           = 0x8769
+	tagDateTimeOriginal  = 0x9003
+	tagDateTimeDigitized = 0x9004
+	tagOffsetTime        = 0x9010
+	tagOffsetOriginal    = 0x9011
+	tagOffsetDigitized   = 0x9012
+
+	tiffTypeASCII = 2
+	tiffTypeLong  = 4
+
+	// maxIFDEntries guards against corrupt directories claiming absurd entry counts.
+	maxIFDEntries = 1024
+)
+
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value [4]byte
+}
+
+type tiffReader struct {
+	r     io.ReaderAt
+	order binary.ByteOrder
+}
+
+// exifCaptureTime reads the capture timestamp from a TIFF structure, preferring
+// DateTimeOriginal, then DateTimeDigitized, then the IFD0 DateTime.
+func exifCaptureTime(r io.ReaderAt) (time.Time, bool) {
+	header := make([]byte, 8)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return time.Time{}, false
+	}
+
+	tr := tiffReader{r: r}
+	switch string(header[:2]) {
+	case "II":
+		tr.order = binary.LittleEndian
+	case "MM":
+		tr.order = binary.BigEndian
+	default:
+		return time.Time{}, false
+	}
+	if tr.order.Uint16(header[2:4]) != 42 {
+		return time.Time{}, false
+	}
+
+	ifd0, ok := tr.readIFD(int64(tr.order.Uint32(header[4:8])))
+	if !ok {
+		return time.Time{}, false
+	}
+
+	var exif map[uint16]ifdEntry
+	if pointer, ok := ifd0[tagExifIFD]; ok && pointer.typ == tiffTypeLong {
+		exif, _ = tr.readIFD(int64(tr.order.Uint32(pointer.value[:])))
+	}
+
+	lookups := []struct {
+		ifd        map[uint16]ifdEntry
+		date, zone uint16
+	}{
+		{exif, tagDateTimeOriginal, tagOffsetOriginal},
+		{exif, tagDateTimeDigitized, tagOffsetDigitized},
+		{ifd0, tagDateTime, tagOffsetTime},
+	}
+	for _, lookup := range lookups {
+		if lookup.ifd == nil {
+			continue
+		}
+		entry, ok := lookup.ifd[lookup.date]
+		if !ok {
+			continue
+		}
+		raw, ok := tr.ascii(entry)
+		if !ok {
+			continue
+		}
+		offset := ""
+		if zoneEntry, ok := exif[lookup.zone]; ok {
+			offset, _ = tr.ascii(zoneEntry)
+		}
+		if ts, ok := parseExifDate(raw, offset); ok {
+			return ts, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (t tiffReader) readIFD(offset int64) (map[uint16]ifdEntry, bool) {
+	countBuf := make([]byte, 2)
+	if _, err := t.r.ReadAt(countBuf, offset); err != nil {
+		return nil, false
+	}
+	count := int(t.order.Uint16(countBuf))
+	if count == 0 || count > maxIFDEntries {
+		return nil, false
+	}
+
+	data := make([]byte, count*12)
+	if _, err := t.r.ReadAt(data, offset+2); err != nil {
+		return nil, false
+	}
+
+	entries := make(map[uint16]ifdEntry, count)
+	for i := 0; i < count; i++ {
+		raw := data[i*12 : i*12+12]
+		entry := ifdEntry{
+			typ:   t.order.Uint16(raw[2:4]),
+			count: t.order.Uint32(raw[4:8]),
+		}
+		copy(entry.value[:], raw[8:12])
+		entries[t.order.Uint16(raw[0:2])] = entry
+	}
+	return entries, true
+}
+
+func (t tiffReader) ascii(entry ifdEntry) (string, bool) {
+	if entry.typ != tiffTypeASCII || entry.count == 0 || entry.count > 256 {
+		return "", false
+	}
+	if entry.count <= 4 {
+		return trimASCII(entry.value[:entry.count]), true
+	}
+	buf := make([]byte, entry.count)
+	if _, err := t.r.ReadAt(buf, int64(t.order.Uint32(entry.value[:]))); err != nil {
+		return "", false
+	}
+	return trimASCII(buf), true
+}
+
+func trimASCII(raw []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(raw), "\x00"))
+}
+
+// parseExifDate parses "2006:01:02 15:04:05" with an optional "+07:00" offset. EXIF timestamps
+// without an offset are wall-clock times of the camera and are interpreted in the local zone.
+func parseExifDate(raw, offset string) (time.Time, bool) {
+	if raw == "" || strings.HasPrefix(raw, "0000") {
+		return time.Time{}, false
+	}
+	if offset != "" {
+		if ts, err := time.Parse("2006:01:02 15:04:05-07:00", raw+offset); err == nil {
+			return ts, true
+		}
+	}
+	ts, err := time.ParseInLocation("2006:01:02 15:04:05", raw, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
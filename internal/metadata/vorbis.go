@@ -0,0 +1,163 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+const (
+	flacBlockVorbisComment = 4
+	// maxCommentBlock bounds the comment payload read into memory.
+	maxCommentBlock = 16 << 20
+	// maxOggPages bounds how far into an Ogg stream the comment header is searched for.
+	maxOggPages = 64
+)
+
+var vorbisFields = map[string]string{
+	"TITLE": TagTitle, "ARTIST": TagArtist, "ALBUM": TagAlbum, "ALBUMARTIST": TagAlbumArtist,
+	"ALBUM ARTIST": TagAlbumArtist, "TRACKNUMBER": TagTrack, "DISCNUMBER": TagDisc,
+	"DATE": TagYear, "YEAR": TagYear, "GENRE": TagGenre,
+}
+
+// readFLAC walks the FLAC metadata blocks looking for VORBIS_COMMENT.
+func readFLAC(r io.ReaderAt, tags Tags) {
+	offset := int64(4)
+	header := make([]byte, 4)
+	for {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7f
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		if blockType == flacBlockVorbisComment && length <= maxCommentBlock {
+			data := make([]byte, length)
+			if _, err := r.ReadAt(data, offset+4); err == nil {
+				parseVorbisComments(data, "FLAC", tags)
+			}
+			return
+		}
+		if last {
+			return
+		}
+		offset += 4 + length
+	}
+}
+
+// readOgg reassembles the second logical packet (the comment header) of an Ogg Vorbis or Opus
+// stream.
+func readOgg(r io.ReaderAt, tags Tags) {
+	offset := int64(0)
+	packets := make([][]byte, 0, 2)
+	var current []byte
+	header := make([]byte, 27)
+
+	for page := 0; page < maxOggPages && len(packets) < 2; page++ {
+		if _, err := r.ReadAt(header, offset); err != nil || string(header[:4]) != "OggS" {
+			return
+		}
+		segmentCount := int(header[26])
+		table := make([]byte, segmentCount)
+		if _, err := r.ReadAt(table, offset+27); err != nil {
+			return
+		}
+		dataOffset := offset + 27 + int64(segmentCount)
+		for _, segment := range table {
+			chunk := make([]byte, segment)
+			if _, err := r.ReadAt(chunk, dataOffset); err != nil {
+				return
+			}
+			dataOffset += int64(segment)
+			current = append(current, chunk...)
+			if len(current) > maxCommentBlock {
+				return
+			}
+			if segment < 255 {
+				packets = append(packets, current)
+				current = nil
+				if len(packets) == 2 {
+					break
+				}
+			}
+		}
+		offset = dataOffset
+	}
+	if len(packets) < 2 {
+		return
+	}
+
+	comment := packets[1]
+	switch {
+	case bytes.HasPrefix(comment, []byte("\x03vorbis")):
+		parseVorbisComments(comment[7:], "Vorbis", tags)
+	case bytes.HasPrefix(comment, []byte("OpusTags")):
+		parseVorbisComments(comment[8:], "Opus", tags)
+	}
+}
+
+// parseVorbisComments decodes the vendor string and KEY=value list shared by Vorbis, Opus, and
+// FLAC. Parsing stops at the first length that runs past the end of the block, keeping the
+// comments decoded so far.
+func parseVorbisComments(data []byte, label string, tags Tags) {
+	c := &leCursor{data: data}
+	vendorLen := int64(c.uint32())
+	if c.bad || vendorLen > c.remaining() {
+		return
+	}
+	c.skip(int(vendorLen))
+	count := int64(c.uint32())
+	for i := int64(0); i < count && !c.bad; i++ {
+		length := int64(c.uint32())
+		if c.bad || length > c.remaining() {
+			return
+		}
+		field := c.bytes(int(length))
+		if c.bad {
+			return
+		}
+		key, value, ok := strings.Cut(string(field), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(key)
+		if name, known := vorbisFields[key]; known {
+			tags.set(name, value, label+" "+key)
+		}
+	}
+}
+
+// leCursor reads little-endian fields from an in-memory buffer.
+type leCursor struct {
+	data []byte
+	pos  int
+	bad  bool
+}
+
+func (c *leCursor) uint32() uint32 {
+	b := c.bytes(4)
+	if c.bad {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (c *leCursor) bytes(n int) []byte {
+	if c.bad || n < 0 || c.pos+n > len(c.data) {
+		c.bad = true
+		return nil
+	}
+	b := c.data[c.pos : c.pos+n]
+	c.pos += n
+	return b
+}
+
+// remaining reports how many unread bytes are left.
+func (c *leCursor) remaining() int64 {
+	return int64(len(c.data) - c.pos)
+}
+
+func (c *leCursor) skip(n int) {
+	c.bytes(n)
+}
@@ -0,0 +1,55 @@
+package normalize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// conflictDetector tracks proposed targets. Keys are folded to NFC and lower case so two sources
+// that normalize to the same name are caught even on normalization- or case-insensitive filesystems.
+type conflictDetector struct {
+	planned map[string]string
+}
+
+func newConflictDetector() *conflictDetector {
+	return &conflictDetector{planned: make(map[string]string)}
+}
+
+func foldKey(relative string) string {
+	return strings.ToLower(norm.NFC.String(relative))
+}
+
+// reserve records a path that keeps its current name so later targets cannot claim it.
+func (d *conflictDetector) reserve(relative string) {
+	d.planned[foldKey(relative)] = relative
+}
+
+// evaluate returns an empty reason when the rename may proceed, or a conflict reason otherwise.
+func (d *conflictDetector) evaluate(candidateRel, targetRel, originalAbs, targetAbs string) (string, error) {
+	if existing, ok := d.planned[foldKey(targetRel)]; ok && existing != candidateRel {
+		return fmt.Sprintf("duplicate_target with %s", existing), nil
+	}
+
+	if info, err := os.Stat(targetAbs); err == nil {
+		origInfo, origErr := os.Stat(originalAbs)
+		if origErr != nil {
+			return "", origErr
+		}
+		// Normalization-insensitive filesystems resolve the target to the source itself.
+		if !os.SameFile(info, origInfo) {
+			if info.IsDir() {
+				return "existing_directory", nil
+			}
+			return "existing_file", nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	d.planned[foldKey(targetRel)] = candidateRel
+	return "", nil
+}
@@ -0,0 +1,3 @@
+// Package normalize implements the `renamer normalize` engine, rewriting names into a chosen
+// Unicode normalization form and optionally transliterating them to ASCII.
+package normalize
@@ -0,0 +1,175 @@
+package normalize
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation captures a filesystem rename to be applied.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	IsDir            bool
+	Depth            int
+}
+
+// BuildPlan enumerates candidates, normalizes their names, and prepares filesystem operations.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("normalize request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	detector := newConflictDetector()
+
+	var transliterator *Transliterator
+	if req.ASCII {
+		transliterator = NewTransliterator(req.Mappings, req.Fallback)
+	}
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) || (!isDir && req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			proposedName := req.Form.Apply(name)
+			if transliterator != nil {
+				proposedName = transliterator.ToASCII(proposedName)
+			}
+
+			proposedRelative := joinRelative(relative, proposedName)
+			originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(relative))
+			proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative))
+
+			preview := PreviewEntry{
+				OriginalPath: relative,
+				ProposedPath: proposedRelative,
+				Status:       StatusChanged,
+				Invisible:    proposedName != name && VisuallyEqual(proposedName, name),
+			}
+
+			switch {
+			case proposedName == name:
+				preview.Status = StatusNoChange
+				detector.reserve(relative)
+			case strings.Trim(proposedName, ".") == "":
+				summary.AddConflict(Conflict{
+					OriginalPath: relative,
+					ProposedPath: proposedRelative,
+					Reason:       "empty_name",
+				})
+				preview.Status = StatusSkipped
+			default:
+				reason, err := detector.evaluate(relative, proposedRelative, originalAbsolute, proposedAbsolute)
+				if err != nil {
+					return err
+				}
+				if reason == "" {
+					if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+						reason = violation.Reason()
+					}
+				}
+				if reason != "" {
+					summary.AddConflict(Conflict{
+						OriginalPath: relative,
+						ProposedPath: proposedRelative,
+						Reason:       reason,
+					})
+					preview.Status = StatusSkipped
+					break
+				}
+				operations = append(operations, PlannedOperation{
+					OriginalRelative: relative,
+					OriginalAbsolute: originalAbsolute,
+					ProposedRelative: proposedRelative,
+					ProposedAbsolute: proposedAbsolute,
+					IsDir:            isDir,
+					Depth:            depth,
+				})
+			}
+
+			summary.RecordEntry(preview)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
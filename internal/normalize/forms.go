@@ -0,0 +1,54 @@
+package normalize
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Form identifies a Unicode normalization form.
+type Form string
+
+const (
+	FormNFC  Form = "nfc"
+	FormNFD  Form = "nfd"
+	FormNFKC Form = "nfkc"
+	FormNFKD Form = "nfkd"
+)
+
+// ParseForm validates the --form token.
+func ParseForm(value string) (Form, error) {
+	switch Form(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FormNFC:
+		return FormNFC, nil
+	case FormNFD:
+		return FormNFD, nil
+	case FormNFKC:
+		return FormNFKC, nil
+	case FormNFKD:
+		return FormNFKD, nil
+	default:
+		return "", fmt.Errorf("unsupported normalization form %q (use nfc, nfd, nfkc, or nfkd)", value)
+	}
+}
+
+// Apply normalizes value into the form.
+func (f Form) Apply(value string) string {
+	switch f {
+	case FormNFD:
+		return norm.NFD.String(value)
+	case FormNFKC:
+		return norm.NFKC.String(value)
+	case FormNFKD:
+		return norm.NFKD.String(value)
+	default:
+		return norm.NFC.String(value)
+	}
+}
+
+// VisuallyEqual reports whether a and b are canonically equivalent, meaning they render the same
+// but may differ in their byte representation (for example NFD names copied from macOS).
+func VisuallyEqual(a, b string) bool {
+	return norm.NFC.String(a) == norm.NFC.String(b)
+}
@@ -0,0 +1,89 @@
+package normalize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Preview computes planned normalization updates, renders preview output, and returns the summary.
+func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("normalize request cannot be nil")
+	}
+
+	summary, operations, err := BuildPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary.LedgerMetadata["form"] = string(req.Form)
+	summary.LedgerMetadata["ascii"] = req.ASCII
+	if req.ASCII {
+		summary.LedgerMetadata["fallback"] = req.Fallback
+		if len(req.Mappings) > 0 {
+			mappings := make(map[string]string, len(req.Mappings))
+			for from, to := range req.Mappings {
+				mappings[from] = to
+			}
+			summary.LedgerMetadata["mappings"] = mappings
+		}
+	}
+	scope := map[string]any{
+		"includeDirs":   req.IncludeDirs,
+		"recursive":     req.Recursive,
+		"includeHidden": req.IncludeHidden,
+	}
+	if len(req.ExtensionFilter) > 0 {
+		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
+	summary.LedgerMetadata["scope"] = scope
+
+	if out != nil {
+		conflictReasons := make(map[string]string, len(summary.Conflicts))
+		for _, conflict := range summary.Conflicts {
+			conflictReasons[conflict.OriginalPath+"->"+conflict.ProposedPath] = conflict.Reason
+		}
+
+		for _, entry := range summary.Entries {
+			switch entry.Status {
+			case StatusChanged:
+				if entry.Invisible {
+					fmt.Fprintf(out, "%s -> %s (normalization only)\n", entry.OriginalPath, entry.ProposedPath)
+				} else {
+					fmt.Fprintf(out, "%s -> %s\n", entry.OriginalPath, entry.ProposedPath)
+				}
+			case StatusNoChange:
+				fmt.Fprintf(out, "%s (no change)\n", entry.OriginalPath)
+			case StatusSkipped:
+				reason := conflictReasons[entry.OriginalPath+"->"+entry.ProposedPath]
+				if reason == "" {
+					reason = "skipped"
+				}
+				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			}
+		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
+
+		if summary.TotalCandidates > 0 {
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change (%d normalization-only), %d already normalized\n",
+				summary.TotalCandidates, summary.TotalChanged, summary.Invisible, summary.NoChange)
+		} else {
+			fmt.Fprintln(out, "No candidates found.")
+		}
+
+		if len(summary.Warnings) > 0 {
+			fmt.Fprintln(out)
+			for _, warning := range summary.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	return summary, operations, nil
+}
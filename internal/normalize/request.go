@@ -0,0 +1,128 @@
+package normalize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// DefaultFallback replaces characters that have no ASCII transliteration.
+const DefaultFallback = "_"
+
+// Request encapsulates the inputs required to run a normalization pass.
+type Request struct {
+	WorkingDir      string
+	Form            Form
+	ASCII           bool
+	Mappings        map[string]string
+	Fallback        string
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest constructs a Request from shared listing scope.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{Form: FormNFC, Fallback: DefaultFallback}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		Form:            FormNFC,
+		Fallback:        DefaultFallback,
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode updates dry-run and auto-apply preferences.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetTransliteration enables ASCII transliteration with custom mappings and fallback text.
+func (r *Request) SetTransliteration(enabled bool, mappings map[string]string, fallback string) {
+	r.ASCII = enabled
+	r.Mappings = mappings
+	r.Fallback = fallback
+}
+
+// Normalize ensures working directory, form, and timestamp fields are ready for execution.
+func (r *Request) Normalize() error {
+	form, err := ParseForm(string(r.Form))
+	if err != nil {
+		return err
+	}
+	r.Form = form
+
+	if len(r.Mappings) > 0 && !r.ASCII {
+		return fmt.Errorf("custom mappings require --ascii")
+	}
+	if r.ASCII {
+		for _, ch := range r.Fallback {
+			if ch == '/' || ch == '\\' || ch < 0x20 {
+				return fmt.Errorf("fallback %q must not contain path separators or control characters", r.Fallback)
+			}
+		}
+		for from, to := range r.Mappings {
+			for _, ch := range to {
+				if ch == '/' || ch == '\\' || ch < 0x20 {
+					return fmt.Errorf("mapping for %q must not produce path separators or control characters", from)
+				}
+			}
+		}
+	}
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package normalize
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// builtinASCII maps letters and punctuation that do not decompose into ASCII base characters.
+var builtinASCII = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "TH",
+	'ı': "i",
+	'ħ': "h", 'Ħ': "H",
+	'ŋ': "ng", 'Ŋ': "NG",
+	'‘': "'", '’': "'", '‚': "'",
+	'“': "\"", '”': "\"", '„': "\"",
+	'«': "<<", '»': ">>",
+	'–': "-", '—': "-", '‐': "-", '‑': "-",
+	'…': "...", '×': "x", '\u00a0': " ",
+}
+
+// Transliterator converts names to ASCII. User mappings are applied first so they can override the
+// built-in table; remaining characters are decomposed (é → e + ◌́) and stripped of combining marks,
+// and anything still outside ASCII is replaced with the fallback string.
+type Transliterator struct {
+	mappings map[string]string
+	replacer *strings.Replacer
+	fallback string
+}
+
+// NewTransliterator builds a transliterator from custom mappings and a fallback replacement.
+func NewTransliterator(mappings map[string]string, fallback string) *Transliterator {
+	t := &Transliterator{
+		mappings: make(map[string]string, len(mappings)),
+		fallback: fallback,
+	}
+
+	keys := make([]string, 0, len(mappings))
+	for from, to := range mappings {
+		if from == "" {
+			continue
+		}
+		t.mappings[from] = to
+		keys = append(keys, from)
+	}
+	// Longest keys first so multi-character mappings win over their prefixes.
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) == len(keys[j]) {
+			return keys[i] < keys[j]
+		}
+		return len(keys[i]) > len(keys[j])
+	})
+	if len(keys) > 0 {
+		pairs := make([]string, 0, len(keys)*2)
+		for _, key := range keys {
+			// Keys are normalized so NFD input still matches NFC mapping files.
+			pairs = append(pairs, norm.NFC.String(key), t.mappings[key])
+		}
+		t.replacer = strings.NewReplacer(pairs...)
+	}
+
+	return t
+}
+
+// Mappings returns a copy of the custom mapping table for ledger metadata.
+func (t *Transliterator) Mappings() map[string]string {
+	copied := make(map[string]string, len(t.mappings))
+	for k, v := range t.mappings {
+		copied[k] = v
+	}
+	return copied
+}
+
+// ToASCII transliterates value.
+func (t *Transliterator) ToASCII(value string) string {
+	current := norm.NFC.String(value)
+	if t.replacer != nil {
+		current = t.replacer.Replace(current)
+	}
+
+	var builder strings.Builder
+	for _, r := range current {
+		if r < unicode.MaxASCII {
+			builder.WriteRune(r)
+			continue
+		}
+		if mapped, ok := builtinASCII[r]; ok {
+			builder.WriteString(mapped)
+			continue
+		}
+
+		decomposed := norm.NFKD.String(string(r))
+		stripped := make([]rune, 0, len(decomposed))
+		for _, d := range decomposed {
+			if unicode.Is(unicode.Mn, d) {
+				continue
+			}
+			stripped = append(stripped, d)
+		}
+
+		ascii := true
+		for _, d := range stripped {
+			if d >= unicode.MaxASCII {
+				ascii = false
+				break
+			}
+		}
+		if ascii && len(stripped) > 0 {
+			builder.WriteString(string(stripped))
+			continue
+		}
+		builder.WriteString(t.fallback)
+	}
+	return builder.String()
+}
+
+// ParseMappings converts repeated "from=to" tokens into a mapping table.
+func ParseMappings(tokens []string) (map[string]string, error) {
+	mappings := make(map[string]string, len(tokens))
+	for _, token := range tokens {
+		from, to, ok := strings.Cut(token, "=")
+		if !ok || from == "" {
+			return nil, fmt.Errorf("invalid mapping %q (expected from=to)", token)
+		}
+		mappings[from] = to
+	}
+	return mappings, nil
+}
+
+// LoadMappingFile reads a mapping table with one "from=to" (or tab-separated) pair per line.
+// Blank lines and lines starting with # are ignored.
+func LoadMappingFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open mapping file: %w", err)
+	}
+	defer file.Close()
+
+	mappings := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+		sep := "="
+		if strings.Contains(text, "\t") {
+			sep = "\t"
+		}
+		from, to, ok := strings.Cut(text, sep)
+		if !ok || from == "" {
+			return nil, fmt.Errorf("%s:%d: expected from=to mapping", path, line)
+		}
+		mappings[from] = to
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, errors.New("mapping file " + path + " contains no mappings")
+	}
+	return mappings, nil
+}
@@ -0,0 +1,4 @@
+// Package organize moves files into subdirectories computed from a template rule such as
+// "{mtime:2006}/{mtime:01}" or "{mime-major}", creating the directories as needed and recording
+// them in the ledger so undo can remove them again.
+package organize
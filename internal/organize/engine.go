@@ -0,0 +1,253 @@
+package organize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/template"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation captures a move to be applied.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+}
+
+type candidate struct {
+	relative string
+	info     fs.FileInfo
+}
+
+// BuildPlan enumerates files, renders the directory rule for each, and prepares moves. Files keep
+// their names; only their directory changes.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("organize request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	detector := newConflictDetector()
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	candidates := make([]candidate, 0)
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		false,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." || entry.IsDir() {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			if !req.ExtensionModel.Allows(entry.Name(), filterSet) {
+				return nil
+			}
+			if !req.NameFilter.Allows(relative, false) || req.Sidecars.IsFollower(relative) {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, candidate{relative: relative, info: info})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Render every rule first and reserve files that are already in place, so a moved file never
+	// claims the name of one that stays.
+	targets := make([]string, len(candidates))
+	reasons := make([]string, len(candidates))
+	for i, c := range candidates {
+		dir, reason, err := renderDirectory(req, c, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		reasons[i] = reason
+		targets[i] = path.Join(dir, path.Base(c.relative))
+		if reason == "" && targets[i] == c.relative {
+			detector.reserve(c.relative)
+		}
+	}
+
+	created := make(map[string]bool)
+	for i, c := range candidates {
+		proposedRelative := targets[i]
+		originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(c.relative))
+		proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative))
+
+		preview := PreviewEntry{
+			OriginalPath: c.relative,
+			ProposedPath: proposedRelative,
+			Status:       StatusChanged,
+		}
+
+		reason := reasons[i]
+		if reason == "" && proposedRelative == c.relative {
+			preview.Status = StatusNoChange
+			summary.RecordEntry(preview)
+			continue
+		}
+		if reason == "" {
+			reason = blockedParent(req.WorkingDir, proposedRelative)
+		}
+		if reason == "" {
+			reason, err = detector.evaluate(c.relative, proposedRelative, originalAbsolute, proposedAbsolute)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if reason == "" {
+			if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+				reason = violation.Reason()
+			}
+		}
+		if reason != "" {
+			if proposedRelative == "" {
+				preview.ProposedPath = c.relative
+			}
+			summary.AddConflict(Conflict{
+				OriginalPath: c.relative,
+				ProposedPath: preview.ProposedPath,
+				Reason:       reason,
+			})
+			preview.Status = StatusSkipped
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		for _, dir := range missingDirectories(req.WorkingDir, path.Dir(proposedRelative)) {
+			if !created[dir] {
+				created[dir] = true
+				summary.Directories = append(summary.Directories, dir)
+			}
+		}
+
+		operations = append(operations, PlannedOperation{
+			OriginalRelative: c.relative,
+			OriginalAbsolute: originalAbsolute,
+			ProposedRelative: proposedRelative,
+			ProposedAbsolute: proposedAbsolute,
+		})
+		summary.RecordEntry(preview)
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	sort.Strings(summary.Directories)
+
+	return summary, operations, nil
+}
+
+// renderDirectory evaluates the rule for one file and validates the result as a relative
+// directory. A non-empty reason reports a per-file conflict. Rules only render directories, so
+// {ext} drops its leading dot there: "{ext}" files photo.JPG under JPG/, not a hidden .JPG/.
+func renderDirectory(req *Request, c candidate, index int) (string, string, error) {
+	rctx := template.NewContext(req.WorkingDir, c.relative, c.info, index, req.ExtensionModel)
+	rctx.Ext = strings.TrimPrefix(rctx.Ext, ".")
+	rendered, _, err := req.Rule.Render(rctx)
+	var missing *template.MissingTagError
+	if errors.As(err, &missing) {
+		return "", missing.Error(), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	dir := strings.Trim(rendered, "/")
+	switch {
+	case dir == "":
+		return "", "empty_directory", nil
+	case strings.Contains(dir, "\\"):
+		return "", "path_separator", nil
+	}
+	for _, part := range strings.Split(dir, "/") {
+		switch part {
+		case "":
+			return "", "empty_path_component", nil
+		case ".", "..":
+			return "", "invalid_name", nil
+		}
+	}
+	return dir, "", nil
+}
+
+// blockedParent reports a conflict when an existing file sits where a target directory would be
+// created.
+func blockedParent(workingDir, relative string) string {
+	dir := path.Dir(relative)
+	for dir != "." && dir != "/" {
+		info, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(dir)))
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Sprintf("parent_not_directory (%s)", dir)
+			}
+			return ""
+		}
+		dir = path.Dir(dir)
+	}
+	return ""
+}
+
+// missingDirectories lists dir and its parents that do not exist yet, parents first.
+func missingDirectories(workingDir, dir string) []string {
+	missing := make([]string, 0)
+	for dir != "." && dir != "/" && dir != "" {
+		if _, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(dir))); err == nil {
+			break
+		}
+		missing = append([]string{dir}, missing...)
+		dir = path.Dir(dir)
+	}
+	return missing
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"time"
 )
 
 // ProgressReporter prints textual progress for rename operations.
@@ -38,3 +39,59 @@ func (r *ProgressReporter) Complete() error {
 	_, err := fmt.Fprintf(r.writer, "Completed %d rename(s).\n", r.count)
 	return err
 }
+
+// progressInterval throttles ByteProgress redraws.
+const progressInterval = 100 * time.Millisecond
+
+// ByteProgress redraws a single status line of files and bytes processed, e.g. while hashing.
+type ByteProgress struct {
+	writer  io.Writer
+	label   string
+	last    time.Time
+	printed bool
+}
+
+// NewByteProgress returns a reporter writing to w; a nil writer yields a nil reporter whose
+// methods are no-ops.
+func NewByteProgress(w io.Writer, label string) *ByteProgress {
+	if w == nil {
+		return nil
+	}
+	return &ByteProgress{writer: w, label: label}
+}
+
+// Update redraws the status line, at most every progressInterval unless the work is complete.
+func (p *ByteProgress) Update(done, total int, bytes int64) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	if done < total && now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+	p.printed = true
+	fmt.Fprintf(p.writer, "\r%s %d/%d files (%s)", p.label, done, total, FormatBytes(bytes))
+}
+
+// Finish terminates the status line if anything was drawn.
+func (p *ByteProgress) Finish() {
+	if p == nil || !p.printed {
+		return
+	}
+	fmt.Fprintln(p.writer)
+}
+
+// FormatBytes renders a byte count with binary units, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
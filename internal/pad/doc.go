@@ -0,0 +1,5 @@
+// Package pad rewrites the numbers already embedded in names so they line up: ep1, ep2, ep10
+// become ep01, ep02, ep10. Widths are derived per directory from names that share a shape, so
+// s1e3 and track7 are sized independently, or fixed with --width; --strip removes the zeros
+// instead.
+package pad
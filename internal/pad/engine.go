@@ -0,0 +1,306 @@
+package pad
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation is one padding rename, with paths both relative to and joined with the working
+// directory.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	IsDir            bool
+	Depth            int
+}
+
+type candidate struct {
+	relative string
+	stem     string
+	ext      string
+	runs     [][2]int
+	group    string // directory joined with nameShape; keys the auto width
+	isDir    bool
+	depth    int
+}
+
+// BuildPlan enumerates candidates, pads their digit runs, and prepares filesystem operations.
+// Auto widths are computed per name shape within a directory and per run position (the season
+// and episode numbers of s1e10 are sized separately) from the longest digit run, so existing
+// zeros are never lost and unrelated names such as ep001x do not widen s1e03.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("pad request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	detector := newConflictDetector()
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	candidates := make([]candidate, 0)
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) || (!isDir && req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			stem, ext := splitName(name, isDir, req.ExtensionModel)
+			runs := digitRuns(stem)
+			candidates = append(candidates, candidate{
+				relative: relative,
+				stem:     stem,
+				ext:      ext,
+				runs:     selectRuns(runs, req.Occurrence),
+				group:    path.Join(path.Dir(relative), nameShape(stem, runs)),
+				isDir:    isDir,
+				depth:    depth,
+			})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, c := range candidates {
+		if len(c.runs) == 0 {
+			continue
+		}
+		widths := summary.Widths[c.group]
+		for i, run := range c.runs {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], run[1]-run[0])
+		}
+		summary.Widths[c.group] = widths
+	}
+
+	for _, c := range candidates {
+		if len(c.runs) == 0 {
+			summary.NoDigits++
+		}
+
+		widths := make([]int, len(c.runs))
+		for i := range widths {
+			switch {
+			case req.Strip:
+				widths[i] = 1
+			case req.Width > 0:
+				widths[i] = req.Width
+			default:
+				widths[i] = summary.Widths[c.group][i]
+			}
+		}
+
+		proposedName := padStem(c.stem, c.runs, widths) + c.ext
+		proposedRelative := joinRelative(c.relative, proposedName)
+		originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(c.relative))
+		proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative))
+
+		preview := PreviewEntry{
+			OriginalPath: c.relative,
+			ProposedPath: proposedRelative,
+			Status:       StatusChanged,
+		}
+
+		if proposedRelative == c.relative {
+			preview.Status = StatusNoChange
+			detector.reserve(c.relative)
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		reason, err := detector.evaluate(c.relative, proposedRelative, originalAbsolute, proposedAbsolute)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reason == "" {
+			if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+				reason = violation.Reason()
+			}
+		}
+		if reason != "" {
+			summary.AddConflict(Conflict{
+				OriginalPath: c.relative,
+				ProposedPath: proposedRelative,
+				Reason:       reason,
+			})
+			preview.Status = StatusSkipped
+		} else {
+			operations = append(operations, PlannedOperation{
+				OriginalRelative: c.relative,
+				OriginalAbsolute: originalAbsolute,
+				ProposedRelative: proposedRelative,
+				ProposedAbsolute: proposedAbsolute,
+				IsDir:            c.isDir,
+				Depth:            c.depth,
+			})
+		}
+		summary.RecordEntry(preview)
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+// splitName separates a file's extension (compound ones such as .tar.gz whole); directories and
+// dotfiles keep their full name.
+func splitName(name string, isDir bool, extensions fileext.Model) (string, string) {
+	if isDir {
+		return name, ""
+	}
+	return extensions.Split(name)
+}
+
+// nameShape replaces each digit run of stem with "#", so s1e03 and s2e10 share the shape s#e#
+// while ep001x does not.
+func nameShape(stem string, runs [][2]int) string {
+	var builder strings.Builder
+	last := 0
+	for _, run := range runs {
+		builder.WriteString(stem[last:run[0]])
+		builder.WriteByte('#')
+		last = run[1]
+	}
+	builder.WriteString(stem[last:])
+	return builder.String()
+}
+
+// digitRuns returns the byte spans of maximal ASCII digit runs in value.
+func digitRuns(value string) [][2]int {
+	runs := make([][2]int, 0)
+	for i := 0; i < len(value); {
+		if !isDigit(value[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(value) && isDigit(value[j]) {
+			j++
+		}
+		runs = append(runs, [2]int{i, j})
+		i = j
+	}
+	return runs
+}
+
+func selectRuns(runs [][2]int, occurrence Occurrence) [][2]int {
+	if len(runs) == 0 {
+		return runs
+	}
+	switch occurrence {
+	case OccurrenceFirst:
+		return runs[:1]
+	case OccurrenceLast:
+		return runs[len(runs)-1:]
+	default:
+		return runs
+	}
+}
+
+// significant strips leading zeros, keeping a single zero for all-zero runs.
+func significant(digits string) string {
+	trimmed := strings.TrimLeft(digits, "0")
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+// padStem rewrites each selected run as its significant digits left-padded with zeros to the
+// matching width. Numbers longer than their width are kept whole.
+func padStem(stem string, runs [][2]int, widths []int) string {
+	var builder strings.Builder
+	last := 0
+	for i, run := range runs {
+		width := widths[i]
+		builder.WriteString(stem[last:run[0]])
+		digits := significant(stem[run[0]:run[1]])
+		if missing := width - len(digits); missing > 0 {
+			builder.WriteString(strings.Repeat("0", missing))
+		}
+		builder.WriteString(digits)
+		last = run[1]
+	}
+	builder.WriteString(stem[last:])
+	return builder.String()
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
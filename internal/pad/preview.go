@@ -0,0 +1,100 @@
+package pad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Preview plans the padding renames and prints each change, the widths chosen, and any conflicts
+// to out.
+func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("pad request cannot be nil")
+	}
+
+	summary, operations, err := BuildPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary.LedgerMetadata["width"] = req.Width
+	summary.LedgerMetadata["occurrence"] = string(req.Occurrence)
+	summary.LedgerMetadata["strip"] = req.Strip
+	scope := map[string]any{
+		"includeDirs":   req.IncludeDirs,
+		"recursive":     req.Recursive,
+		"includeHidden": req.IncludeHidden,
+	}
+	if len(req.ExtensionFilter) > 0 {
+		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
+	summary.LedgerMetadata["scope"] = scope
+
+	if out != nil {
+		conflictReasons := make(map[string]string, len(summary.Conflicts))
+		for _, conflict := range summary.Conflicts {
+			conflictReasons[conflict.OriginalPath+"->"+conflict.ProposedPath] = conflict.Reason
+		}
+
+		for _, entry := range summary.Entries {
+			switch entry.Status {
+			case StatusChanged:
+				fmt.Fprintf(out, "%s -> %s\n", entry.OriginalPath, entry.ProposedPath)
+			case StatusNoChange:
+				fmt.Fprintf(out, "%s (no change)\n", entry.OriginalPath)
+			case StatusSkipped:
+				reason := conflictReasons[entry.OriginalPath+"->"+entry.ProposedPath]
+				if reason == "" {
+					reason = "skipped"
+				}
+				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			}
+		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
+
+		if req.Width == 0 && !req.Strip && len(summary.Widths) > 0 {
+			dirs := make([]string, 0, len(summary.Widths))
+			for dir := range summary.Widths {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			fmt.Fprintln(out, "\nDetected widths:")
+			for _, dir := range dirs {
+				widths := make([]string, len(summary.Widths[dir]))
+				for i, width := range summary.Widths[dir] {
+					widths[i] = strconv.Itoa(width)
+				}
+				fmt.Fprintf(out, "  %s: %s\n", dir, strings.Join(widths, ", "))
+			}
+		}
+
+		if summary.TotalCandidates > 0 {
+			unchanged := "already padded"
+			if req.Strip {
+				unchanged = "no leading zeros"
+			}
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d %s, %d without digits\n",
+				summary.TotalCandidates, summary.TotalChanged, summary.NoChange-summary.NoDigits, unchanged, summary.NoDigits)
+		} else {
+			fmt.Fprintln(out, "No candidates found.")
+		}
+
+		if len(summary.Warnings) > 0 {
+			fmt.Fprintln(out)
+			for _, warning := range summary.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	return summary, operations, nil
+}
@@ -0,0 +1,140 @@
+package pad
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Occurrence selects which digit runs in a stem are padded.
+type Occurrence string
+
+const (
+	// OccurrenceAll pads every digit run (default), e.g. s1e2 → s01e02.
+	OccurrenceAll Occurrence = "all"
+	// OccurrenceFirst pads only the first digit run.
+	OccurrenceFirst Occurrence = "first"
+	// OccurrenceLast pads only the last digit run.
+	OccurrenceLast Occurrence = "last"
+)
+
+// ParseOccurrence validates an --occurrence value; empty selects all.
+func ParseOccurrence(value string) (Occurrence, error) {
+	switch occurrence := Occurrence(strings.ToLower(strings.TrimSpace(value))); occurrence {
+	case "":
+		return OccurrenceAll, nil
+	case OccurrenceAll, OccurrenceFirst, OccurrenceLast:
+		return occurrence, nil
+	default:
+		return "", fmt.Errorf("unsupported occurrence %q (use all, first, or last)", value)
+	}
+}
+
+// Request holds the scope and padding options of a pad run.
+type Request struct {
+	WorkingDir string
+	// Width is the fixed digit width; zero detects the widest selected number per directory.
+	Width      int
+	Occurrence Occurrence
+	// Strip removes leading zeros instead of adding them.
+	Strip           bool
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest copies the shared scope flags into a pad Request.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{Occurrence: OccurrenceAll}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		Occurrence:      OccurrenceAll,
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode records whether the run only previews or applies without prompting.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetPadding stores the width (zero for auto), the runs to touch, and whether to strip zeros.
+func (r *Request) SetPadding(width int, occurrence Occurrence, strip bool) {
+	r.Width = width
+	r.Occurrence = occurrence
+	r.Strip = strip
+}
+
+// Normalize rejects negative widths, a width combined with strip, and unknown occurrences, and
+// resolves the working directory.
+func (r *Request) Normalize() error {
+	if r.Width < 0 {
+		return errors.New("width cannot be negative")
+	}
+	if r.Strip && r.Width > 0 {
+		return errors.New("width cannot be combined with strip mode")
+	}
+	occurrence, err := ParseOccurrence(string(r.Occurrence))
+	if err != nil {
+		return err
+	}
+	r.Occurrence = occurrence
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
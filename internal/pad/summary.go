@@ -0,0 +1,100 @@
+package pad
+
+import (
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Status reports whether padding changes a candidate's name.
+type Status string
+
+const (
+	StatusChanged  Status = "changed"
+	StatusNoChange Status = "no_change"
+	StatusSkipped  Status = "skipped"
+)
+
+// PreviewEntry pairs a candidate with its padded name.
+type PreviewEntry struct {
+	OriginalPath string
+	ProposedPath string
+	Status       Status
+}
+
+// Conflict records a padded name that collides with another target or an existing entry.
+type Conflict struct {
+	OriginalPath string
+	ProposedPath string
+	Reason       string
+}
+
+// Summary collects the padding preview: per-candidate entries, the widths chosen per name shape,
+// and the conflicts that block apply.
+type Summary struct {
+	TotalCandidates int
+	TotalChanged    int
+	NoChange        int
+	// NoDigits counts candidates whose stem holds no digit run; they are reported as no change.
+	NoDigits int
+	// Widths records the detected width of each digit run position per name shape, keyed by the
+	// directory joined with the stem's shape (digit runs written as "#"), e.g. "season/s#e#".
+	Widths map[string][]int
+
+	Entries   []PreviewEntry
+	Conflicts []Conflict
+	Warnings  []string
+
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
+
+	LedgerMetadata map[string]any
+}
+
+// NewSummary returns a Summary ready for BuildPlan to fill.
+func NewSummary() *Summary {
+	return &Summary{
+		Widths:         make(map[string][]int),
+		Entries:        make([]PreviewEntry, 0),
+		Conflicts:      make([]Conflict, 0),
+		Warnings:       make([]string, 0),
+		LedgerMetadata: make(map[string]any),
+	}
+}
+
+// RecordEntry adds entry to the preview and counts it as changed or unchanged.
+func (s *Summary) RecordEntry(entry PreviewEntry) {
+	s.Entries = append(s.Entries, entry)
+	s.TotalCandidates++
+
+	switch entry.Status {
+	case StatusChanged:
+		s.TotalChanged++
+	case StatusNoChange:
+		s.NoChange++
+	}
+}
+
+// AddConflict records a collision; any conflict blocks apply.
+func (s *Summary) AddConflict(conflict Conflict) {
+	s.Conflicts = append(s.Conflicts, conflict)
+}
+
+// AddWarning records msg once, however many candidates raise it.
+func (s *Summary) AddWarning(msg string) {
+	if msg == "" {
+		return
+	}
+	for _, existing := range s.Warnings {
+		if existing == msg {
+			return
+		}
+	}
+	s.Warnings = append(s.Warnings, msg)
+}
+
+// HasConflicts reports whether any padded name collides.
+func (s *Summary) HasConflicts() bool {
+	return len(s.Conflicts) > 0
+}
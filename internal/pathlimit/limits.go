@@ -0,0 +1,76 @@
+// Package pathlimit validates proposed names against filesystem length limits. Every rename
+// engine runs its planned targets through Check during preview so over-long names surface as
+// conflicts instead of failing halfway through apply.
+package pathlimit
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	// DefaultMaxNameBytes is the per-component limit of ext4, NTFS (in UTF-16 units), APFS, and
+	// most archive formats.
+	DefaultMaxNameBytes = 255
+	// DefaultMaxPathBytes matches Linux PATH_MAX.
+	DefaultMaxPathBytes = 4096
+)
+
+// Kind identifies which limit a name violates.
+type Kind string
+
+const (
+	KindNameTooLong Kind = "name_too_long"
+	KindPathTooLong Kind = "path_too_long"
+)
+
+// Limits holds the byte budgets for a single path component and for the whole absolute path.
+// A zero value disables the corresponding check.
+type Limits struct {
+	MaxNameBytes int
+	MaxPathBytes int
+}
+
+// Default returns the limits enforced by every engine's preview.
+func Default() Limits {
+	return Limits{MaxNameBytes: DefaultMaxNameBytes, MaxPathBytes: DefaultMaxPathBytes}
+}
+
+// Violation describes a limit exceeded by a proposed path.
+type Violation struct {
+	Kind   Kind
+	Length int
+	Limit  int
+}
+
+// Reason renders the violation as a conflict reason, e.g. "name_too_long (312 bytes > 255)".
+func (v Violation) Reason() string {
+	return fmt.Sprintf("%s (%d bytes > %d)", v.Kind, v.Length, v.Limit)
+}
+
+// Check validates the slash-separated relative path beneath workingDir.
+func (l Limits) Check(workingDir, relative string) (Violation, bool) {
+	return l.CheckPath(filepath.Join(workingDir, filepath.FromSlash(relative)))
+}
+
+// CheckPath validates an absolute path: its base name against MaxNameBytes and the whole path
+// against MaxPathBytes.
+func (l Limits) CheckPath(absolute string) (Violation, bool) {
+	if name := filepath.Base(absolute); l.MaxNameBytes > 0 && len(name) > l.MaxNameBytes {
+		return Violation{Kind: KindNameTooLong, Length: len(name), Limit: l.MaxNameBytes}, false
+	}
+	if l.MaxPathBytes > 0 && len(absolute) > l.MaxPathBytes {
+		return Violation{Kind: KindPathTooLong, Length: len(absolute), Limit: l.MaxPathBytes}, false
+	}
+	return Violation{}, true
+}
+
+// Check validates relative against the default limits.
+func Check(workingDir, relative string) (Violation, bool) {
+	return Default().Check(workingDir, relative)
+}
+
+// CheckPath validates an absolute path against the default limits.
+func CheckPath(absolute string) (Violation, bool) {
+	return Default().CheckPath(absolute)
+}
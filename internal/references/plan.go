@@ -0,0 +1,314 @@
+package references
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/history"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+var (
+	markdownInline = regexp.MustCompile(`\]\(\s*(?:<([^>\n]*)>|([^)\s]+))`)
+	markdownDefine = regexp.MustCompile(`(?m)^ {0,3}\[[^\]\n]+\]:[ \t]*(?:<([^>\n]*)>|(\S+))`)
+	htmlAttribute  = regexp.MustCompile(`(?i)\b(?:src|href|poster)\s*=\s*(?:"([^"\n]*)"|'([^'\n]*)')`)
+)
+
+// Edit is the rewrite of one document. Path names the document before the batch runs and
+// Target where it lives afterwards; both are slash-separated and relative to the working
+// directory.
+type Edit struct {
+	Path   string
+	Target string
+	Before string
+	After  string
+	Count  int
+}
+
+// Skip names a matching document that was not scanned for references and why.
+type Skip struct {
+	Path   string
+	Reason string
+}
+
+// Plan holds the document rewrites derived for a batch of renames.
+type Plan struct {
+	Edits   []Edit
+	Skipped []Skip
+}
+
+// Plan rewrites the relative references in every selected document so they keep pointing at
+// their files once the batch has run. Stages list the batch's renames in the order they run:
+// within a stage a path takes its own rename first and is then rebased through renamed parent
+// directories, deepest first, as engines rename children before their parents. A document that
+// moves has its references recomputed from its new directory. References are found in Markdown
+// links, images, and link definitions, in HTML src, href, and poster attributes, and on the
+// entry lines of .m3u/.m3u8 playlists; URLs, absolute paths, paths leaving the working
+// directory, and paths that do not exist are left alone.
+func (r *Rewriter) Plan(stages ...[]sidecar.Rename) (Plan, error) {
+	plan := Plan{}
+	if r == nil {
+		return plan, nil
+	}
+	res := newResolver(stages)
+	if res.empty() {
+		return plan, nil
+	}
+
+	docs, skipped, err := r.documents()
+	if err != nil {
+		return plan, err
+	}
+	plan.Skipped = skipped
+	for _, doc := range docs {
+		target := res.resolve(doc.rel)
+		after, count := r.rewrite(doc, target, res)
+		if count == 0 {
+			continue
+		}
+		plan.Edits = append(plan.Edits, Edit{Path: doc.rel, Target: target, Before: doc.content, After: after, Count: count})
+	}
+	return plan, nil
+}
+
+// span locates one reference inside a document; bare references end at whitespace, so
+// replacements containing it are percent-encoded.
+type span struct {
+	start int
+	end   int
+	bare  bool
+}
+
+func (r *Rewriter) rewrite(doc document, target string, res resolver) (string, int) {
+	oldDir := path.Dir(doc.rel)
+	newDir := path.Dir(target)
+
+	var b strings.Builder
+	last := 0
+	count := 0
+	for _, s := range findSpans(doc) {
+		replacement, ok := r.rewriteReference(doc.content[s.start:s.end], oldDir, newDir, s.bare, res)
+		if !ok {
+			continue
+		}
+		b.WriteString(doc.content[last:s.start])
+		b.WriteString(replacement)
+		last = s.end
+		count++
+	}
+	if count == 0 {
+		return doc.content, 0
+	}
+	b.WriteString(doc.content[last:])
+	return b.String(), count
+}
+
+func (r *Rewriter) rewriteReference(ref, oldDir, newDir string, bare bool, res resolver) (string, bool) {
+	pathPart, suffix := ref, ""
+	if idx := strings.IndexAny(ref, "?#"); idx >= 0 {
+		pathPart, suffix = ref[:idx], ref[idx:]
+	}
+	if pathPart == "" || strings.HasPrefix(pathPart, "/") || strings.Contains(pathPart, ":") || strings.Contains(pathPart, `\`) {
+		return "", false
+	}
+
+	escaped := strings.Contains(pathPart, "%")
+	decoded := pathPart
+	if escaped {
+		value, err := url.PathUnescape(pathPart)
+		if err != nil {
+			return "", false
+		}
+		decoded = value
+	}
+
+	current := path.Join(oldDir, decoded)
+	if current == "." || current == ".." || strings.HasPrefix(current, "../") {
+		return "", false
+	}
+	if _, err := os.Lstat(filepath.Join(r.workingDir, filepath.FromSlash(current))); err != nil {
+		return "", false
+	}
+	final := res.resolve(current)
+	if final == current && newDir == oldDir {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(newDir), filepath.FromSlash(final))
+	if err != nil {
+		return "", false
+	}
+	updated := filepath.ToSlash(rel)
+	if strings.HasPrefix(decoded, "./") && !strings.HasPrefix(updated, "../") {
+		updated = "./" + updated
+	}
+	if strings.HasSuffix(decoded, "/") && !strings.HasSuffix(updated, "/") {
+		updated += "/"
+	}
+	if escaped || (bare && strings.ContainsAny(updated, " \t()")) {
+		updated = (&url.URL{Path: updated}).EscapedPath()
+	}
+	if updated == pathPart {
+		return "", false
+	}
+	return updated + suffix, true
+}
+
+// findSpans returns the references in doc ordered by position, without overlaps.
+func findSpans(doc document) []span {
+	spans := make([]span, 0)
+	switch strings.ToLower(path.Ext(doc.rel)) {
+	case ".m3u", ".m3u8":
+		offset := 0
+		for _, line := range strings.SplitAfter(doc.content, "\n") {
+			entry := strings.TrimRight(line, "\r\n")
+			trimmed := strings.TrimSpace(entry)
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				start := offset + strings.Index(entry, trimmed)
+				spans = append(spans, span{start: start, end: start + len(trimmed)})
+			}
+			offset += len(line)
+		}
+		return spans
+	}
+
+	for _, pattern := range []*regexp.Regexp{markdownInline, markdownDefine, htmlAttribute} {
+		bare := pattern != htmlAttribute
+		for _, match := range pattern.FindAllStringSubmatchIndex(doc.content, -1) {
+			switch {
+			case match[2] >= 0:
+				spans = append(spans, span{start: match[2], end: match[3]})
+			case match[4] >= 0:
+				spans = append(spans, span{start: match[4], end: match[5], bare: bare})
+			}
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	ordered := spans[:0]
+	end := -1
+	for _, s := range spans {
+		if s.start < end {
+			continue
+		}
+		ordered = append(ordered, s)
+		end = s.end
+	}
+	return ordered
+}
+
+// resolver maps paths from before a batch to where they end up afterwards.
+type resolver struct {
+	stages []stage
+}
+
+type stage struct {
+	exact map[string]string
+	dirs  []sidecar.Rename
+}
+
+func newResolver(batches [][]sidecar.Rename) resolver {
+	res := resolver{}
+	for _, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+		st := stage{exact: make(map[string]string, len(batch))}
+		for _, rename := range batch {
+			if rename.From == rename.To {
+				continue
+			}
+			st.exact[rename.From] = rename.To
+			st.dirs = append(st.dirs, rename)
+		}
+		sort.SliceStable(st.dirs, func(i, j int) bool {
+			return strings.Count(st.dirs[i].From, "/") > strings.Count(st.dirs[j].From, "/")
+		})
+		res.stages = append(res.stages, st)
+	}
+	return res
+}
+
+func (r resolver) empty() bool {
+	for _, st := range r.stages {
+		if len(st.exact) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r resolver) resolve(rel string) string {
+	for _, st := range r.stages {
+		if to, ok := st.exact[rel]; ok {
+			rel = to
+		}
+		for _, dir := range st.dirs {
+			if strings.HasPrefix(rel, dir.From+"/") {
+				rel = dir.To + rel[len(dir.From):]
+			}
+		}
+	}
+	return rel
+}
+
+// WritePreview prints each rewrite as a unified diff beneath an engine's own preview, after a
+// warning for every matching document that was skipped.
+func (p Plan) WritePreview(out io.Writer) {
+	if out == nil || (len(p.Edits) == 0 && len(p.Skipped) == 0) {
+		return
+	}
+	fmt.Fprintln(out, "\nReferences:")
+	for _, skip := range p.Skipped {
+		fmt.Fprintf(out, "Warning: %s not scanned for references (%s)\n", skip.Path, skip.Reason)
+	}
+	for _, edit := range p.Edits {
+		fmt.Fprintf(out, "--- %s\n+++ %s\n", edit.Path, edit.Target)
+		before := strings.Split(edit.Before, "\n")
+		after := strings.Split(edit.After, "\n")
+		// Rewrites never add or remove lines, so hunks are runs of changed lines.
+		for i := 0; i < len(before) && i < len(after); i++ {
+			if before[i] == after[i] {
+				continue
+			}
+			j := i
+			for j < len(before) && j < len(after) && before[j] != after[j] {
+				j++
+			}
+			fmt.Fprintf(out, "@@ -%[1]d,%[2]d +%[1]d,%[2]d @@\n", i+1, j-i)
+			for _, line := range before[i:j] {
+				fmt.Fprintf(out, "-%s\n", line)
+			}
+			for _, line := range after[i:j] {
+				fmt.Fprintf(out, "+%s\n", line)
+			}
+			i = j
+		}
+	}
+}
+
+// Apply writes the rewritten documents once the engine and its sidecars have renamed everything
+// and returns the edit operations to record last in the ledger. A failure restores the documents
+// already written.
+func (p Plan) Apply(workingDir string) ([]history.Operation, error) {
+	if len(p.Edits) == 0 {
+		return nil, nil
+	}
+	done := make([]history.Operation, 0, len(p.Edits))
+	for _, edit := range p.Edits {
+		op, err := history.WriteContent(workingDir, edit.Target, []byte(edit.Before), []byte(edit.After))
+		if err != nil {
+			return nil, errors.Join(err, history.Rollback(workingDir, done))
+		}
+		done = append(done, op)
+	}
+	return done, nil
+}
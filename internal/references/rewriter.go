@@ -0,0 +1,117 @@
+package references
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/history"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// maxDocumentBytes bounds the files read as documents; larger files are skipped.
+const maxDocumentBytes = 8 << 20
+
+// Rewriter finds the documents selected by --update-references. A nil Rewriter disables
+// reference updates, so engines can call it unconditionally.
+type Rewriter struct {
+	workingDir    string
+	patterns      []string
+	includeHidden bool
+}
+
+// ParsePatterns splits a pipe-delimited glob list such as "*.md|docs/*.html". Patterns without
+// a slash match base names at any depth; patterns with one match paths relative to the working
+// directory.
+func ParsePatterns(raw string) ([]string, error) {
+	patterns := make([]string, 0)
+	for _, part := range strings.Split(raw, "|") {
+		pattern := strings.TrimSpace(filepath.ToSlash(part))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid reference glob %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("reference globs cannot be empty")
+	}
+	return patterns, nil
+}
+
+// New builds a Rewriter scanning every file under workingDir, recursively, whose path matches
+// one of patterns. Hidden files are considered only when includeHidden is set.
+func New(workingDir string, patterns []string, includeHidden bool) *Rewriter {
+	return &Rewriter{workingDir: workingDir, patterns: patterns, includeHidden: includeHidden}
+}
+
+// Patterns returns the globs selecting documents.
+func (r *Rewriter) Patterns() []string {
+	if r == nil {
+		return nil
+	}
+	return append([]string(nil), r.patterns...)
+}
+
+func (r *Rewriter) matches(rel string) bool {
+	for _, pattern := range r.patterns {
+		subject := rel
+		if !strings.Contains(pattern, "/") {
+			subject = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// documents lists the matching text files as slash-separated relative paths with their contents.
+// The ledger and its backups are ignored; binary files, invalid UTF-8, and oversized files are
+// returned as skipped so the preview can name them.
+func (r *Rewriter) documents() ([]document, []Skip, error) {
+	docs := make([]document, 0)
+	skipped := make([]Skip, 0)
+	err := traversal.NewWalker().Walk(r.workingDir, true, false, r.includeHidden, 0, func(relPath string, entry fs.DirEntry, depth int) error {
+		rel := filepath.ToSlash(relPath)
+		if entry.IsDir() || !entry.Type().IsRegular() || history.IsLedgerPath(rel) || !r.matches(rel) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxDocumentBytes {
+			skipped = append(skipped, Skip{Path: rel, Reason: fmt.Sprintf("larger than %d MiB", maxDocumentBytes>>20)})
+			return nil
+		}
+		data, err := os.ReadFile(filepath.Join(r.workingDir, relPath))
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, 0) >= 0 {
+			skipped = append(skipped, Skip{Path: rel, Reason: "contains NUL bytes"})
+			return nil
+		}
+		if !utf8.Valid(data) {
+			skipped = append(skipped, Skip{Path: rel, Reason: "not valid UTF-8"})
+			return nil
+		}
+		docs = append(docs, document{rel: rel, content: string(data)})
+		return nil
+	})
+	return docs, skipped, err
+}
+
+type document struct {
+	rel     string
+	content string
+}
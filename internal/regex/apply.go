@@ -2,9 +2,9 @@ package regex
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 
@@ -31,31 +31,21 @@ func Apply(ctx context.Context, req Request, planned []PlannedRename, summary Su
 	done := make([]history.Operation, 0, len(planned))
 	groupsMeta := make(map[string][]string, len(planned))
 
-	revert := func() error {
-		for i := len(done) - 1; i >= 0; i-- {
-			op := done[i]
-			source := filepath.Join(reqCopy.WorkingDir, filepath.FromSlash(op.To))
-			destination := filepath.Join(reqCopy.WorkingDir, filepath.FromSlash(op.From))
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
-		}
-		return nil
-	}
-
 	for _, plan := range planned {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(reqCopy.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
-		if err := os.MkdirAll(filepath.Dir(plan.TargetAbsolute), 0o755); err != nil {
-			_ = revert()
+		created, err := history.EnsureDir(reqCopy.WorkingDir, path.Dir(filepath.ToSlash(plan.TargetRelative)))
+		done = append(done, created...)
+		if err != nil {
+			_ = history.Rollback(reqCopy.WorkingDir, done)
 			return history.Entry{}, fmt.Errorf("prepare target directory: %w", err)
 		}
 
 		if err := os.Rename(plan.SourceAbsolute, plan.TargetAbsolute); err != nil {
-			_ = revert()
+			_ = history.Rollback(reqCopy.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -67,6 +57,19 @@ func Apply(ctx context.Context, req Request, planned []PlannedRename, summary Su
 		}
 	}
 
+	followers, err := summary.Sidecars.Apply(reqCopy.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(reqCopy.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, followers...)
+	edits, err := summary.References.Apply(reqCopy.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(reqCopy.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, edits...)
+
 	if len(done) == 0 {
 		return entry, nil
 	}
@@ -75,16 +78,26 @@ func Apply(ctx context.Context, req Request, planned []PlannedRename, summary Su
 	metadata := map[string]any{
 		"pattern":  reqCopy.Pattern,
 		"template": reqCopy.Template,
+		"target":   string(reqCopy.Target),
 		"matched":  summary.Matched,
 		"changed":  summary.Changed,
 	}
+	if reqCopy.Limit != 0 {
+		metadata["limit"] = reqCopy.Limit
+	}
+	if reqCopy.IgnoreCase || reqCopy.Multiline {
+		metadata["flags"] = map[string]any{"ignoreCase": reqCopy.IgnoreCase, "multiline": reqCopy.Multiline}
+	}
+	if nameFilter := reqCopy.NameFilter.Metadata(); nameFilter != nil {
+		metadata["nameFilter"] = nameFilter
+	}
 	if len(groupsMeta) > 0 {
 		metadata["matchGroups"] = groupsMeta
 	}
 	entry.Metadata = metadata
 
 	if err := history.Append(reqCopy.WorkingDir, entry); err != nil {
-		_ = revert()
+		_ = history.Rollback(reqCopy.WorkingDir, done)
 		return history.Entry{}, err
 	}
 
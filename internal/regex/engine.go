@@ -3,6 +3,7 @@ package regex
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 // Engine encapsulates a compiled regex pattern and parsed template for reuse across candidates.
@@ -28,6 +29,17 @@ func NewEngine(pattern, tmpl string) (*Engine, error) {
 		return nil, ErrTemplateGroupOutOfRange{Group: maxGroup, Available: re.NumSubexp()}
 	}
 
+	for i, segment := range parsed.segments {
+		if segment.name == "" {
+			continue
+		}
+		index := re.SubexpIndex(segment.name)
+		if index < 0 {
+			return nil, ErrUnknownGroupName{Name: segment.name}
+		}
+		parsed.segments[i].group = index
+	}
+
 	return &Engine{
 		re:     re,
 		tmpl:   parsed,
@@ -35,26 +47,76 @@ func NewEngine(pattern, tmpl string) (*Engine, error) {
 	}, nil
 }
 
+// Result describes one evaluation of the engine against an input.
+type Result struct {
+	// Output is the rewritten input.
+	Output string
+	// Groups holds the capture groups of the first match, excluding the full match.
+	Groups []string
+	// Spans are the byte ranges of input that were replaced, in order.
+	Spans [][2]int
+}
+
 // Apply evaluates the regex against input and renders the replacement when it matches.
 // When no match occurs, matched is false without error.
 func (e *Engine) Apply(input string) (output string, matchGroups []string, matched bool, err error) {
-	submatches := e.re.FindStringSubmatch(input)
-	if submatches == nil {
-		return "", nil, false, nil
+	result, matched, err := e.Substitute(input, 0)
+	if err != nil || !matched {
+		return "", nil, false, err
 	}
+	return result.Output, result.Groups, true, nil
+}
 
-	rendered, err := e.tmpl.render(submatches)
-	if err != nil {
-		return "", nil, false, err
+// Substitute rewrites input using the template. A limit of zero replaces the whole input with the
+// template rendered from the first match, which is the classic regex command behaviour. A
+// positive limit replaces up to that many matches in place and a negative limit replaces every
+// match, keeping the unmatched text around them.
+func (e *Engine) Substitute(input string, limit int) (Result, bool, error) {
+	n := limit
+	if limit == 0 {
+		n = 1
 	}
+	matches := e.re.FindAllStringSubmatchIndex(input, n)
+	if len(matches) == 0 {
+		return Result{}, false, nil
+	}
+
+	result := Result{Spans: make([][2]int, 0, len(matches))}
+	var builder strings.Builder
+	last := 0
+	for i, indexes := range matches {
+		submatches := make([]string, len(indexes)/2)
+		participated := make([]bool, len(indexes)/2)
+		for g := range submatches {
+			if start := indexes[2*g]; start >= 0 {
+				submatches[g] = input[start:indexes[2*g+1]]
+				participated[g] = true
+			}
+		}
 
-	// Exclude the full match from the recorded match group slice.
-	groups := make([]string, 0, len(submatches)-1)
-	if len(submatches) > 1 {
-		groups = append(groups, submatches[1:]...)
+		rendered, err := e.tmpl.render(submatches, participated)
+		if err != nil {
+			return Result{}, false, err
+		}
+
+		if i == 0 {
+			// Exclude the full match from the recorded match group slice.
+			result.Groups = append(make([]string, 0, len(submatches)-1), submatches[1:]...)
+			if limit == 0 {
+				result.Output = rendered
+				result.Spans = append(result.Spans, [2]int{indexes[0], indexes[1]})
+				return result, true, nil
+			}
+		}
+		result.Spans = append(result.Spans, [2]int{indexes[0], indexes[1]})
+		builder.WriteString(input[last:indexes[0]])
+		builder.WriteString(rendered)
+		last = indexes[1]
 	}
+	builder.WriteString(input[last:])
+	result.Output = builder.String()
 
-	return rendered, groups, true, nil
+	return result, true, nil
 }
 
 // ErrTemplateGroupOutOfRange indicates that the template references a capture group that the regex
@@ -67,3 +129,13 @@ type ErrTemplateGroupOutOfRange struct {
 func (e ErrTemplateGroupOutOfRange) Error() string {
 	return fmt.Sprintf("template references @%d but pattern only defines %d groups", e.Group, e.Available)
 }
+
+// ErrUnknownGroupName indicates that the template references @{name} but the pattern defines no
+// (?P<name>...) group with that name.
+type ErrUnknownGroupName struct {
+	Name string
+}
+
+func (e ErrUnknownGroupName) Error() string {
+	return fmt.Sprintf("template references @{%s} but pattern defines no group named %q", e.Name, e.Name)
+}
@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // PlannedRename represents a proposed rename resulting from preview.
@@ -26,7 +31,7 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 		return Summary{}, nil, err
 	}
 
-	engine, err := NewEngine(reqCopy.Pattern, reqCopy.Template)
+	engine, err := NewEngine(reqCopy.EffectivePattern(), reqCopy.Template)
 	if err != nil {
 		return Summary{}, nil, err
 	}
@@ -46,7 +51,15 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 	err = TraverseCandidates(ctx, &reqCopy, func(candidate Candidate) error {
 		summary.TotalCandidates++
 
-		rendered, groups, matched, err := engine.Apply(candidate.Stem)
+		input := candidate.Stem
+		switch reqCopy.Target {
+		case filters.MatchTargetBasename:
+			input = candidate.BaseName
+		case filters.MatchTargetPath:
+			input = candidate.RelativePath
+		}
+
+		result, matched, err := engine.Substitute(input, reqCopy.Limit)
 		if err != nil {
 			summary.Warnings = append(summary.Warnings, err.Error())
 			summary.Skipped++
@@ -69,9 +82,10 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 		}
 
 		summary.Matched++
+		groups := result.Groups
 
-		proposedName := rendered
-		if !candidate.IsDir && candidate.Extension != "" {
+		proposedName := result.Output
+		if reqCopy.Target == filters.MatchTargetStem && !candidate.IsDir && candidate.Extension != "" {
 			proposedName += candidate.Extension
 		}
 
@@ -81,9 +95,16 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 		}
 
 		var proposedRelative string
-		if dir != "" {
+		switch {
+		case reqCopy.Target == filters.MatchTargetPath:
+			proposedRelative = result.Output
+			proposedName = path.Base(proposedRelative)
+			if !validRelativePath(proposedRelative) {
+				proposedName = ""
+			}
+		case dir != "":
 			proposedRelative = filepath.ToSlash(filepath.Join(dir, proposedName))
-		} else {
+		default:
 			proposedRelative = filepath.ToSlash(proposedName)
 		}
 
@@ -140,6 +161,18 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 			return nil
 		}
 
+		if violation, ok := pathlimit.Check(reqCopy.WorkingDir, proposedRelative); !ok {
+			summary.Conflicts = append(summary.Conflicts, Conflict{
+				OriginalPath: candidate.RelativePath,
+				ProposedPath: proposedRelative,
+				Reason:       ConflictReason(violation.Reason()),
+			})
+			summary.Skipped++
+			matchEntry.Status = EntrySkipped
+			summary.Entries = append(summary.Entries, matchEntry)
+			return nil
+		}
+
 		targetAbsolute := filepath.Join(reqCopy.WorkingDir, filepath.FromSlash(proposedRelative))
 		if info, statErr := os.Stat(targetAbsolute); statErr == nil {
 			origInfo, origErr := os.Stat(candidate.OriginalPath)
@@ -178,6 +211,7 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 
 		if out != nil {
 			fmt.Fprintf(out, "%s -> %s\n", candidate.RelativePath, proposedRelative)
+			fmt.Fprintf(out, "    match: %s\n", highlightMatches(candidate, reqCopy.Target, input, result.Spans))
 		}
 
 		return nil
@@ -186,5 +220,59 @@ func Preview(ctx context.Context, req Request, out io.Writer) (Summary, []Planne
 		return Summary{}, nil, err
 	}
 
+	renames := make([]sidecar.Rename, 0, len(planned))
+	for _, op := range planned {
+		renames = append(renames, sidecar.Rename{From: op.SourceRelative, To: op.TargetRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(reqCopy.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.Conflicts = append(summary.Conflicts, Conflict{
+			OriginalPath: conflict.From,
+			ProposedPath: conflict.To,
+			Reason:       ConflictReason(conflict.Reason),
+		})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return Summary{}, nil, err
+	}
+	summary.References = refs
+	summary.Sidecars.WritePreview(out)
+	summary.References.WritePreview(out)
+
 	return summary, planned, nil
 }
+
+// validRelativePath reports whether a rewritten path stays inside the working directory and has
+// no empty, "." or ".." components.
+func validRelativePath(rel string) bool {
+	if rel == "" || strings.HasPrefix(rel, "/") || strings.Contains(rel, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(rel, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightMatches brackets the replaced spans of input and shows it in the context of the
+// candidate name, so the preview makes clear which text the pattern consumed.
+func highlightMatches(candidate Candidate, target filters.NameMatchTarget, input string, spans [][2]int) string {
+	var builder strings.Builder
+	last := 0
+	for _, span := range spans {
+		builder.WriteString(input[last:span[0]])
+		builder.WriteString("[")
+		builder.WriteString(input[span[0]:span[1]])
+		builder.WriteString("]")
+		last = span[1]
+	}
+	builder.WriteString(input[last:])
+
+	if target == filters.MatchTargetStem && !candidate.IsDir {
+		builder.WriteString(candidate.Extension)
+	}
+	return builder.String()
+}
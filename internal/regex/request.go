@@ -5,18 +5,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // Request captures the inputs required to evaluate regex-based rename operations.
 type Request struct {
-	WorkingDir         string
-	Pattern            string
-	Template           string
+	WorkingDir string
+	Pattern    string
+	Template   string
+	// Target selects what the pattern is matched against: the stem (default), the basename
+	// including its extension, or the slash-separated path relative to WorkingDir.
+	Target filters.NameMatchTarget
+	// Limit switches from whole-target replacement (zero) to in-place substitution of the first
+	// Limit matches; a negative value substitutes every match.
+	Limit              int
+	IgnoreCase         bool
+	Multiline          bool
 	IncludeDirectories bool
 	Recursive          bool
 	IncludeHidden      bool
 	Extensions         []string
+	ExtensionModel     fileext.Model
+	NameFilter         filters.NameFilter
+	Sidecars           *sidecar.Groups
+	References         *references.Rewriter
 	DryRun             bool
 	AutoConfirm        bool
 	Timestamp          time.Time
@@ -31,6 +50,41 @@ func NewRequest(workingDir string) Request {
 	}
 }
 
+// ParseLimit converts a --replace value into a Request.Limit: "all" substitutes every match,
+// "first" or a positive count substitutes that many, and an empty value keeps whole-target
+// replacement.
+func ParseLimit(value string) (int, error) {
+	switch trimmed := strings.ToLower(strings.TrimSpace(value)); trimmed {
+	case "":
+		return 0, nil
+	case "all":
+		return -1, nil
+	case "first":
+		return 1, nil
+	default:
+		count, err := strconv.Atoi(trimmed)
+		if err != nil || count < 1 {
+			return 0, fmt.Errorf("invalid --replace value %q (use all, first, or a positive count)", value)
+		}
+		return count, nil
+	}
+}
+
+// EffectivePattern returns Pattern prefixed with the inline flags selected on the request.
+func (r Request) EffectivePattern() string {
+	flags := ""
+	if r.IgnoreCase {
+		flags += "i"
+	}
+	if r.Multiline {
+		flags += "m"
+	}
+	if flags == "" {
+		return r.Pattern
+	}
+	return "(?" + flags + ")" + r.Pattern
+}
+
 // Validate ensures the request has usable defaults and a resolvable working directory.
 func (r *Request) Validate() error {
 	if r == nil {
@@ -41,6 +95,10 @@ func (r *Request) Validate() error {
 		return errors.New("regex pattern is required")
 	}
 
+	if r.Target == "" {
+		r.Target = filters.MatchTargetStem
+	}
+
 	if r.WorkingDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -1,5 +1,10 @@
 package regex
 
+import (
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
 // Summary describes the outcome of previewing or applying a regex rename request.
 type Summary struct {
 	TotalCandidates int
@@ -9,6 +14,8 @@ type Summary struct {
 	Conflicts       []Conflict
 	Warnings        []string
 	Entries         []PreviewEntry
+	Sidecars        sidecar.Plan
+	References      references.Plan
 	LedgerMetadata  map[string]any
 }
 
@@ -4,11 +4,25 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/casing"
 )
 
 type templateSegment struct {
 	literal string
 	group   int
+	// name is set for @{name} references until the engine resolves it to a group index.
+	name string
+	// raw is the placeholder as written, used in error messages.
+	raw string
+	// style converts the substituted text; empty leaves it unchanged.
+	style casing.Style
+	// pad zero-pads numeric captures to this many digits.
+	pad int
+	// fallback replaces a group that did not participate in the match.
+	fallback    string
+	hasFallback bool
 }
 
 const literalSegment = -1
@@ -19,13 +33,23 @@ type template struct {
 }
 
 // parseTemplate converts a string containing literal text, numbered placeholders (@0, @1, ...),
-// and escaped @@ sequences into a template structure. It returns the template, the highest
-// placeholder index encountered, or an error when syntax is invalid.
+// braced placeholders (@{2}, @{date}, @{1:upper:03}), and escaped @@ sequences into a template
+// structure. It returns the template, the highest numbered placeholder index encountered, or an
+// error when syntax is invalid. Named references are resolved later against the pattern.
 func parseTemplate(input string) (template, int, error) {
 	segments := make([]templateSegment, 0)
 	var literal strings.Builder
 	maxGroup := 0
 
+	// Flush any buffered literal before handling placeholder/escape.
+	flushLiteral := func() {
+		if literal.Len() == 0 {
+			return
+		}
+		segments = append(segments, templateSegment{literal: literal.String(), group: literalSegment})
+		literal.Reset()
+	}
+
 	i := 0
 	for i < len(input) {
 		ch := input[i]
@@ -35,27 +59,35 @@ func parseTemplate(input string) (template, int, error) {
 			continue
 		}
 
-		// Flush any buffered literal before handling placeholder/escape.
-		flushLiteral := func() {
-			if literal.Len() == 0 {
-				return
-			}
-			segments = append(segments, templateSegment{literal: literal.String(), group: literalSegment})
-			literal.Reset()
-		}
-
 		if i+1 >= len(input) {
 			return template{}, 0, fmt.Errorf("dangling @ at end of template")
 		}
 
 		next := input[i+1]
 		if next == '@' {
-			flushLiteral()
 			literal.WriteByte('@')
 			i += 2
 			continue
 		}
 
+		if next == '{' {
+			end := strings.IndexByte(input[i+2:], '}')
+			if end < 0 {
+				return template{}, 0, fmt.Errorf("unclosed placeholder starting at offset %d", i)
+			}
+			segment, err := parseBracedPlaceholder(input[i+2 : i+2+end])
+			if err != nil {
+				return template{}, 0, err
+			}
+			flushLiteral()
+			segments = append(segments, segment)
+			if segment.name == "" && segment.group > maxGroup {
+				maxGroup = segment.group
+			}
+			i += end + 3
+			continue
+		}
+
 		j := i + 1
 		for j < len(input) && input[j] >= '0' && input[j] <= '9' {
 			j++
@@ -71,7 +103,7 @@ func parseTemplate(input string) (template, int, error) {
 		}
 
 		flushLiteral()
-		segments = append(segments, templateSegment{group: index})
+		segments = append(segments, templateSegment{group: index, raw: input[i:j]})
 		if index > maxGroup {
 			maxGroup = index
 		}
@@ -79,17 +111,84 @@ func parseTemplate(input string) (template, int, error) {
 		i = j
 	}
 
-	if literal.Len() > 0 {
-		segments = append(segments, templateSegment{literal: literal.String(), group: literalSegment})
-	}
+	flushLiteral()
 
 	return template{segments: segments}, maxGroup, nil
 }
 
+// parseBracedPlaceholder parses the body of @{...}: a group index or name followed by
+// ':'-separated modifiers. A case style (upper, lower, title, …) converts the text, a
+// zero-led width such as 03 pads numeric captures, and a final "-text" modifier supplies the
+// value for a group that did not participate in the match (it may itself contain colons).
+func parseBracedPlaceholder(body string) (templateSegment, error) {
+	raw := "@{" + body + "}"
+	ref, rest, _ := strings.Cut(body, ":")
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return templateSegment{}, fmt.Errorf("empty placeholder %s", raw)
+	}
+
+	segment := templateSegment{raw: raw}
+	if index, err := strconv.Atoi(ref); err == nil {
+		if index < 0 {
+			return templateSegment{}, fmt.Errorf("invalid placeholder index %s", raw)
+		}
+		segment.group = index
+	} else {
+		if !validGroupName(ref) {
+			return templateSegment{}, fmt.Errorf("invalid group name in %s", raw)
+		}
+		segment.name = ref
+	}
+
+	for rest != "" {
+		var modifier string
+		if strings.HasPrefix(rest, "-") {
+			modifier, rest = rest, ""
+		} else {
+			modifier, rest, _ = strings.Cut(rest, ":")
+		}
+
+		switch {
+		case strings.HasPrefix(modifier, "-"):
+			segment.fallback = modifier[1:]
+			segment.hasFallback = true
+		case len(modifier) > 1 && modifier[0] == '0':
+			width, err := strconv.Atoi(modifier)
+			if err != nil || width < 1 {
+				return templateSegment{}, fmt.Errorf("invalid padding %q in %s", modifier, raw)
+			}
+			segment.pad = width
+		default:
+			style, err := casing.ParseStyle(modifier)
+			if err != nil {
+				return templateSegment{}, fmt.Errorf("unknown modifier %q in %s (use a case style, a width like 03, or -default)", modifier, raw)
+			}
+			if segment.style != "" {
+				return templateSegment{}, fmt.Errorf("multiple case modifiers in %s", raw)
+			}
+			segment.style = style
+		}
+	}
+
+	return segment, nil
+}
+
+// validGroupName mirrors RE2's rules for (?P<name>...) group names.
+func validGroupName(name string) bool {
+	for _, r := range name {
+		if r != '_' && !('0' <= r && r <= '9') && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') {
+			return false
+		}
+	}
+	return name != ""
+}
+
 // render produces the output string for a given set of submatches. The slice must contain the
-// full match at index 0 followed by capture groups. Missing groups (e.g., optional matches)
-// expand to empty strings. Referencing a group index beyond the available matches returns an error.
-func (t template) render(submatches []string) (string, error) {
+// full match at index 0 followed by capture groups; matched reports, per index, whether the
+// group participated in the match. Missing groups expand to their default, or to empty strings
+// when none is given. Referencing a group index beyond the available matches returns an error.
+func (t template) render(submatches []string, matched []bool) (string, error) {
 	var builder strings.Builder
 
 	for _, segment := range t.segments {
@@ -102,8 +201,35 @@ func (t template) render(submatches []string) (string, error) {
 			return "", ErrUndefinedPlaceholder{Index: segment.group}
 		}
 
-		builder.WriteString(submatches[segment.group])
+		value := submatches[segment.group]
+		if segment.hasFallback && segment.group < len(matched) && !matched[segment.group] {
+			value = segment.fallback
+		}
+		if segment.style != "" {
+			value = casing.Convert(value, segment.style)
+		}
+		if segment.pad > 0 {
+			value = padNumber(value, segment.pad)
+		}
+		builder.WriteString(value)
 	}
 
 	return builder.String(), nil
 }
+
+// padNumber left-pads an all-digit value with zeros to width runes. Other values, including
+// empty ones, are returned unchanged.
+func padNumber(value string, width int) string {
+	if value == "" {
+		return value
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return value
+		}
+	}
+	if missing := width - utf8.RuneCountInString(value); missing > 0 {
+		return strings.Repeat("0", missing) + value
+	}
+	return value
+}
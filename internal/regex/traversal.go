@@ -52,16 +52,9 @@ func TraverseCandidates(ctx context.Context, req *Request, fn func(Candidate) er
 			stem := name
 			ext := ""
 			if !isDir {
-				if dot := strings.IndexRune(name, '.'); dot > 0 {
-					ext = name[dot:]
-					stem = name[:dot]
-				}
-
-				if len(extensions) > 0 {
-					lower := strings.ToLower(ext)
-					if _, ok := extensions[lower]; !ok {
-						return nil
-					}
+				stem, ext = req.ExtensionModel.Split(name)
+				if !req.ExtensionModel.Allows(name, extensions) {
+					return nil
 				}
 			}
 
@@ -70,6 +63,10 @@ func TraverseCandidates(ctx context.Context, req *Request, fn func(Candidate) er
 				return nil
 			}
 
+			if !req.NameFilter.Allows(rel, isDir) || (!isDir && req.Sidecars.IsFollower(rel)) {
+				return nil
+			}
+
 			candidate := Candidate{
 				RelativePath: rel,
 				OriginalPath: filepath.Join(req.WorkingDir, relPath),
@@ -2,7 +2,6 @@ package remove
 
 import (
 	"context"
-	"errors"
 	"os"
 	"path/filepath"
 	"sort"
@@ -24,21 +23,9 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 
 	done := make([]history.Operation, 0, len(planned))
 
-	revert := func() error {
-		for i := len(done) - 1; i >= 0; i-- {
-			op := done[i]
-			source := filepath.Join(req.WorkingDir, op.To)
-			destination := filepath.Join(req.WorkingDir, op.From)
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
-		}
-		return nil
-	}
-
 	for _, op := range planned {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -50,7 +37,7 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 		}
 
 		if err := os.Rename(from, to); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -60,6 +47,19 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 		})
 	}
 
+	followers, err := summary.Sidecars.Apply(req.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, followers...)
+	edits, err := summary.References.Apply(req.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, edits...)
+
 	if len(done) == 0 {
 		return entry, nil
 	}
@@ -79,12 +79,21 @@ func Apply(ctx context.Context, req *Request, planned []PlannedOperation, summar
 		"changed":         summary.ChangedCount,
 		"totalCandidates": summary.TotalCandidates,
 	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		entry.Metadata["nameFilter"] = nameFilter
+	}
+	if req.Modes.Active() {
+		entry.Metadata["modes"] = req.Modes.Labels()
+	}
+	if len(summary.OutOfBounds) > 0 {
+		entry.Metadata["outOfBounds"] = append([]string(nil), summary.OutOfBounds...)
+	}
 	if len(summary.Empties) > 0 {
 		entry.Metadata["empties"] = append([]string(nil), summary.Empties...)
 	}
 
 	if err := history.Append(req.WorkingDir, entry); err != nil {
-		_ = revert()
+		_ = history.Rollback(req.WorkingDir, done)
 		return history.Entry{}, err
 	}
 
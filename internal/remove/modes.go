@@ -0,0 +1,343 @@
+package remove
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/insert"
+)
+
+// Range removes the runes between two insert-style positions (`^`, `$`, `N`, `N$`) of the stem.
+type Range struct {
+	Start string
+	End   string
+}
+
+// String renders the range as it was written on the command line.
+func (r Range) String() string {
+	return r.Start + ":" + r.End
+}
+
+// ParseRange parses a START:END pair of position tokens. Both positions are validated against a
+// generous stem so syntax errors surface before traversal.
+func ParseRange(value string) (Range, error) {
+	start, end, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok || start == "" || end == "" {
+		return Range{}, fmt.Errorf("invalid range %q (use START:END, e.g. 3:8 or 4$:$)", value)
+	}
+	for _, token := range []string{start, end} {
+		if _, err := insert.ResolvePosition(token, 1<<20); err != nil {
+			return Range{}, fmt.Errorf("invalid range %q: %w", value, err)
+		}
+	}
+	return Range{Start: start, End: end}, nil
+}
+
+// Class names a set of characters removed from the stem.
+type Class string
+
+const (
+	ClassDigits   Class = "digits"
+	ClassLetters  Class = "letters"
+	ClassSpaces   Class = "spaces"
+	ClassPunct    Class = "punct"
+	ClassNonASCII Class = "nonascii"
+)
+
+// Classes lists every supported character class in CLI display order.
+var Classes = []Class{ClassDigits, ClassLetters, ClassSpaces, ClassPunct, ClassNonASCII}
+
+// ParseClass validates a --class value.
+func ParseClass(value string) (Class, error) {
+	normalized := Class(strings.ToLower(strings.TrimSpace(value)))
+	for _, class := range Classes {
+		if class == normalized {
+			return class, nil
+		}
+	}
+	names := make([]string, len(Classes))
+	for i, class := range Classes {
+		names[i] = string(class)
+	}
+	return "", fmt.Errorf("unsupported character class %q (use %s)", value, strings.Join(names, ", "))
+}
+
+func (c Class) matches(r rune) bool {
+	switch c {
+	case ClassDigits:
+		return unicode.IsDigit(r)
+	case ClassLetters:
+		return unicode.IsLetter(r)
+	case ClassSpaces:
+		return unicode.IsSpace(r)
+	case ClassPunct:
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	case ClassNonASCII:
+		return r > unicode.MaxASCII
+	default:
+		return false
+	}
+}
+
+// DefaultBrackets are the delimiter pairs removed by a bare --bracketed flag.
+const DefaultBrackets = "()[]{}"
+
+// ParseBrackets validates a string of opening/closing delimiter pairs such as "[]()".
+func ParseBrackets(value string) (string, error) {
+	runes := []rune(value)
+	if len(runes) == 0 || len(runes)%2 != 0 {
+		return "", fmt.Errorf("invalid bracket pairs %q (list opening and closing characters, e.g. \"[]()\")", value)
+	}
+	for i := 0; i < len(runes); i += 2 {
+		if runes[i] == runes[i+1] {
+			return "", fmt.Errorf("invalid bracket pair %q: opening and closing characters must differ", string(runes[i:i+2]))
+		}
+	}
+	return value, nil
+}
+
+// Modes selects positional, delimiter, and character-class removals that complement literal
+// tokens. They act on the stem, so a file's extension is kept; directories use their full name.
+type Modes struct {
+	// Ranges remove rune spans resolved against the original stem.
+	Ranges []Range
+	// First and Last remove that many runes from the start or end of the original stem.
+	First int
+	Last  int
+	// Brackets lists opening/closing pairs whose balanced segments are removed, e.g. "[1080p]".
+	Brackets string
+	// Classes remove every rune of the given kinds.
+	Classes []Class
+	// TrimSeparators trims spaces, underscores, hyphens, and dots from the ends of the stem and
+	// collapses repeats of the same separator left behind by other removals.
+	TrimSeparators bool
+}
+
+// Active reports whether any mode is selected.
+func (m Modes) Active() bool {
+	return len(m.Ranges) > 0 || m.First > 0 || m.Last > 0 || m.Brackets != "" || len(m.Classes) > 0 || m.TrimSeparators
+}
+
+// Labels names each selected mode the way it is keyed in match counts and ledger metadata.
+func (m Modes) Labels() []string {
+	labels := make([]string, 0)
+	for _, r := range m.Ranges {
+		labels = append(labels, "range "+r.String())
+	}
+	if m.First > 0 {
+		labels = append(labels, fmt.Sprintf("first %d", m.First))
+	}
+	if m.Last > 0 {
+		labels = append(labels, fmt.Sprintf("last %d", m.Last))
+	}
+	if m.Brackets != "" {
+		labels = append(labels, "bracketed "+m.Brackets)
+	}
+	for _, class := range m.Classes {
+		labels = append(labels, "class "+string(class))
+	}
+	if m.TrimSeparators {
+		labels = append(labels, "trim separators")
+	}
+	return labels
+}
+
+// ErrRangeOutOfBounds reports a range or count that does not fit a candidate's stem.
+type ErrRangeOutOfBounds struct {
+	Path  string
+	Range string
+	Runes int
+}
+
+func (e ErrRangeOutOfBounds) Error() string {
+	return fmt.Sprintf("%s: range %s out of bounds for %d-character stem", e.Path, e.Range, e.Runes)
+}
+
+// ApplyRemovals runs positional removals against the original stem, then literal tokens against
+// the base name (as ApplyTokens does), then bracketed segments, character classes, and separator
+// trimming against the resulting stem. Stems end before the extension recognised by extensions,
+// so compound extensions such as .tar.gz stay whole. Matches counts removed occurrences per token
+// or mode label. A range that does not fit the stem returns ErrRangeOutOfBounds.
+func ApplyRemovals(candidate Candidate, tokens []string, modes Modes, extensions fileext.Model) (Result, error) {
+	matches := make(map[string]int)
+	stem, ext := splitStem(candidate, extensions)
+
+	stem, err := removePositions(candidate, stem, modes, matches)
+	if err != nil {
+		return Result{}, err
+	}
+
+	current := stem + ext
+	if len(tokens) > 0 {
+		tokenResult := ApplyTokens(Candidate{BaseName: current}, tokens)
+		current = tokenResult.ProposedName
+		for token, count := range tokenResult.Matches {
+			matches[token] += count
+		}
+	}
+
+	if modes.Brackets != "" || len(modes.Classes) > 0 || modes.TrimSeparators {
+		stem, ext = splitStem(Candidate{BaseName: current, IsDir: candidate.IsDir}, extensions)
+		if modes.Brackets != "" {
+			var count int
+			stem, count = removeBracketed(stem, modes.Brackets)
+			if count > 0 {
+				matches["bracketed "+modes.Brackets] += count
+			}
+		}
+		for _, class := range modes.Classes {
+			var count int
+			stem, count = removeClass(stem, class)
+			if count > 0 {
+				matches["class "+string(class)] += count
+			}
+		}
+		if modes.TrimSeparators {
+			if trimmed := trimSeparators(stem); trimmed != stem {
+				stem = trimmed
+				matches["trim separators"]++
+			}
+		}
+		current = stem + ext
+	}
+
+	return Result{
+		Candidate:    candidate,
+		ProposedName: current,
+		Matches:      matches,
+		Changed:      current != candidate.BaseName,
+	}, nil
+}
+
+// splitStem separates a file's extension as the model recognises it; directories and dotfiles
+// keep their full name.
+func splitStem(candidate Candidate, extensions fileext.Model) (string, string) {
+	if candidate.IsDir {
+		return candidate.BaseName, ""
+	}
+	return extensions.Split(candidate.BaseName)
+}
+
+func removePositions(candidate Candidate, stem string, modes Modes, matches map[string]int) (string, error) {
+	if len(modes.Ranges) == 0 && modes.First == 0 && modes.Last == 0 {
+		return stem, nil
+	}
+
+	runes := []rune(stem)
+	removed := make([]bool, len(runes))
+	mark := func(label string, start, end int) {
+		if end > start {
+			for i := start; i < end; i++ {
+				removed[i] = true
+			}
+			matches[label]++
+		}
+	}
+
+	for _, r := range modes.Ranges {
+		start, startErr := insert.ResolvePosition(r.Start, len(runes))
+		end, endErr := insert.ResolvePosition(r.End, len(runes))
+		if startErr != nil || endErr != nil || start.Index > end.Index {
+			return "", ErrRangeOutOfBounds{Path: candidate.RelativePath, Range: r.String(), Runes: len(runes)}
+		}
+		mark("range "+r.String(), start.Index, end.Index)
+	}
+	if modes.First > 0 {
+		if modes.First > len(runes) {
+			return "", ErrRangeOutOfBounds{Path: candidate.RelativePath, Range: fmt.Sprintf("first %d", modes.First), Runes: len(runes)}
+		}
+		mark(fmt.Sprintf("first %d", modes.First), 0, modes.First)
+	}
+	if modes.Last > 0 {
+		if modes.Last > len(runes) {
+			return "", ErrRangeOutOfBounds{Path: candidate.RelativePath, Range: fmt.Sprintf("last %d", modes.Last), Runes: len(runes)}
+		}
+		mark(fmt.Sprintf("last %d", modes.Last), len(runes)-modes.Last, len(runes))
+	}
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if !removed[i] {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String(), nil
+}
+
+// removeBracketed deletes balanced, possibly nested segments delimited by the given pairs and
+// returns the number of outermost segments removed. Unbalanced delimiters are left in place.
+func removeBracketed(value, pairs string) (string, int) {
+	closers := make(map[rune]rune)
+	openers := make(map[rune]bool)
+	pairRunes := []rune(pairs)
+	for i := 0; i+1 < len(pairRunes); i += 2 {
+		openers[pairRunes[i]] = true
+		closers[pairRunes[i+1]] = pairRunes[i]
+	}
+
+	runes := []rune(value)
+	removed := make([]bool, len(runes))
+	type open struct {
+		char  rune
+		index int
+	}
+	stack := make([]open, 0)
+	count := 0
+	for i, r := range runes {
+		if openers[r] {
+			stack = append(stack, open{char: r, index: i})
+			continue
+		}
+		opener, ok := closers[r]
+		if !ok || len(stack) == 0 || stack[len(stack)-1].char != opener {
+			continue
+		}
+		start := stack[len(stack)-1].index
+		stack = stack[:len(stack)-1]
+		if len(stack) == 0 {
+			for j := start; j <= i; j++ {
+				removed[j] = true
+			}
+			count++
+		}
+	}
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if !removed[i] {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String(), count
+}
+
+func removeClass(value string, class Class) (string, int) {
+	count := 0
+	result := strings.Map(func(r rune) rune {
+		if class.matches(r) {
+			count++
+			return -1
+		}
+		return r
+	}, value)
+	return result, count
+}
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '_' || r == '-' || r == '.'
+}
+
+func trimSeparators(value string) string {
+	var builder strings.Builder
+	var previous rune
+	for _, r := range value {
+		if isSeparator(r) && r == previous {
+			continue
+		}
+		builder.WriteRune(r)
+		previous = r
+	}
+	return strings.TrimFunc(builder.String(), isSeparator)
+}
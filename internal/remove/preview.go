@@ -2,10 +2,15 @@ package remove
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // PlannedOperation represents a rename that will be executed during apply.
@@ -26,7 +31,16 @@ func Preview(ctx context.Context, req *Request, parsed ParseArgsResult, out io.W
 	plannedTargets := make(map[string]string)
 
 	err := Traverse(ctx, req, func(candidate Candidate) error {
-		res := ApplyTokens(candidate, parsed.Tokens)
+		res, err := ApplyRemovals(candidate, parsed.Tokens, req.Modes, req.ExtensionModel)
+		var bounds ErrRangeOutOfBounds
+		if errors.As(err, &bounds) {
+			summary.TotalCandidates++
+			summary.AddOutOfBounds(bounds.Error())
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 		summary.RecordCandidate(res)
 
 		if !res.Changed {
@@ -54,6 +68,16 @@ func Preview(ctx context.Context, req *Request, parsed ParseArgsResult, out io.W
 			return nil
 		}
 
+		// A stem reduced to nothing or to whitespace (" .txt") would leave an unusable name.
+		if _, ext := splitStem(candidate, req.ExtensionModel); strings.TrimSpace(strings.TrimSuffix(res.ProposedName, ext)) == "" {
+			summary.AddConflict(ConflictDetail{
+				OriginalPath: candidate.RelativePath,
+				ProposedPath: targetRelative,
+				Reason:       "stem would be empty",
+			})
+			return nil
+		}
+
 		if existing, ok := plannedTargets[targetRelative]; ok && existing != candidate.RelativePath {
 			summary.AddConflict(ConflictDetail{
 				OriginalPath: candidate.RelativePath,
@@ -63,6 +87,15 @@ func Preview(ctx context.Context, req *Request, parsed ParseArgsResult, out io.W
 			return nil
 		}
 
+		if violation, ok := pathlimit.Check(req.WorkingDir, targetRelative); !ok {
+			summary.AddConflict(ConflictDetail{
+				OriginalPath: candidate.RelativePath,
+				ProposedPath: targetRelative,
+				Reason:       violation.Reason(),
+			})
+			return nil
+		}
+
 		targetAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(targetRelative))
 		if info, err := os.Stat(targetAbsolute); err == nil {
 			if candidate.OriginalPath != targetAbsolute {
@@ -102,5 +135,21 @@ func Preview(ctx context.Context, req *Request, parsed ParseArgsResult, out io.W
 		return Summary{}, nil, err
 	}
 
+	renames := make([]sidecar.Rename, 0, len(planned))
+	for _, op := range planned {
+		renames = append(renames, sidecar.Rename{From: op.Result.Candidate.RelativePath, To: op.TargetRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(ConflictDetail{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return Summary{}, nil, err
+	}
+	summary.References = refs
+	summary.Sidecars.WritePreview(out)
+	summary.References.WritePreview(out)
+
 	return summary, planned, nil
 }
@@ -3,7 +3,11 @@ package remove
 import (
 	"fmt"
 
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
 	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // Request encapsulates the options required for remove operations.
@@ -15,10 +19,16 @@ type Request struct {
 	Recursive          bool
 	IncludeHidden      bool
 	Extensions         []string
+	ExtensionModel     fileext.Model
+	NameFilter         filters.NameFilter
+	Sidecars           *sidecar.Groups
+	References         *references.Rewriter
+	// Modes adds positional, bracketed, and character-class removals alongside Tokens.
+	Modes Modes
 }
 
-// FromListing builds a Request from the shared listing scope plus ordered tokens.
-func FromListing(scope *listing.ListingRequest, tokens []string) (*Request, error) {
+// FromListing builds a Request from the shared listing scope plus ordered tokens and modes.
+func FromListing(scope *listing.ListingRequest, tokens []string, modes Modes) (*Request, error) {
 	if scope == nil {
 		return nil, fmt.Errorf("scope must not be nil")
 	}
@@ -28,7 +38,12 @@ func FromListing(scope *listing.ListingRequest, tokens []string) (*Request, erro
 		Recursive:          scope.Recursive,
 		IncludeHidden:      scope.IncludeHidden,
 		Extensions:         append([]string(nil), scope.Extensions...),
+		ExtensionModel:     scope.ExtensionModel,
+		NameFilter:         scope.NameFilter,
+		Sidecars:           scope.Sidecars,
+		References:         scope.References,
 		Tokens:             append([]string(nil), tokens...),
+		Modes:              modes,
 	}
 	if err := req.Validate(); err != nil {
 		return nil, err
@@ -41,8 +56,8 @@ func (r *Request) Validate() error {
 	if r.WorkingDir == "" {
 		return fmt.Errorf("working directory must be provided")
 	}
-	if len(r.Tokens) == 0 {
-		return fmt.Errorf("at least one removal token is required")
+	if len(r.Tokens) == 0 && !r.Modes.Active() {
+		return fmt.Errorf("at least one removal token or mode is required")
 	}
 	for i, token := range r.Tokens {
 		if token == "" {
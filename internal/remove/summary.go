@@ -1,6 +1,11 @@
 package remove
 
-import "sort"
+import (
+	"sort"
+
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
 
 // ConflictDetail describes a rename that cannot proceed.
 type ConflictDetail struct {
@@ -17,6 +22,12 @@ type Summary struct {
 	Conflicts       []ConflictDetail
 	Empties         []string
 	Duplicates      []string
+	// OutOfBounds lists candidates skipped because a range did not fit their stem.
+	OutOfBounds []string
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
 }
 
 // NewSummary constructs an initialized summary instance.
@@ -48,6 +59,11 @@ func (s *Summary) AddEmpty(path string) {
 	s.Empties = append(s.Empties, path)
 }
 
+// AddOutOfBounds records a candidate skipped because a positional removal did not fit.
+func (s *Summary) AddOutOfBounds(reason string) {
+	s.OutOfBounds = append(s.OutOfBounds, reason)
+}
+
 // AddDuplicate stores duplicate tokens captured during parsing.
 func (s *Summary) AddDuplicate(token string) {
 	if token == "" {
@@ -47,12 +47,9 @@ func Traverse(ctx context.Context, req *Request, fn func(Candidate) error) error
 			}
 
 			isDir := entry.IsDir()
-			ext := strings.ToLower(filepath.Ext(entry.Name()))
 
-			if !isDir && len(extensions) > 0 {
-				if _, ok := extensions[ext]; !ok {
-					return nil
-				}
+			if !isDir && !req.ExtensionModel.Allows(entry.Name(), extensions) {
+				return nil
 			}
 
 			if isDir && !req.IncludeDirectories {
@@ -71,6 +68,10 @@ func Traverse(ctx context.Context, req *Request, fn func(Candidate) error) error
 				return nil
 			}
 
+			if !req.NameFilter.Allows(candidate.RelativePath, isDir) || (!isDir && req.Sidecars.IsFollower(candidate.RelativePath)) {
+				return nil
+			}
+
 			return fn(candidate)
 		},
 	)
@@ -2,7 +2,6 @@ package replace
 
 import (
 	"context"
-	"errors"
 	"os"
 	"path/filepath"
 	"sort"
@@ -24,21 +23,9 @@ func Apply(ctx context.Context, req *ReplaceRequest, planned []PlannedOperation,
 
 	done := make([]history.Operation, 0, len(planned))
 
-	revert := func() error {
-		for i := len(done) - 1; i >= 0; i-- {
-			op := done[i]
-			source := filepath.Join(req.WorkingDir, op.To)
-			destination := filepath.Join(req.WorkingDir, op.From)
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
-		}
-		return nil
-	}
-
 	for _, op := range planned {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -50,7 +37,7 @@ func Apply(ctx context.Context, req *ReplaceRequest, planned []PlannedOperation,
 		}
 
 		if err := os.Rename(from, to); err != nil {
-			_ = revert()
+			_ = history.Rollback(req.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -60,6 +47,19 @@ func Apply(ctx context.Context, req *ReplaceRequest, planned []PlannedOperation,
 		})
 	}
 
+	followers, err := summary.Sidecars.Apply(req.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, followers...)
+	edits, err := summary.References.Apply(req.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(req.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, edits...)
+
 	if len(done) == 0 {
 		return entry, nil
 	}
@@ -75,10 +75,19 @@ func Apply(ctx context.Context, req *ReplaceRequest, planned []PlannedOperation,
 		"changed":         summary.ChangedCount,
 		"totalCandidates": summary.TotalCandidates,
 	}
+	entry.Metadata["options"] = map[string]any{
+		"ignoreCase": req.Options.IgnoreCase,
+		"wholeWord":  req.Options.WholeWord,
+		"occurrence": string(req.Options.Occurrence),
+		"stemOnly":   req.Options.StemOnly,
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		entry.Metadata["nameFilter"] = nameFilter
+	}
 
 	if err := history.Append(req.WorkingDir, entry); err != nil {
 		// Attempt to undo renames if ledger append fails.
-		_ = revert()
+		_ = history.Rollback(req.WorkingDir, done)
 		return history.Entry{}, err
 	}
 
@@ -1,6 +1,14 @@
 package replace
 
-import "strings"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+)
 
 // Result captures the outcome of applying patterns to a candidate name.
 type Result struct {
@@ -10,23 +18,84 @@ type Result struct {
 	Changed      bool
 }
 
-// ApplyPatterns replaces every occurrence of the provided patterns within the candidate's base name.
-func ApplyPatterns(candidate Candidate, patterns []string, replacement string) Result {
+// Occurrence limits which matches of each pattern are replaced.
+type Occurrence string
+
+const (
+	// OccurrenceAll replaces every match (default).
+	OccurrenceAll Occurrence = "all"
+	// OccurrenceFirst replaces only the leftmost match.
+	OccurrenceFirst Occurrence = "first"
+	// OccurrenceLast replaces only the rightmost match.
+	OccurrenceLast Occurrence = "last"
+)
+
+// ParseOccurrence validates an --occurrence value; empty selects all.
+func ParseOccurrence(value string) (Occurrence, error) {
+	switch occurrence := Occurrence(strings.ToLower(strings.TrimSpace(value))); occurrence {
+	case "":
+		return OccurrenceAll, nil
+	case OccurrenceAll, OccurrenceFirst, OccurrenceLast:
+		return occurrence, nil
+	default:
+		return "", fmt.Errorf("unsupported occurrence %q (use all, first, or last)", value)
+	}
+}
+
+// Options refines how patterns are matched. The zero value reproduces the original behaviour:
+// case-sensitive substring matches, every occurrence, across the whole base name.
+type Options struct {
+	// IgnoreCase matches patterns regardless of letter case (Copy, copy, COPY).
+	IgnoreCase bool
+	// WholeWord only accepts matches not flanked by letters or digits, so "copy" matches in
+	// "a copy" and "a_copy" but not in "copyright".
+	WholeWord bool
+	// Occurrence limits each pattern to its first or last match.
+	Occurrence Occurrence
+	// StemOnly leaves a file's extension untouched.
+	StemOnly bool
+}
+
+// ApplyPatterns replaces occurrences of the provided patterns within the candidate's base name.
+// Patterns apply in order, each to the result of the previous one, and Matches counts the
+// occurrences each pattern actually replaced. With StemOnly the extension is the one extensions
+// recognises, so compound extensions such as .tar.gz are left whole.
+func ApplyPatterns(candidate Candidate, patterns []string, replacement string, opts Options, extensions fileext.Model) Result {
 	current := candidate.BaseName
+	ext := ""
+	if opts.StemOnly && !candidate.IsDir {
+		current, ext = extensions.Split(current)
+	}
 	matches := make(map[string]int, len(patterns))
 
 	for _, pattern := range patterns {
 		if pattern == "" {
 			continue
 		}
-		count := strings.Count(current, pattern)
-		if count == 0 {
+		spans := findOccurrences(current, pattern, opts)
+		if len(spans) == 0 {
 			continue
 		}
-		current = strings.ReplaceAll(current, pattern, replacement)
-		matches[pattern] += count
+		switch opts.Occurrence {
+		case OccurrenceFirst:
+			spans = spans[:1]
+		case OccurrenceLast:
+			spans = spans[len(spans)-1:]
+		}
+
+		var builder strings.Builder
+		last := 0
+		for _, span := range spans {
+			builder.WriteString(current[last:span[0]])
+			builder.WriteString(replacement)
+			last = span[1]
+		}
+		builder.WriteString(current[last:])
+		current = builder.String()
+		matches[pattern] += len(spans)
 	}
 
+	current += ext
 	changed := current != candidate.BaseName
 
 	return Result{
@@ -36,3 +105,64 @@ func ApplyPatterns(candidate Candidate, patterns []string, replacement string) R
 		Changed:      changed,
 	}
 }
+
+// findOccurrences returns the byte spans of non-overlapping matches of pattern in text, scanning
+// left to right. A match rejected by the whole-word check does not consume its text, so a later
+// overlapping match can still qualify.
+func findOccurrences(text, pattern string, opts Options) [][2]int {
+	if !opts.IgnoreCase && !opts.WholeWord {
+		spans := make([][2]int, 0)
+		for offset := 0; ; {
+			index := strings.Index(text[offset:], pattern)
+			if index < 0 {
+				return spans
+			}
+			start := offset + index
+			spans = append(spans, [2]int{start, start + len(pattern)})
+			offset = start + len(pattern)
+		}
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re := regexp.MustCompile(expr)
+
+	spans := make([][2]int, 0)
+	for offset := 0; offset < len(text); {
+		loc := re.FindStringIndex(text[offset:])
+		if loc == nil {
+			break
+		}
+		start, end := offset+loc[0], offset+loc[1]
+		if opts.WholeWord && !atWordBoundary(text, start, end) {
+			_, size := utf8.DecodeRuneInString(text[start:])
+			offset = start + size
+			continue
+		}
+		spans = append(spans, [2]int{start, end})
+		offset = end
+	}
+	return spans
+}
+
+// atWordBoundary reports whether text[start:end] is not directly preceded or followed by a letter
+// or digit. Separators such as spaces, underscores, hyphens, and dots count as boundaries.
+func atWordBoundary(text string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
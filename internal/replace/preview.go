@@ -6,6 +6,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // PlannedOperation represents a rename that will be executed during apply.
@@ -26,7 +29,7 @@ func Preview(ctx context.Context, req *ReplaceRequest, parseResult ParseArgsResu
 	plannedTargets := make(map[string]string) // target rel -> source rel to detect duplicates
 
 	err := TraverseCandidates(ctx, req, func(candidate Candidate) error {
-		res := ApplyPatterns(candidate, parseResult.Patterns, parseResult.Replacement)
+		res := ApplyPatterns(candidate, parseResult.Patterns, parseResult.Replacement, req.Options, req.ExtensionModel)
 		summary.RecordCandidate(res)
 
 		if !res.Changed {
@@ -58,6 +61,15 @@ func Preview(ctx context.Context, req *ReplaceRequest, parseResult ParseArgsResu
 			return nil
 		}
 
+		if violation, ok := pathlimit.Check(req.WorkingDir, targetRelative); !ok {
+			summary.AddConflict(ConflictDetail{
+				OriginalPath: candidate.RelativePath,
+				ProposedPath: targetRelative,
+				Reason:       violation.Reason(),
+			})
+			return nil
+		}
+
 		targetAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(targetRelative))
 		if info, err := os.Stat(targetAbsolute); err == nil {
 			if candidate.OriginalPath != targetAbsolute {
@@ -101,6 +113,22 @@ func Preview(ctx context.Context, req *ReplaceRequest, parseResult ParseArgsResu
 		return Summary{}, nil, err
 	}
 
+	renames := make([]sidecar.Rename, 0, len(planned))
+	for _, op := range planned {
+		renames = append(renames, sidecar.Rename{From: op.Result.Candidate.RelativePath, To: op.TargetRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(ConflictDetail{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return Summary{}, nil, err
+	}
+	summary.References = refs
+	summary.Sidecars.WritePreview(out)
+	summary.References.WritePreview(out)
+
 	if summary.ReplacementWasEmpty(parseResult.Replacement) {
 		if out != nil {
 			fmt.Fprintln(out, "Warning: replacement string is empty; matched patterns will be removed.")
@@ -5,6 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // ReplaceRequest captures all inputs needed to evaluate a replace operation.
@@ -16,6 +21,11 @@ type ReplaceRequest struct {
 	Recursive          bool
 	IncludeHidden      bool
 	Extensions         []string
+	ExtensionModel     fileext.Model
+	NameFilter         filters.NameFilter
+	Sidecars           *sidecar.Groups
+	References         *references.Rewriter
+	Options            Options
 }
 
 // Validate ensures the request is well-formed before preview/apply.
@@ -28,6 +38,10 @@ func (r *ReplaceRequest) Validate() error {
 		return errors.New("at least one pattern is required")
 	}
 
+	if r.Options.Occurrence == "" {
+		r.Options.Occurrence = OccurrenceAll
+	}
+
 	if r.Replacement == "" {
 		// Allow empty replacement but make sure caller has surfaced warnings elsewhere.
 		// No error returned; preview will message accordingly.
@@ -1,6 +1,11 @@
 package replace
 
-import "sort"
+import (
+	"sort"
+
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
 
 // ConflictDetail describes a rename that could not be applied.
 type ConflictDetail struct {
@@ -17,6 +22,11 @@ type Summary struct {
 	Conflicts        []ConflictDetail
 	Duplicates       []string
 	EmptyReplacement bool
+
+	// Sidecars holds the companion renames derived from the planned operations.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the planned renames.
+	References references.Plan
 }
 
 // NewSummary constructs an initialized summary.
@@ -47,12 +47,9 @@ func TraverseCandidates(ctx context.Context, req *ReplaceRequest, fn func(Candid
 			}
 
 			isDir := entry.IsDir()
-			ext := strings.ToLower(filepath.Ext(entry.Name()))
 
-			if !isDir && len(extensions) > 0 {
-				if _, ok := extensions[ext]; !ok {
-					return nil
-				}
+			if !isDir && !req.ExtensionModel.Allows(entry.Name(), extensions) {
+				return nil
 			}
 
 			candidate := Candidate{
@@ -67,6 +64,10 @@ func TraverseCandidates(ctx context.Context, req *ReplaceRequest, fn func(Candid
 				return nil
 			}
 
+			if !req.NameFilter.Allows(candidate.RelativePath, isDir) || (!isDir && req.Sidecars.IsFollower(candidate.RelativePath)) {
+				return nil
+			}
+
 			return fn(candidate)
 		},
 	)
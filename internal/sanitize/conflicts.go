@@ -0,0 +1,101 @@
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+)
+
+// maxSuffixAttempts bounds the search for a free deduplication suffix.
+const maxSuffixAttempts = 10000
+
+// errSuffixesExhausted signals that every suffix up to maxSuffixAttempts is already taken.
+var errSuffixesExhausted = errors.New("suffixes_exhausted")
+
+// targetAllocator hands out collision-free target paths. Candidates are visited in lexical walk
+// order, so the first claimant keeps the sanitized name and later ones receive _1, _2, … suffixes,
+// which makes the outcome deterministic across runs.
+type targetAllocator struct {
+	workingDir      string
+	caseInsensitive bool
+	extensions      fileext.Model
+	planned         map[string]string
+}
+
+func newTargetAllocator(workingDir string, caseInsensitive bool, extensions fileext.Model) *targetAllocator {
+	return &targetAllocator{
+		workingDir:      workingDir,
+		caseInsensitive: caseInsensitive,
+		extensions:      extensions,
+		planned:         make(map[string]string),
+	}
+}
+
+func (a *targetAllocator) key(relative string) string {
+	if a.caseInsensitive {
+		return strings.ToLower(relative)
+	}
+	return relative
+}
+
+// reserve records a path that keeps its current name so later targets cannot claim it.
+func (a *targetAllocator) reserve(relative string) {
+	a.planned[a.key(relative)] = relative
+}
+
+// allocate returns the first free target derived from targetRel, and whether a suffix was needed.
+// Suffixes go before the whole extension, so archive.tar.gz becomes archive_1.tar.gz.
+func (a *targetAllocator) allocate(candidateRel, targetRel string, isDir bool) (string, bool, error) {
+	dir := filepath.ToSlash(filepath.Dir(filepath.FromSlash(targetRel)))
+	name := filepath.Base(filepath.FromSlash(targetRel))
+	stem, ext := name, ""
+	if !isDir {
+		stem, ext = a.extensions.Split(name)
+	}
+
+	originalAbs := filepath.Join(a.workingDir, filepath.FromSlash(candidateRel))
+	for attempt := 0; attempt < maxSuffixAttempts; attempt++ {
+		proposed := targetRel
+		if attempt > 0 {
+			proposed = fmt.Sprintf("%s_%d%s", stem, attempt, ext)
+			if dir != "." {
+				proposed = dir + "/" + proposed
+			}
+		}
+
+		free, err := a.available(candidateRel, proposed, originalAbs)
+		if err != nil {
+			return "", false, err
+		}
+		if free {
+			a.planned[a.key(proposed)] = candidateRel
+			return proposed, attempt > 0, nil
+		}
+	}
+
+	return "", false, errSuffixesExhausted
+}
+
+func (a *targetAllocator) available(candidateRel, proposedRel, originalAbs string) (bool, error) {
+	if existing, ok := a.planned[a.key(proposedRel)]; ok && existing != candidateRel {
+		return false, nil
+	}
+
+	info, err := os.Stat(filepath.Join(a.workingDir, filepath.FromSlash(proposedRel)))
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	origInfo, err := os.Stat(originalAbs)
+	if err != nil {
+		return false, err
+	}
+	// On case-insensitive filesystems the target may resolve to the source itself.
+	return os.SameFile(info, origInfo), nil
+}
@@ -0,0 +1,3 @@
+// Package sanitize implements the `renamer sanitize` engine, rewriting names that are illegal or
+// unsafe on a target platform profile and resolving the resulting collisions deterministically.
+package sanitize
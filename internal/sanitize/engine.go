@@ -0,0 +1,188 @@
+package sanitize
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation captures a filesystem rename to be applied.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	IsDir            bool
+	Depth            int
+}
+
+type candidate struct {
+	relative string
+	name     string
+	isDir    bool
+	depth    int
+}
+
+// BuildPlan enumerates candidates, sanitizes their names, and resolves collisions with suffixes.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("sanitize request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	allocator := newTargetAllocator(req.WorkingDir, req.Profile.CaseInsensitive(), req.ExtensionModel)
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	candidates := make([]candidate, 0)
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) || (!isDir && req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			candidates = append(candidates, candidate{relative: relative, name: name, isDir: isDir, depth: depth})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Reserve every name that survives unchanged before allocating targets, so a sanitized name
+	// never claims a slot that an untouched sibling occupies under case-insensitive comparison.
+	proposals := make([]string, len(candidates))
+	reasonSets := make([][]Reason, len(candidates))
+	for i, c := range candidates {
+		proposals[i], reasonSets[i] = Sanitize(c.name, req.Profile, req.Replacement)
+		if proposals[i] == c.name {
+			allocator.reserve(c.relative)
+		}
+	}
+
+	for i, c := range candidates {
+		preview := PreviewEntry{
+			OriginalPath: c.relative,
+			ProposedPath: c.relative,
+			Status:       StatusNoChange,
+			Reasons:      reasonSets[i],
+		}
+
+		if proposals[i] == c.name {
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		target := joinRelative(c.relative, proposals[i])
+		proposedRelative, deduplicated, err := allocator.allocate(c.relative, target, c.isDir)
+		if errors.Is(err, errSuffixesExhausted) {
+			preview.ProposedPath = target
+			preview.Status = StatusSkipped
+			summary.AddConflict(Conflict{
+				OriginalPath: c.relative,
+				ProposedPath: target,
+				Reason:       err.Error(),
+			})
+			summary.RecordEntry(preview)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+			preview.ProposedPath = proposedRelative
+			preview.Status = StatusSkipped
+			summary.AddConflict(Conflict{
+				OriginalPath: c.relative,
+				ProposedPath: proposedRelative,
+				Reason:       violation.Reason(),
+			})
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		preview.ProposedPath = proposedRelative
+		preview.Status = StatusChanged
+		preview.Deduplicated = deduplicated
+		operations = append(operations, PlannedOperation{
+			OriginalRelative: c.relative,
+			OriginalAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(c.relative)),
+			ProposedRelative: proposedRelative,
+			ProposedAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative)),
+			IsDir:            c.isDir,
+			Depth:            c.depth,
+		})
+		summary.RecordEntry(preview)
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
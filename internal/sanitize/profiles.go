@@ -0,0 +1,180 @@
+package sanitize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Profile identifies the platform whose naming rules are enforced.
+type Profile string
+
+const (
+	// ProfilePOSIX strips control characters only; '/' can never appear in an existing name.
+	ProfilePOSIX Profile = "posix"
+	// ProfileWindows enforces NTFS/Win32 rules: \ : * ? " < > |, trailing dots and spaces, and
+	// reserved device names such as CON or LPT1.
+	ProfileWindows Profile = "windows"
+	// ProfilePortable extends the Windows rules for SMB shares and cloud sync clients: leading
+	// spaces are trimmed and # and % are replaced.
+	ProfilePortable Profile = "portable"
+	// ProfileS3 restricts names to the S3 safe object-key character set [A-Za-z0-9!-_.*'()].
+	ProfileS3 Profile = "s3"
+)
+
+// Profiles lists every supported profile in CLI display order.
+var Profiles = []Profile{ProfilePOSIX, ProfileWindows, ProfilePortable, ProfileS3}
+
+// ParseProfile validates a user-supplied profile token.
+func ParseProfile(value string) (Profile, error) {
+	normalized := Profile(strings.ToLower(strings.TrimSpace(value)))
+	if normalized == "" {
+		return ProfilePortable, nil
+	}
+	for _, profile := range Profiles {
+		if profile == normalized {
+			return profile, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported sanitize profile %q (use posix, windows, portable, or s3)", value)
+}
+
+// CaseInsensitive reports whether the profile's target filesystems compare names case-insensitively.
+func (p Profile) CaseInsensitive() bool {
+	return p != ProfilePOSIX
+}
+
+// Reason identifies a rule that changed a name.
+type Reason string
+
+const (
+	ReasonControl    Reason = "control_characters"
+	ReasonIllegal    Reason = "illegal_characters"
+	ReasonTrailing   Reason = "trailing_dots_or_spaces"
+	ReasonLeading    Reason = "leading_spaces"
+	ReasonReserved   Reason = "reserved_name"
+	ReasonSeparators Reason = "repeated_separators"
+	ReasonEmpty      Reason = "empty_name"
+)
+
+const windowsIllegal = `\/:*?"<>|`
+
+var windowsReserved = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// Sanitize rewrites name according to the profile. Control characters are removed, illegal
+// characters are replaced with replacement (an empty replacement strips them), repeated
+// separators are collapsed, and platform-specific trimming and reserved-name rules are applied.
+// The returned reasons list every rule that fired, in evaluation order.
+func Sanitize(name string, profile Profile, replacement string) (string, []Reason) {
+	reasons := make([]Reason, 0)
+	note := func(reason Reason) {
+		for _, existing := range reasons {
+			if existing == reason {
+				return
+			}
+		}
+		reasons = append(reasons, reason)
+	}
+
+	var builder strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f || unicode.IsControl(r):
+			note(ReasonControl)
+		case isIllegal(r, profile):
+			note(ReasonIllegal)
+			builder.WriteString(replacement)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	current := builder.String()
+
+	if collapsed := collapseSeparators(current, replacement); collapsed != current {
+		note(ReasonSeparators)
+		current = collapsed
+	}
+
+	if profile == ProfilePortable {
+		if trimmed := strings.TrimLeft(current, " "); trimmed != current {
+			note(ReasonLeading)
+			current = trimmed
+		}
+	}
+
+	if profile != ProfilePOSIX {
+		if trimmed := strings.TrimRight(current, ". "); trimmed != current {
+			note(ReasonTrailing)
+			current = trimmed
+		}
+
+		if profile != ProfileS3 {
+			stem, rest := current, ""
+			if dot := strings.IndexByte(current, '.'); dot >= 0 {
+				stem, rest = current[:dot], current[dot:]
+			}
+			if _, reserved := windowsReserved[strings.ToUpper(strings.TrimRight(stem, " "))]; reserved {
+				note(ReasonReserved)
+				current = stem + "_" + rest
+			}
+		}
+	}
+
+	if current == "" || current == "." || current == ".." {
+		note(ReasonEmpty)
+		current = "_"
+	}
+
+	return current, reasons
+}
+
+// validReplacement reports whether every rune of replacement is legal for the profile.
+func validReplacement(replacement string, profile Profile) bool {
+	for _, r := range replacement {
+		if r < 0x20 || r == 0x7f || unicode.IsControl(r) || isIllegal(r, profile) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIllegal(r rune, profile Profile) bool {
+	switch profile {
+	case ProfilePOSIX:
+		return r == '/'
+	case ProfileWindows:
+		return strings.ContainsRune(windowsIllegal, r)
+	case ProfilePortable:
+		return strings.ContainsRune(windowsIllegal, r) || r == '#' || r == '%'
+	case ProfileS3:
+		if r > unicode.MaxASCII {
+			return true
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+		return !strings.ContainsRune("!-_.*'()", r)
+	default:
+		return false
+	}
+}
+
+// collapseSeparators folds runs of the same separator (space, underscore, hyphen, or the
+// replacement string) into a single occurrence.
+func collapseSeparators(value, replacement string) string {
+	separators := []string{" ", "_", "-"}
+	if replacement != "" {
+		separators = append(separators, replacement)
+	}
+	for _, sep := range separators {
+		double := sep + sep
+		for strings.Contains(value, double) {
+			value = strings.ReplaceAll(value, double, sep)
+		}
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package sanitize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Request encapsulates the inputs required to sanitize names.
+type Request struct {
+	WorkingDir      string
+	Profile         Profile
+	Replacement     string
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest constructs a Request from shared listing scope.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{Profile: ProfilePortable, Replacement: "_"}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		Profile:         ProfilePortable,
+		Replacement:     "_",
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode updates dry-run and auto-apply preferences.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetProfile stores the target profile and the string substituted for illegal characters.
+func (r *Request) SetProfile(profile Profile, replacement string) {
+	r.Profile = profile
+	r.Replacement = replacement
+}
+
+// Normalize ensures working directory, profile, and timestamp fields are ready for execution.
+func (r *Request) Normalize() error {
+	profile, err := ParseProfile(string(r.Profile))
+	if err != nil {
+		return err
+	}
+	r.Profile = profile
+
+	// The replacement must itself survive sanitization, otherwise the output would be illegal.
+	if r.Replacement != "" {
+		if !validReplacement(r.Replacement, r.Profile) {
+			return fmt.Errorf("replacement %q is not valid for the %s profile", r.Replacement, r.Profile)
+		}
+	}
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
@@ -2,7 +2,6 @@ package sequence
 
 import (
 	"context"
-	"errors"
 	"os"
 	"path/filepath"
 	"sort"
@@ -50,23 +49,33 @@ func Apply(ctx context.Context, opts Options, plan Plan) (history.Entry, error)
 		return ops[i].depth > ops[j].depth
 	})
 
-	done := make([]history.Operation, 0, len(ops))
-
-	revert := func() error {
-		for i := len(done) - 1; i >= 0; i-- {
-			op := done[i]
-			source := filepath.Join(merged.WorkingDir, filepath.FromSlash(op.To))
-			destination := filepath.Join(merged.WorkingDir, filepath.FromSlash(op.From))
-			if err := os.Rename(source, destination); err != nil && !errors.Is(err, os.ErrNotExist) {
-				return err
-			}
+	if plan.Config.Renumber {
+		// Renumbered targets overlap their sources, so order the renames (via temporary names
+		// for cycles) and record every hop so undo retraces them.
+		moves := make([]history.Operation, 0, len(ops))
+		for _, op := range ops {
+			moves = append(moves, history.Operation{From: op.fromRel, To: op.toRel})
+		}
+		ordered, err := history.OrderRenames(merged.WorkingDir, moves)
+		if err != nil {
+			return history.Entry{}, err
+		}
+		ops = ops[:0]
+		for _, move := range ordered {
+			ops = append(ops, renameOp{
+				fromAbs: filepath.Join(merged.WorkingDir, filepath.FromSlash(move.From)),
+				toAbs:   filepath.Join(merged.WorkingDir, filepath.FromSlash(move.To)),
+				fromRel: move.From,
+				toRel:   move.To,
+			})
 		}
-		return nil
 	}
 
+	done := make([]history.Operation, 0, len(ops))
+
 	for _, op := range ops {
 		if err := ctx.Err(); err != nil {
-			_ = revert()
+			_ = history.Rollback(merged.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -75,7 +84,7 @@ func Apply(ctx context.Context, opts Options, plan Plan) (history.Entry, error)
 		}
 
 		if err := os.Rename(op.fromAbs, op.toAbs); err != nil {
-			_ = revert()
+			_ = history.Rollback(merged.WorkingDir, done)
 			return history.Entry{}, err
 		}
 
@@ -85,6 +94,19 @@ func Apply(ctx context.Context, opts Options, plan Plan) (history.Entry, error)
 		})
 	}
 
+	followers, err := plan.Sidecars.Apply(merged.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(merged.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, followers...)
+	edits, err := plan.References.Apply(merged.WorkingDir)
+	if err != nil {
+		_ = history.Rollback(merged.WorkingDir, done)
+		return history.Entry{}, err
+	}
+	done = append(done, edits...)
+
 	if len(done) == 0 {
 		return entry, nil
 	}
@@ -92,20 +114,36 @@ func Apply(ctx context.Context, opts Options, plan Plan) (history.Entry, error)
 	entry.Operations = done
 	entry.Metadata = map[string]any{
 		"sequence": map[string]any{
-			"start":     plan.Config.Start,
-			"width":     plan.Summary.AppliedWidth,
-			"placement": string(plan.Config.Placement),
-			"separator": plan.Config.Separator,
-			"prefix":    plan.Config.NumberPrefix,
-			"suffix":    plan.Config.NumberSuffix,
+			"start":        plan.Config.Start,
+			"width":        plan.Summary.AppliedWidth,
+			"placement":    string(plan.Config.Placement),
+			"separator":    plan.Config.Separator,
+			"prefix":       plan.Config.NumberPrefix,
+			"suffix":       plan.Config.NumberSuffix,
+			"sort":         sortLabel(plan.Config.Sort, plan.Config.SortSeed),
+			"reverse":      plan.Config.Reverse,
+			"perDirectory": plan.Config.PerDirectory,
+			"step":         plan.Config.Step,
+			"renumber":     plan.Config.Renumber,
+			"style":        string(plan.Config.Style),
 		},
 		"totalCandidates": plan.Summary.TotalCandidates,
 		"renamed":         plan.Summary.RenamedCount,
 		"skipped":         plan.Summary.SkippedCount,
 	}
+	if plan.Config.Renumber {
+		field := map[string]any{"position": string(plan.Config.FieldPosition)}
+		if plan.Config.FieldPattern != "" {
+			field = map[string]any{"regex": plan.Config.FieldPattern}
+		}
+		entry.Metadata["field"] = field
+	}
+	if nameFilter := merged.NameFilter.Metadata(); nameFilter != nil {
+		entry.Metadata["nameFilter"] = nameFilter
+	}
 
 	if err := history.Append(merged.WorkingDir, entry); err != nil {
-		_ = revert()
+		_ = history.Rollback(merged.WorkingDir, done)
 		return history.Entry{}, err
 	}
 
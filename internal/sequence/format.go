@@ -3,8 +3,53 @@ package sequence
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
+// Style selects the numbering system used for sequence labels.
+type Style string
+
+const (
+	// StyleDecimal emits zero-padded decimal numbers (001, 002, …).
+	StyleDecimal Style = "decimal"
+	// StyleAlpha emits bijective base-26 letters (a, b, …, z, aa, ab, …).
+	StyleAlpha Style = "alpha"
+	// StyleAlphaUpper emits uppercase letters (A, B, …, Z, AA, …).
+	StyleAlphaUpper Style = "ALPHA"
+	// StyleRoman emits uppercase roman numerals (I, II, …, MMMCMXCIX).
+	StyleRoman Style = "roman"
+	// StyleHex emits zero-padded lowercase hexadecimal numbers.
+	StyleHex Style = "hex"
+	// StyleOf emits total-aware labels such as 03of12.
+	StyleOf Style = "of"
+)
+
+// maxRoman is the largest value representable with standard roman numerals.
+const maxRoman = 3999
+
+// ParseStyle validates a --style value. Letter styles are case-sensitive (alpha vs ALPHA); the
+// others accept any case.
+func ParseStyle(value string) (Style, error) {
+	trimmed := strings.TrimSpace(value)
+	switch Style(trimmed) {
+	case "":
+		return StyleDecimal, nil
+	case StyleAlpha, StyleAlphaUpper:
+		return Style(trimmed), nil
+	}
+	switch style := Style(strings.ToLower(trimmed)); style {
+	case StyleDecimal, StyleRoman, StyleHex, StyleOf:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unsupported numbering style %q (use decimal, alpha, ALPHA, roman, hex, or of)", value)
+	}
+}
+
+// padded reports whether the style honours a digit width.
+func (s Style) padded() bool {
+	return s == StyleDecimal || s == StyleHex || s == StyleOf
+}
+
 // formatNumber zero-pads the provided value using the requested width and returns
 // both the padded number string and the width that was ultimately used.
 func formatNumber(value, requestedWidth int) (string, int) {
@@ -18,3 +63,78 @@ func formatNumber(value, requestedWidth int) (string, int) {
 	}
 	return fmt.Sprintf("%0*d", width, value), width
 }
+
+// formatLabel renders value in the given style. total is the number of candidates sharing the
+// counter and is only used by StyleOf. The returned width is the padded digit count for padded
+// styles and the label length otherwise.
+func formatLabel(style Style, value, requestedWidth, total int) (string, int, error) {
+	switch style {
+	case StyleHex:
+		digits := len(strconv.FormatInt(int64(value), 16))
+		width := max(requestedWidth, digits)
+		return fmt.Sprintf("%0*x", width, value), width, nil
+	case StyleOf:
+		number, width := formatNumber(value, requestedWidth)
+		return number + "of" + strconv.Itoa(total), width, nil
+	case StyleAlpha, StyleAlphaUpper:
+		label := alphaLabel(value)
+		if style == StyleAlphaUpper {
+			label = strings.ToUpper(label)
+		}
+		return label, len(label), nil
+	case StyleRoman:
+		if value < 1 || value > maxRoman {
+			return "", 0, fmt.Errorf("roman numerals cover 1-%d; %d is out of range", maxRoman, value)
+		}
+		label := romanLabel(value)
+		return label, len(label), nil
+	default:
+		number, width := formatNumber(value, requestedWidth)
+		return number, width, nil
+	}
+}
+
+// labelWidth returns the digit count needed to render value in a padded style.
+func labelWidth(style Style, value int) int {
+	if style == StyleHex {
+		return len(strconv.FormatInt(int64(value), 16))
+	}
+	return len(strconv.Itoa(value))
+}
+
+// alphaLabel converts value (1-based) to bijective base-26: 1 → a, 26 → z, 27 → aa.
+func alphaLabel(value int) string {
+	if value < 1 {
+		return ""
+	}
+	var letters []byte
+	for value > 0 {
+		value--
+		letters = append(letters, byte('a'+value%26))
+		value /= 26
+	}
+	for i, j := 0, len(letters)-1; i < j; i, j = i+1, j-1 {
+		letters[i], letters[j] = letters[j], letters[i]
+	}
+	return string(letters)
+}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func romanLabel(value int) string {
+	var builder strings.Builder
+	for _, numeral := range romanNumerals {
+		for value >= numeral.value {
+			builder.WriteString(numeral.symbol)
+			value -= numeral.value
+		}
+	}
+	return builder.String()
+}
@@ -4,7 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // Placement controls where a sequence number is inserted.
@@ -17,7 +23,11 @@ const (
 	PlacementPrefix Placement = "prefix"
 )
 
-// Options captures configuration for numbering operations.
+// Options captures configuration for numbering operations. SortSeed seeds the SortRandom shuffle,
+// Reverse flips the sort order, PerDirectory restarts the counter at Start in every directory, and
+// Step is the increment between consecutive numbers. Renumber rewrites an existing number located
+// by FieldPattern or FieldPosition instead of adding one, and Style picks the numbering system.
+// A zero Width sizes labels for the largest number in the run.
 type Options struct {
 	WorkingDir         string
 	Start              int
@@ -27,21 +37,38 @@ type Options struct {
 	NumberSuffix       string
 	Placement          Placement
 	Separator          string
+	Sort               SortKey
+	SortSeed           int64
+	Reverse            bool
+	PerDirectory       bool
+	Step               int
+	Renumber           bool
+	FieldPattern       string
+	FieldPosition      FieldPosition
+	Style              Style
 	IncludeHidden      bool
 	IncludeDirectories bool
 	Recursive          bool
 	Extensions         []string
+	ExtensionModel     fileext.Model
+	NameFilter         filters.NameFilter
+	Sidecars           *sidecar.Groups
+	References         *references.Rewriter
 	DryRun             bool
 	AutoApply          bool
+
+	fieldPattern *regexp.Regexp
 }
 
 // DefaultOptions returns a copy of the default configuration.
 func DefaultOptions() Options {
 	return Options{
 		Start:     1,
-		Width:     3,
 		Placement: PlacementPrefix,
 		Separator: "_",
+		Sort:      SortName,
+		Step:      1,
+		Style:     StyleDecimal,
 	}
 }
 
@@ -80,6 +107,43 @@ func validateOptions(opts *Options) error {
 		return fmt.Errorf("unsupported placement %q", opts.Placement)
 	}
 
+	sortKey, seed, err := ParseSort(sortLabel(opts.Sort, opts.SortSeed))
+	if err != nil {
+		return err
+	}
+	opts.Sort, opts.SortSeed = sortKey, seed
+
+	style, err := ParseStyle(string(opts.Style))
+	if err != nil {
+		return err
+	}
+	opts.Style = style
+
+	if opts.Step < 1 {
+		return errors.New("step must be >= 1")
+	}
+
+	if opts.Sort == SortNumber && !opts.Renumber {
+		return errors.New("sort key number requires renumber mode")
+	}
+	if opts.Renumber {
+		if opts.NumberPrefix != "" || opts.NumberSuffix != "" {
+			return errors.New("number prefix and suffix cannot be combined with renumber mode")
+		}
+		position, err := ParseFieldPosition(string(opts.FieldPosition))
+		if err != nil {
+			return err
+		}
+		opts.FieldPosition = position
+		if opts.FieldPattern != "" {
+			re, err := compileFieldPattern(opts.FieldPattern)
+			if err != nil {
+				return err
+			}
+			opts.fieldPattern = re
+		}
+	}
+
 	if strings.ContainsAny(opts.Separator, "/\\") {
 		return errors.New("separator cannot contain path separators")
 	}
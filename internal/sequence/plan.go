@@ -1,11 +1,20 @@
 package sequence
 
+import (
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
 // Plan represents the ordered numbering proposal produced during preview.
 type Plan struct {
 	Candidates       []Candidate
 	SkippedConflicts []Conflict
 	Summary          Summary
 	Config           Config
+	// Sidecars holds the companion renames derived from the pending candidates.
+	Sidecars sidecar.Plan
+	// References holds the document rewrites that follow the pending candidates.
+	References references.Plan
 }
 
 // Candidate describes a single file considered for numbering.
@@ -59,10 +68,19 @@ type Summary struct {
 
 // Config snapshots the numbering configuration for preview/apply/ledger.
 type Config struct {
-	Start        int
-	Width        int
-	Placement    Placement
-	Separator    string
-	NumberPrefix string
-	NumberSuffix string
+	Start         int
+	Width         int
+	Placement     Placement
+	Separator     string
+	NumberPrefix  string
+	NumberSuffix  string
+	Sort          SortKey
+	SortSeed      int64
+	Reverse       bool
+	PerDirectory  bool
+	Step          int
+	Renumber      bool
+	FieldPattern  string
+	FieldPosition FieldPosition
+	Style         Style
 }
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
 )
 
 // Preview computes the numbering plan for the provided options, returning the
@@ -24,26 +28,71 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 	if err != nil {
 		return Plan{}, err
 	}
+	autoWidth := !merged.WidthSet && merged.Width == 0
+	var renumberWarnings []string
+	if merged.Renumber {
+		traversalCandidates, renumberWarnings = selectRenumberCandidates(traversalCandidates, merged)
+		if autoWidth {
+			merged.Width = renumberWidth(traversalCandidates)
+		}
+	}
+	if err := sortCandidates(ctx, traversalCandidates, merged); err != nil {
+		return Plan{}, err
+	}
+
+	totals := counterTotals(traversalCandidates, merged.PerDirectory)
+	if autoWidth && merged.Style.padded() {
+		// Size labels for the largest value up front so widths never grow mid-run; renumbering
+		// keeps at least the existing field width.
+		largest := 0
+		for _, total := range totals {
+			largest = max(largest, total)
+		}
+		merged.Width = max(merged.Width, labelWidth(merged.Style, merged.Start+max(largest-1, 0)*merged.Step))
+	}
 
 	plan := Plan{
 		Candidates: make([]Candidate, 0, len(traversalCandidates)),
 		Config: Config{
-			Start:        merged.Start,
-			Width:        merged.Width,
-			Placement:    merged.Placement,
-			Separator:    merged.Separator,
-			NumberPrefix: merged.NumberPrefix,
-			NumberSuffix: merged.NumberSuffix,
+			Start:         merged.Start,
+			Width:         merged.Width,
+			Placement:     merged.Placement,
+			Separator:     merged.Separator,
+			NumberPrefix:  merged.NumberPrefix,
+			NumberSuffix:  merged.NumberSuffix,
+			Sort:          merged.Sort,
+			SortSeed:      merged.SortSeed,
+			Reverse:       merged.Reverse,
+			PerDirectory:  merged.PerDirectory,
+			Step:          merged.Step,
+			Renumber:      merged.Renumber,
+			FieldPattern:  merged.FieldPattern,
+			FieldPosition: merged.FieldPosition,
+			Style:         merged.Style,
 		},
 	}
+	plan.Summary.Warnings = append(plan.Summary.Warnings, renumberWarnings...)
+
+	sources := make(map[string]struct{}, len(traversalCandidates))
+	if merged.Renumber {
+		for _, entry := range traversalCandidates {
+			sources[entry.RelativePath] = struct{}{}
+		}
+	}
 
 	plannedTargets := make(map[string]string)
 	plannedTargetsFold := make(map[string]string)
 
-	widthUsed := merged.Width
+	widthUsed := 0
+	if merged.Style.padded() {
+		widthUsed = merged.Width
+	}
 	widthWarned := false
+	orderWarned := false
+	previousLabel := ""
 	nextValue := merged.Start
 	sequenceIndex := 0
+	currentDir := ""
 
 	for _, entry := range traversalCandidates {
 		if entry.IsDir {
@@ -51,20 +100,42 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 			continue
 		}
 
+		group := ""
+		if merged.PerDirectory {
+			group = path.Dir(entry.RelativePath)
+			if group != currentDir {
+				currentDir = group
+				nextValue = merged.Start
+				previousLabel = ""
+			}
+		}
+
 		plan.Summary.TotalCandidates++
 
-		number, appliedWidth := formatNumber(nextValue, merged.Width)
-		if merged.WidthSet && appliedWidth > merged.Width && !widthWarned {
+		number, appliedWidth, err := formatLabel(merged.Style, nextValue, merged.Width, totals[group])
+		if err != nil {
+			return Plan{}, fmt.Errorf("%s: %w", entry.RelativePath, err)
+		}
+		if previousLabel != "" && number < previousLabel && !orderWarned {
+			plan.Summary.Warnings = append(plan.Summary.Warnings, fmt.Sprintf("%s sorts before %s by name, so listings will not follow the numbering order (%s)", number, previousLabel, entry.RelativePath))
+			orderWarned = true
+		}
+		previousLabel = number
+		if merged.Style.padded() && !autoWidth && appliedWidth > merged.Width && !widthWarned {
 			plan.Summary.Warnings = append(plan.Summary.Warnings, fmt.Sprintf("requested width %d expanded to %d for %s", merged.Width, appliedWidth, entry.RelativePath))
 			widthWarned = true
 		}
-		if appliedWidth > widthUsed {
+		// Alphabetic and roman labels are never zero-padded, so they report no width.
+		if merged.Style.padded() && appliedWidth > widthUsed {
 			widthUsed = appliedWidth
 		}
 
 		formattedNumber := merged.NumberPrefix + number + merged.NumberSuffix
 
 		proposed := buildProposedPath(entry, merged, formattedNumber)
+		if merged.Renumber {
+			proposed = renumberedPath(entry, number)
+		}
 
 		candidate := Candidate{
 			OriginalPath: entry.RelativePath,
@@ -78,7 +149,7 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 			candidate.Status = CandidateUnchanged
 			plan.Candidates = append(plan.Candidates, candidate)
 			sequenceIndex++
-			nextValue++
+			nextValue += merged.Step
 			continue
 		}
 
@@ -88,7 +159,7 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 			candidate.Status = CandidateSkipped
 			plan.Candidates = append(plan.Candidates, candidate)
 			sequenceIndex++
-			nextValue++
+			nextValue += merged.Step
 			continue
 		}
 
@@ -99,12 +170,24 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 			candidate.Status = CandidateSkipped
 			plan.Candidates = append(plan.Candidates, candidate)
 			sequenceIndex++
-			nextValue++
+			nextValue += merged.Step
 			continue
 		}
 
 		targetAbs := filepath.Join(merged.WorkingDir, filepath.FromSlash(proposed))
-		if info, statErr := os.Stat(targetAbs); statErr == nil {
+		if violation, ok := pathlimit.CheckPath(targetAbs); !ok {
+			plan.appendConflict(entry.RelativePath, proposed, ConflictReason(violation.Reason()))
+			plan.Summary.SkippedCount++
+			candidate.Status = CandidateSkipped
+			plan.Candidates = append(plan.Candidates, candidate)
+			sequenceIndex++
+			nextValue += merged.Step
+			continue
+		}
+		// A target held by another renumbered file is checked by resolveOccupiedTargets once every
+		// candidate's status is known.
+		_, occupiedBySource := sources[proposed]
+		if info, statErr := os.Stat(targetAbs); statErr == nil && !occupiedBySource {
 			origInfo, origErr := os.Stat(entry.AbsolutePath)
 			if origErr != nil || !os.SameFile(info, origInfo) {
 				plan.appendConflict(entry.RelativePath, proposed, ConflictExistingTarget)
@@ -112,10 +195,10 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 				candidate.Status = CandidateSkipped
 				plan.Candidates = append(plan.Candidates, candidate)
 				sequenceIndex++
-				nextValue++
+				nextValue += merged.Step
 				continue
 			}
-		} else if !errors.Is(statErr, os.ErrNotExist) {
+		} else if statErr != nil && !errors.Is(statErr, os.ErrNotExist) {
 			return Plan{}, statErr
 		}
 
@@ -124,7 +207,15 @@ func Preview(ctx context.Context, opts Options, w io.Writer) (Plan, error) {
 		plannedTargets[proposed] = entry.RelativePath
 		plannedTargetsFold[lowerKey] = entry.RelativePath
 		sequenceIndex++
-		nextValue++
+		nextValue += merged.Step
+	}
+
+	if merged.Renumber {
+		plan.resolveOccupiedTargets()
+	}
+	plan.expandSidecars(merged)
+	if err := plan.planReferences(merged); err != nil {
+		return Plan{}, err
 	}
 
 	plan.Summary.AppliedWidth = widthUsed
@@ -146,16 +237,52 @@ func mergeOptions(opts Options) Options {
 	}
 	merged.NumberPrefix = opts.NumberPrefix
 	merged.NumberSuffix = opts.NumberSuffix
+	if opts.Sort != "" {
+		merged.Sort = opts.Sort
+	}
+	merged.SortSeed = opts.SortSeed
+	merged.Reverse = opts.Reverse
+	merged.PerDirectory = opts.PerDirectory
+	merged.Renumber = opts.Renumber
+	merged.FieldPattern = opts.FieldPattern
+	merged.FieldPosition = opts.FieldPosition
+	if opts.Style != "" {
+		merged.Style = opts.Style
+	}
+	if opts.Step != 0 {
+		merged.Step = opts.Step
+	}
 	merged.WorkingDir = opts.WorkingDir
 	merged.IncludeDirectories = opts.IncludeDirectories
 	merged.IncludeHidden = opts.IncludeHidden
 	merged.Recursive = opts.Recursive
 	merged.Extensions = append([]string(nil), opts.Extensions...)
+	merged.ExtensionModel = opts.ExtensionModel
+	merged.NameFilter = opts.NameFilter
+	merged.Sidecars = opts.Sidecars
+	merged.References = opts.References
 	merged.DryRun = opts.DryRun
 	merged.AutoApply = opts.AutoApply
 	return merged
 }
 
+// counterTotals counts the files sharing each counter: one group keyed "" or, with perDirectory,
+// one per parent directory.
+func counterTotals(candidates []traversalCandidate, perDirectory bool) map[string]int {
+	totals := make(map[string]int)
+	for _, entry := range candidates {
+		if entry.IsDir {
+			continue
+		}
+		group := ""
+		if perDirectory {
+			group = path.Dir(entry.RelativePath)
+		}
+		totals[group]++
+	}
+	return totals
+}
+
 func buildProposedPath(entry traversalCandidate, opts Options, formattedNumber string) string {
 	dir := filepath.Dir(entry.RelativePath)
 	if dir == "." {
@@ -186,6 +313,59 @@ func joinIfNeeded(separator, value string) string {
 	return separator + value
 }
 
+// expandSidecars derives the companion renames for pending candidates. A cluster whose companions
+// cannot follow is skipped whole, like any other conflicting candidate.
+func (p *Plan) expandSidecars(opts Options) {
+	blocked := make([]sidecar.Conflict, 0)
+	for {
+		renames := make([]sidecar.Rename, 0, len(p.Candidates))
+		for _, candidate := range p.Candidates {
+			if candidate.Status == CandidatePending {
+				renames = append(renames, sidecar.Rename{From: candidate.OriginalPath, To: candidate.ProposedPath})
+			}
+		}
+		p.Sidecars = opts.Sidecars.Expand(opts.WorkingDir, renames)
+		if !p.Sidecars.HasConflicts() {
+			break
+		}
+
+		heads := make(map[string]bool, len(p.Sidecars.Conflicts))
+		for _, conflict := range p.Sidecars.Conflicts {
+			heads[conflict.Head] = true
+			p.appendConflict(conflict.From, conflict.To, ConflictReason(conflict.Reason))
+		}
+		blocked = append(blocked, p.Sidecars.Conflicts...)
+		for i := range p.Candidates {
+			candidate := &p.Candidates[i]
+			if candidate.Status == CandidatePending && heads[candidate.OriginalPath] {
+				candidate.Status = CandidateSkipped
+				p.Summary.RenamedCount--
+				p.Summary.SkippedCount++
+			}
+		}
+		if opts.Renumber {
+			p.resolveOccupiedTargets()
+		}
+	}
+	p.Sidecars.Conflicts = blocked
+}
+
+// planReferences derives the document rewrites for the pending candidates and their companions.
+func (p *Plan) planReferences(opts Options) error {
+	renames := make([]sidecar.Rename, 0, len(p.Candidates))
+	for _, candidate := range p.Candidates {
+		if candidate.Status == CandidatePending {
+			renames = append(renames, sidecar.Rename{From: candidate.OriginalPath, To: candidate.ProposedPath})
+		}
+	}
+	refs, err := opts.References.Plan(renames, p.Sidecars.Renames)
+	if err != nil {
+		return err
+	}
+	p.References = refs
+	return nil
+}
+
 func (p *Plan) appendConflict(original, proposed string, reason ConflictReason) {
 	p.SkippedConflicts = append(p.SkippedConflicts, Conflict{
 		OriginalPath:    original,
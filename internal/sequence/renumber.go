@@ -0,0 +1,177 @@
+package sequence
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FieldPosition selects which run of digits in the stem is renumbered when no regex is given.
+type FieldPosition string
+
+const (
+	// FieldFirst renumbers the first run of digits (e.g. 007 in 007_photo_2024).
+	FieldFirst FieldPosition = "first"
+	// FieldLast renumbers the last run of digits (e.g. 007 in photo_2024_007).
+	FieldLast FieldPosition = "last"
+)
+
+// ParseFieldPosition validates a --field-position value; empty selects the last run.
+func ParseFieldPosition(value string) (FieldPosition, error) {
+	switch position := FieldPosition(strings.ToLower(strings.TrimSpace(value))); position {
+	case "":
+		return FieldLast, nil
+	case FieldFirst, FieldLast:
+		return position, nil
+	default:
+		return "", fmt.Errorf("unsupported field position %q (use first or last)", value)
+	}
+}
+
+// numberField locates an existing number in a candidate's stem as a byte span.
+type numberField struct {
+	start int
+	end   int
+	value int
+}
+
+// compileFieldPattern validates a --field-regex expression.
+func compileFieldPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field regex: %w", err)
+	}
+	if re.NumSubexp() > 1 {
+		return nil, fmt.Errorf("field regex %q must capture at most one group", pattern)
+	}
+	return re, nil
+}
+
+// locateField finds the number to renumber. With a pattern, the first match's capture group (or
+// the whole match when there is none) must consist of ASCII digits; otherwise the first or last
+// run of digits is used. ok is false when the stem holds no usable number.
+func locateField(stem string, re *regexp.Regexp, position FieldPosition) (numberField, bool) {
+	if re != nil {
+		loc := re.FindStringSubmatchIndex(stem)
+		if loc == nil {
+			return numberField{}, false
+		}
+		start, end := loc[0], loc[1]
+		if len(loc) >= 4 {
+			if loc[2] < 0 {
+				return numberField{}, false
+			}
+			start, end = loc[2], loc[3]
+		}
+		return parseField(stem, start, end)
+	}
+
+	runs := make([][2]int, 0)
+	for i := 0; i < len(stem); {
+		if !isASCIIDigit(rune(stem[i])) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(stem) && isASCIIDigit(rune(stem[j])) {
+			j++
+		}
+		runs = append(runs, [2]int{i, j})
+		i = j
+	}
+	if len(runs) == 0 {
+		return numberField{}, false
+	}
+	run := runs[len(runs)-1]
+	if position == FieldFirst {
+		run = runs[0]
+	}
+	return parseField(stem, run[0], run[1])
+}
+
+func parseField(stem string, start, end int) (numberField, bool) {
+	digits := stem[start:end]
+	if digits == "" {
+		return numberField{}, false
+	}
+	for i := 0; i < len(digits); i++ {
+		if !isASCIIDigit(rune(digits[i])) {
+			return numberField{}, false
+		}
+	}
+	value, err := strconv.Atoi(digits)
+	if err != nil {
+		return numberField{}, false
+	}
+	return numberField{start: start, end: end, value: value}, true
+}
+
+// selectRenumberCandidates records each file's existing number and drops files without one,
+// returning a warning for every file left unchanged.
+func selectRenumberCandidates(candidates []traversalCandidate, opts Options) ([]traversalCandidate, []string) {
+	selected := make([]traversalCandidate, 0, len(candidates))
+	warnings := make([]string, 0)
+	for _, candidate := range candidates {
+		if candidate.IsDir {
+			continue
+		}
+		field, ok := locateField(candidate.Stem, opts.fieldPattern, opts.FieldPosition)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s has no number to renumber; left unchanged", candidate.RelativePath))
+			continue
+		}
+		candidate.field = field
+		selected = append(selected, candidate)
+	}
+	return selected, warnings
+}
+
+// renumberWidth keeps the widest existing field so photo_007 stays three digits by default.
+func renumberWidth(candidates []traversalCandidate) int {
+	width := 1
+	for _, candidate := range candidates {
+		if digits := candidate.field.end - candidate.field.start; digits > width {
+			width = digits
+		}
+	}
+	return width
+}
+
+// renumberedPath replaces the located number with the new one, keeping the rest of the name.
+func renumberedPath(entry traversalCandidate, number string) string {
+	name := entry.Stem[:entry.field.start] + number + entry.Stem[entry.field.end:] + entry.Extension
+	if dir := path.Dir(entry.RelativePath); dir != "." {
+		return dir + "/" + name
+	}
+	return name
+}
+
+// resolveOccupiedTargets skips renames whose target is held by a renumbered file that is not
+// itself moving (unchanged or skipped), repeating until no skip cascades further.
+func (p *Plan) resolveOccupiedTargets() {
+	bySource := make(map[string]int, len(p.Candidates))
+	for i, candidate := range p.Candidates {
+		bySource[candidate.OriginalPath] = i
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i := range p.Candidates {
+			candidate := &p.Candidates[i]
+			if candidate.Status != CandidatePending {
+				continue
+			}
+			j, ok := bySource[candidate.ProposedPath]
+			if !ok || j == i || p.Candidates[j].Status == CandidatePending {
+				continue
+			}
+			candidate.Status = CandidateSkipped
+			p.appendConflict(candidate.OriginalPath, candidate.ProposedPath, ConflictExistingTarget)
+			p.Summary.RenamedCount--
+			p.Summary.SkippedCount++
+			changed = true
+		}
+	}
+}
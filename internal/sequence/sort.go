@@ -0,0 +1,236 @@
+package sequence
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+// SortKey selects the order in which candidates receive sequence numbers.
+type SortKey string
+
+const (
+	// SortName numbers candidates in traversal (path) order.
+	SortName SortKey = "name"
+	// SortNatural orders paths with embedded numbers compared by value, so file2 precedes file10.
+	SortNatural SortKey = "natural"
+	// SortMtime numbers candidates by modification time.
+	SortMtime SortKey = "mtime"
+	// SortSize numbers candidates by size in bytes.
+	SortSize SortKey = "size"
+	// SortEXIF numbers candidates by media capture time, falling back to mtime.
+	SortEXIF SortKey = "exif"
+	// SortNumber orders candidates by the number being renumbered (renumber mode only).
+	SortNumber SortKey = "number"
+	// SortRandom shuffles candidates with a seed so the order is reproducible.
+	SortRandom SortKey = "random"
+)
+
+// ParseSort validates a --sort value. Random ordering requires a seed written as random:SEED; the
+// seed is returned alongside the key and is zero for every other key.
+func ParseSort(value string) (SortKey, int64, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if rest, ok := strings.CutPrefix(normalized, string(SortRandom)); ok {
+		seedText, hasSeed := strings.CutPrefix(rest, ":")
+		if !hasSeed || seedText == "" {
+			return "", 0, fmt.Errorf("random sort requires a seed, e.g. random:42")
+		}
+		seed, err := strconv.ParseInt(seedText, 10, 64)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid random seed %q", seedText)
+		}
+		return SortRandom, seed, nil
+	}
+
+	switch key := SortKey(normalized); key {
+	case "":
+		return SortName, 0, nil
+	case SortName, SortNatural, SortMtime, SortSize, SortEXIF, SortNumber:
+		return key, 0, nil
+	default:
+		return "", 0, fmt.Errorf("unsupported sort key %q (use name, natural, mtime, size, exif, number, or random:SEED)", value)
+	}
+}
+
+// sortLabel renders the sort key as written on the command line.
+func sortLabel(key SortKey, seed int64) string {
+	if key == SortRandom {
+		return fmt.Sprintf("%s:%d", key, seed)
+	}
+	return string(key)
+}
+
+// sortCandidates orders candidates by the requested key. Ties are broken by relative path so the
+// numbering is stable across runs; reverse flips the final order, and perDirectory groups
+// candidates by parent directory (in path order) while keeping the key order inside each group.
+func sortCandidates(ctx context.Context, candidates []traversalCandidate, opts Options) error {
+	switch opts.Sort {
+	case SortNatural:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return naturalLess(candidates[i].RelativePath, candidates[j].RelativePath)
+		})
+	case SortMtime, SortSize, SortEXIF:
+		values, err := sortValues(ctx, candidates, opts.Sort)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			vi, vj := values[candidates[i].RelativePath], values[candidates[j].RelativePath]
+			if vi != vj {
+				return vi < vj
+			}
+			return candidates[i].RelativePath < candidates[j].RelativePath
+		})
+	case SortNumber:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			fi, fj := candidates[i].field.value, candidates[j].field.value
+			if fi != fj {
+				return fi < fj
+			}
+			return candidates[i].RelativePath < candidates[j].RelativePath
+		})
+	case SortRandom:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].RelativePath < candidates[j].RelativePath
+		})
+		rng := rand.New(rand.NewSource(opts.SortSeed))
+		rng.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	}
+
+	if opts.Reverse {
+		for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		}
+	}
+
+	if opts.PerDirectory {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return path.Dir(candidates[i].RelativePath) < path.Dir(candidates[j].RelativePath)
+		})
+	}
+	return nil
+}
+
+// sortValues reads the numeric attribute used by the mtime, size, and exif keys. Directories are
+// never numbered and keep a zero value.
+func sortValues(ctx context.Context, candidates []traversalCandidate, key SortKey) (map[string]int64, error) {
+	values := make(map[string]int64, len(candidates))
+	for _, candidate := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if candidate.IsDir {
+			continue
+		}
+		var value int64
+		switch key {
+		case SortEXIF:
+			ts, _, err := metadata.CaptureTime(candidate.AbsolutePath, nil)
+			if err != nil {
+				return nil, err
+			}
+			value = timeValue(ts)
+		default:
+			info, err := os.Stat(candidate.AbsolutePath)
+			if err != nil {
+				return nil, err
+			}
+			if key == SortSize {
+				value = info.Size()
+			} else {
+				value = timeValue(info.ModTime())
+			}
+		}
+		values[candidate.RelativePath] = value
+	}
+	return values, nil
+}
+
+func timeValue(ts time.Time) int64 {
+	if ts.IsZero() {
+		return 0
+	}
+	return ts.UnixNano()
+}
+
+// naturalLess compares slash-separated paths segment by segment, treating digit runs as numbers
+// and other text case-insensitively. Paths that compare equal fall back to byte order.
+func naturalLess(a, b string) bool {
+	if cmp := naturalCompare(a, b); cmp != 0 {
+		return cmp < 0
+	}
+	return a < b
+}
+
+func naturalCompare(a, b string) int {
+	segmentsA := strings.Split(a, "/")
+	segmentsB := strings.Split(b, "/")
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		if cmp := compareNaturalSegment(segmentsA[i], segmentsB[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return len(segmentsA) - len(segmentsB)
+}
+
+func compareNaturalSegment(a, b string) int {
+	chunksA := naturalChunks(a)
+	chunksB := naturalChunks(b)
+	for i := 0; i < len(chunksA) && i < len(chunksB); i++ {
+		ca, cb := chunksA[i], chunksB[i]
+		digitsA, digitsB := isDigitChunk(ca), isDigitChunk(cb)
+		switch {
+		case digitsA && digitsB:
+			ta, tb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) - len(tb)
+			}
+			if cmp := strings.Compare(ta, tb); cmp != 0 {
+				return cmp
+			}
+			// Equal values: fewer leading zeros sort first (file1 before file01).
+			if len(ca) != len(cb) {
+				return len(ca) - len(cb)
+			}
+		default:
+			if cmp := strings.Compare(strings.ToLower(ca), strings.ToLower(cb)); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+	return len(chunksA) - len(chunksB)
+}
+
+// naturalChunks splits value into alternating runs of ASCII digits and other characters.
+func naturalChunks(value string) []string {
+	chunks := make([]string, 0)
+	start := 0
+	for i, r := range value {
+		if i > start && isASCIIDigit(r) != isASCIIDigit(rune(value[i-1])) {
+			chunks = append(chunks, value[start:i])
+			start = i
+		}
+	}
+	if start < len(value) {
+		chunks = append(chunks, value[start:])
+	}
+	return chunks
+}
+
+func isDigitChunk(chunk string) bool {
+	return chunk != "" && isASCIIDigit(rune(chunk[0]))
+}
+
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -17,6 +17,9 @@ type traversalCandidate struct {
 	Extension    string
 	IsDir        bool
 	Depth        int
+
+	// field is the existing number located in Stem during renumbering.
+	field numberField
 }
 
 func collectTraversalCandidates(ctx context.Context, opts Options) ([]traversalCandidate, error) {
@@ -52,6 +55,10 @@ func collectTraversalCandidates(ctx context.Context, opts Options) ([]traversalC
 		}
 
 		relSlash := filepath.ToSlash(relPath)
+		if !opts.NameFilter.Allows(relSlash, entry.IsDir()) || (!entry.IsDir() && opts.Sidecars.IsFollower(relSlash)) {
+			return nil
+		}
+
 		absolute := filepath.Join(absRoot, relPath)
 		candidate := traversalCandidate{
 			RelativePath: relSlash,
@@ -61,20 +68,11 @@ func collectTraversalCandidates(ctx context.Context, opts Options) ([]traversalC
 		}
 
 		if !entry.IsDir() {
-			rawExt := filepath.Ext(entry.Name())
-			lowerExt := strings.ToLower(rawExt)
-			if len(allowedExts) > 0 {
-				if _, ok := allowedExts[lowerExt]; !ok {
-					return nil
-				}
+			if !opts.ExtensionModel.Allows(entry.Name(), allowedExts) {
+				return nil
 			}
 
-			candidate.Extension = rawExt
-			stem := entry.Name()
-			if rawExt != "" {
-				stem = strings.TrimSuffix(stem, rawExt)
-			}
-			candidate.Stem = stem
+			candidate.Stem, candidate.Extension = opts.ExtensionModel.Split(entry.Name())
 		} else {
 			candidate.Stem = entry.Name()
 		}
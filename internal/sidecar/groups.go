@@ -0,0 +1,228 @@
+// Package sidecar clusters companion files with the file they describe (IMG_0001.CR2 with
+// IMG_0001.xmp, IMG_0001.JPG, and IMG_0001.CR2.dop; movie.mkv with movie.en.srt) so rename engines
+// can treat each cluster as one unit: engines plan the head file only, and the package derives,
+// checks, previews, and applies the matching renames for its followers.
+package sidecar
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// DefaultExtensions lists companion extensions in order of precedence: when a cluster holds only
+// companions, the earliest listed one (a JPEG before its .xmp) heads it.
+var DefaultExtensions = []string{
+	".jpg", ".jpeg", ".heic", ".xmp", ".dop", ".pp3", ".aae", ".thm", ".lrv",
+	".srt", ".vtt", ".ass", ".ssa", ".sub", ".idx", ".nfo",
+}
+
+// Rules decides which files follow another. Extensions are companion extensions in order of
+// precedence; any other extension marks a primary file that always heads its cluster.
+type Rules struct {
+	Extensions []string
+}
+
+// DefaultRules returns the built-in companion list.
+func DefaultRules() Rules {
+	return Rules{Extensions: append([]string(nil), DefaultExtensions...)}
+}
+
+// ParseRules builds rules from a pipe-delimited extension list; empty selects the defaults.
+func ParseRules(raw string) (Rules, error) {
+	if strings.TrimSpace(raw) == "" {
+		return DefaultRules(), nil
+	}
+	extensions, err := filters.ParseExtensions(raw)
+	if err != nil {
+		return Rules{}, fmt.Errorf("invalid sidecar extensions: %w", err)
+	}
+	return Rules{Extensions: extensions}, nil
+}
+
+// rank returns the precedence of a companion extension, or -1 for a primary extension.
+func (r Rules) rank(ext string) int {
+	for i, candidate := range r.Extensions {
+		if strings.EqualFold(candidate, ext) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Scope selects the files whose clusters are built; it mirrors the shared listing scope.
+type Scope struct {
+	WorkingDir    string
+	Recursive     bool
+	IncludeHidden bool
+	Extensions    []string
+	NameFilter    filters.NameFilter
+	Model         fileext.Model
+}
+
+// member is one file of a cluster: its relative path and the name parts used to rebuild it.
+type member struct {
+	rel  string
+	tail string // text between the cluster stem and the extension, e.g. ".CR2" in IMG_0001.CR2.dop
+	ext  string
+}
+
+// Groups indexes the clusters found in a scope. A nil *Groups disables grouping.
+type Groups struct {
+	model     fileext.Model
+	followers map[string][]member // head relative path -> followers
+	heads     map[string]string   // follower relative path -> head relative path
+}
+
+// Build walks the scope and clusters files sharing a stem. A cluster's head is chosen among the
+// files in scope (primary extensions first, then companion precedence, then name); companions
+// follow it even when the --extensions or name filters would exclude them. Clusters without a file
+// in scope are left alone.
+func Build(scope Scope, rules Rules) (*Groups, error) {
+	type file struct {
+		rel     string
+		name    string
+		stem    string
+		ext     string
+		inScope bool
+	}
+	byDir := make(map[string][]file)
+
+	filterSet := make(map[string]struct{}, len(scope.Extensions))
+	for _, ext := range scope.Extensions {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	err := traversal.NewWalker().Walk(
+		scope.WorkingDir,
+		scope.Recursive,
+		false,
+		scope.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			if relPath == "." || entry.IsDir() {
+				return nil
+			}
+			rel := filepath.ToSlash(relPath)
+			name := entry.Name()
+			stem, ext := scope.Model.Split(name)
+			dir := path.Dir(rel)
+			byDir[dir] = append(byDir[dir], file{
+				rel:     rel,
+				name:    name,
+				stem:    stem,
+				ext:     ext,
+				inScope: scope.Model.Allows(name, filterSet) && scope.NameFilter.Allows(rel, false),
+			})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := &Groups{
+		model:     scope.Model,
+		followers: make(map[string][]member),
+		heads:     make(map[string]string),
+	}
+
+	for _, files := range byDir {
+		stemCounts := make(map[string]int, len(files))
+		for _, f := range files {
+			stemCounts[strings.ToLower(f.stem)]++
+		}
+
+		// Companions attach to the longest shared stem: IMG_0001.CR2.dop tries "IMG_0001.CR2", then
+		// "IMG_0001". Primary files only ever cluster on their own stem.
+		clusters := make(map[string][]file)
+		keys := make(map[string]string)
+		for _, f := range files {
+			key := f.stem
+			if rules.rank(f.ext) >= 0 {
+				for candidate := f.stem; ; {
+					if count := stemCounts[strings.ToLower(candidate)]; count > 1 || (candidate != f.stem && count > 0) {
+						key = candidate
+						break
+					}
+					dot := strings.LastIndex(candidate, ".")
+					if dot <= 0 {
+						break
+					}
+					candidate = candidate[:dot]
+				}
+			}
+			lower := strings.ToLower(key)
+			clusters[lower] = append(clusters[lower], f)
+			keys[f.rel] = key
+		}
+
+		for _, members := range clusters {
+			if len(members) < 2 {
+				continue
+			}
+			sort.SliceStable(members, func(i, j int) bool {
+				if members[i].inScope != members[j].inScope {
+					return members[i].inScope
+				}
+				ri, rj := rules.rank(members[i].ext), rules.rank(members[j].ext)
+				if (ri < 0) != (rj < 0) {
+					return ri < 0
+				}
+				if ri != rj {
+					return ri < rj
+				}
+				return members[i].rel < members[j].rel
+			})
+			head := members[0]
+			if !head.inScope {
+				continue
+			}
+			headKey := keys[head.rel]
+			for _, f := range members[1:] {
+				if rules.rank(f.ext) < 0 {
+					// A second primary file (IMG_0001.CR2 beside IMG_0001.MOV) is renamed on its own.
+					continue
+				}
+				groups.followers[head.rel] = append(groups.followers[head.rel], member{
+					rel:  f.rel,
+					tail: f.stem[len(headKey):],
+					ext:  f.ext,
+				})
+				groups.heads[f.rel] = head.rel
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// IsFollower reports whether rel (slash-separated, relative to the working directory) follows
+// another file and should therefore not be planned on its own.
+func (g *Groups) IsFollower(rel string) bool {
+	if g == nil {
+		return false
+	}
+	_, ok := g.heads[filepath.ToSlash(rel)]
+	return ok
+}
+
+// Followers returns the relative paths that follow head.
+func (g *Groups) Followers(head string) []string {
+	if g == nil {
+		return nil
+	}
+	members := g.followers[filepath.ToSlash(head)]
+	paths := make([]string, 0, len(members))
+	for _, m := range members {
+		paths = append(paths, m.rel)
+	}
+	return paths
+}
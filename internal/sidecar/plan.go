@@ -0,0 +1,209 @@
+package sidecar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/history"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+)
+
+// Rename is a planned move of one file, with slash-separated paths relative to the working
+// directory. Head names the file a follower rename was derived from.
+type Rename struct {
+	From string
+	To   string
+	Head string
+}
+
+// Conflict explains why a follower cannot move with its head. Any conflict blocks the whole
+// cluster, so engines report it against Head.
+type Conflict struct {
+	Head   string
+	From   string
+	To     string
+	Reason string
+}
+
+// Message renders the conflict for preview warnings.
+func (c Conflict) Message() string {
+	return fmt.Sprintf("sidecar %s -> %s blocks %s (%s)", c.From, c.To, c.Head, c.Reason)
+}
+
+// Plan holds the follower renames derived for a batch of head renames.
+type Plan struct {
+	Renames   []Rename
+	Conflicts []Conflict
+}
+
+// Expand derives a rename for every follower of the planned renames, which may include
+// directories. A follower keeps its own extension and the text between the shared stem and that
+// extension, taking its head's new stem and directory; a tail spelling the head's old extension
+// (".CR2" in IMG_0001.CR2.dop) tracks the head's new extension. Followers move after every planned
+// rename, so their paths are rebased through renamed parent directories. Targets that collide with
+// planned targets, with each other, or with files that stay put are returned as conflicts; a file
+// that moves away itself, head or follower, frees its name. A follower that is itself among the
+// planned renames is left to the engine.
+func (g *Groups) Expand(workingDir string, heads []Rename) Plan {
+	plan := Plan{}
+	if g == nil || len(g.followers) == 0 {
+		return plan
+	}
+
+	claimed := make(map[string]string, len(heads))
+	moving := make(map[string]struct{}, len(heads))
+	for _, head := range heads {
+		claimed[strings.ToLower(head.To)] = head.From
+		moving[strings.ToLower(head.From)] = struct{}{}
+	}
+
+	// Directory renames run deepest first, so rebasing in that order follows nested renames.
+	dirs := make([]Rename, 0)
+	for _, head := range heads {
+		if _, isHead := g.followers[head.From]; !isHead && g.hasDescendant(head.From) {
+			dirs = append(dirs, head)
+		}
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i].From, "/") > strings.Count(dirs[j].From, "/")
+	})
+	rebase := func(rel string) string {
+		for _, dir := range dirs {
+			if strings.HasPrefix(rel, dir.From+"/") {
+				rel = dir.To + rel[len(dir.From):]
+			}
+		}
+		return rel
+	}
+
+	ordered := append([]Rename(nil), heads...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].From < ordered[j].From })
+
+	// Targets are claimed first and checked against the disk once every moving follower is known.
+	pending := make([]Conflict, 0)
+	for _, head := range ordered {
+		members := g.followers[head.From]
+		if len(members) == 0 || head.From == head.To {
+			continue
+		}
+		_, oldExt := g.model.Split(path.Base(head.From))
+		newStem, newExt := g.model.Split(path.Base(head.To))
+		newDir := path.Dir(head.To)
+
+		for _, m := range members {
+			if _, own := moving[strings.ToLower(m.rel)]; own {
+				// Engines that plan followers themselves (extension) keep their own proposal.
+				continue
+			}
+			tail := m.tail
+			if oldExt != "" && strings.EqualFold(tail, oldExt) {
+				tail = newExt
+			}
+			name := newStem + tail + m.ext
+			target := name
+			if newDir != "." {
+				target = newDir + "/" + name
+			}
+			if target == m.rel {
+				continue
+			}
+
+			lower := strings.ToLower(target)
+			if owner, taken := claimed[lower]; taken && !strings.EqualFold(owner, m.rel) {
+				plan.Conflicts = append(plan.Conflicts, Conflict{Head: head.From, From: m.rel, To: target, Reason: "duplicate_target"})
+				continue
+			}
+			if violation, ok := pathlimit.Check(workingDir, target); !ok {
+				plan.Conflicts = append(plan.Conflicts, Conflict{Head: head.From, From: m.rel, To: target, Reason: violation.Reason()})
+				continue
+			}
+			claimed[lower] = m.rel
+			moving[strings.ToLower(m.rel)] = struct{}{}
+			pending = append(pending, Conflict{Head: head.From, From: m.rel, To: target})
+		}
+	}
+
+	for _, candidate := range pending {
+		if _, vacated := moving[strings.ToLower(candidate.To)]; !vacated && existsOther(workingDir, candidate.From, candidate.To) {
+			candidate.Reason = "existing_file"
+			plan.Conflicts = append(plan.Conflicts, candidate)
+			continue
+		}
+		plan.Renames = append(plan.Renames, Rename{From: rebase(candidate.From), To: rebase(candidate.To), Head: candidate.Head})
+	}
+	return plan
+}
+
+// hasDescendant reports whether any follower lives below dir.
+func (g *Groups) hasDescendant(dir string) bool {
+	for follower := range g.heads {
+		if strings.HasPrefix(follower, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// existsOther reports whether target exists as a file other than source.
+func existsOther(workingDir, source, target string) bool {
+	targetInfo, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(target)))
+	if err != nil {
+		return false
+	}
+	sourceInfo, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(source)))
+	return err != nil || !os.SameFile(targetInfo, sourceInfo)
+}
+
+// HasConflicts reports whether any cluster is blocked.
+func (p Plan) HasConflicts() bool {
+	return len(p.Conflicts) > 0
+}
+
+// WritePreview prints the follower renames beneath an engine's own preview.
+func (p Plan) WritePreview(out io.Writer) {
+	if out == nil || (len(p.Renames) == 0 && len(p.Conflicts) == 0) {
+		return
+	}
+	fmt.Fprintln(out, "\nSidecars:")
+	for _, rename := range p.Renames {
+		fmt.Fprintf(out, "%s -> %s (follows %s)\n", rename.From, rename.To, rename.Head)
+	}
+	for _, conflict := range p.Conflicts {
+		fmt.Fprintf(out, "%s -> %s (skipped: %s; blocks %s)\n", conflict.From, conflict.To, conflict.Reason, conflict.Head)
+	}
+}
+
+// Apply renames the followers once the engine has renamed everything else and returns the ledger
+// operations to record after the engine's own. Followers taking each other's names (as when files
+// are renumbered) are ordered, via temporary names for cycles. A failure reverts the followers
+// already moved.
+func (p Plan) Apply(workingDir string) ([]history.Operation, error) {
+	if len(p.Renames) == 0 {
+		return nil, nil
+	}
+	moves := make([]history.Operation, 0, len(p.Renames))
+	for _, rename := range p.Renames {
+		moves = append(moves, history.Operation{From: rename.From, To: rename.To})
+	}
+	ordered, err := history.OrderRenames(workingDir, moves)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]history.Operation, 0, len(ordered))
+	for _, move := range ordered {
+		source := filepath.Join(workingDir, filepath.FromSlash(move.From))
+		destination := filepath.Join(workingDir, filepath.FromSlash(move.To))
+		if err := history.RenamePath(source, destination); err != nil {
+			_ = history.Rollback(workingDir, done)
+			return nil, err
+		}
+		done = append(done, move)
+	}
+	return done, nil
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// conflictDetector tracks proposed targets using both exact and case-folded keys. Case-folded
+// collisions between different sources are real conflicts on case-insensitive filesystems, while a
+// source whose target differs only by case is a case-only rename and must not be flagged.
+type conflictDetector struct {
+	planned     map[string]string
+	plannedFold map[string]string
+}
+
+func newConflictDetector() *conflictDetector {
+	return &conflictDetector{
+		planned:     make(map[string]string),
+		plannedFold: make(map[string]string),
+	}
+}
+
+// reserve records a path that keeps its current name so later targets cannot claim it.
+func (d *conflictDetector) reserve(relative string) {
+	d.planned[relative] = relative
+	d.plannedFold[strings.ToLower(relative)] = relative
+}
+
+// evaluate returns an empty reason when the rename may proceed, or a conflict reason otherwise.
+// other names the relative path the target collides with: another candidate, or the existing
+// target itself. A target that cannot be inspected (for example, behind an unreadable directory)
+// is reported as a conflict for that candidate alone, with other left empty.
+func (d *conflictDetector) evaluate(candidateRel, targetRel, originalAbs, targetAbs string) (reason, other string, err error) {
+	if existing, ok := d.planned[targetRel]; ok && existing != candidateRel {
+		return fmt.Sprintf("duplicate_target with %s", existing), existing, nil
+	}
+	if existing, ok := d.plannedFold[strings.ToLower(targetRel)]; ok && existing != candidateRel {
+		return fmt.Sprintf("case_fold_collision with %s", existing), existing, nil
+	}
+
+	if info, err := os.Stat(targetAbs); err == nil {
+		origInfo, origErr := os.Stat(originalAbs)
+		if origErr != nil {
+			return statReason("source_unreadable", origErr), "", nil
+		}
+		// On case-insensitive filesystems the target resolves to the source itself.
+		if !os.SameFile(info, origInfo) {
+			if info.IsDir() {
+				return "existing_directory", targetRel, nil
+			}
+			return "existing_file", targetRel, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return statReason("target_unreadable", err), "", nil
+	}
+
+	d.planned[targetRel] = candidateRel
+	d.plannedFold[strings.ToLower(targetRel)] = candidateRel
+	return "", "", nil
+}
+
+// statReason labels a stat failure with its cause, dropping the absolute path the error carries.
+func statReason(label string, err error) string {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return fmt.Sprintf("%s (%v)", label, err)
+}
@@ -0,0 +1,14 @@
+package template
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func changeTime(info fs.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package template
+
+import (
+	"io/fs"
+	"time"
+)
+
+// changeTime falls back to the modification time where the platform exposes no change time.
+func changeTime(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -0,0 +1,4 @@
+// Package template implements the `renamer template` engine. Templates mix literal text with
+// {token} placeholders drawn from a registry of file attributes, optionally piped through
+// filters such as {stem|lower|slice:0:10}.
+package template
@@ -0,0 +1,304 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation captures a filesystem rename to be applied.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	IsDir            bool
+	Depth            int
+}
+
+type candidate struct {
+	relative string
+	info     fs.FileInfo
+	depth    int
+}
+
+// BuildPlan enumerates candidates, renders the template for each, and prepares filesystem
+// operations. Candidates are rendered in traversal order so {n} counters are deterministic.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("template request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+	detector := newConflictDetector()
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	candidates := make([]candidate, 0)
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) || (!isDir && req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, candidate{relative: relative, info: info, depth: depth})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	digests, err := precomputeHashes(ctx, req, candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Unchanged names are reserved up front so a rendered target never claims a sibling that keeps
+	// its name.
+	results := make([]renderResult, len(candidates))
+	for i, c := range candidates {
+		rctx := NewContext(req.WorkingDir, c.relative, c.info, i, req.ExtensionModel)
+		rctx.Missing, rctx.Placeholder = req.Missing, req.Placeholder
+		rctx.hashes = digests[c.relative]
+		name, segments, err := req.Template.Render(rctx)
+		var missing *MissingTagError
+		if errors.As(err, &missing) {
+			results[i] = renderResult{missing: missing}
+			detector.reserve(c.relative)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		results[i] = renderResult{name: name, ext: rctx.Ext, segments: segments}
+		if joinRelative(c.relative, name) == c.relative {
+			detector.reserve(c.relative)
+		}
+	}
+
+	for i, c := range candidates {
+		result := results[i]
+
+		if result.missing != nil {
+			preview := PreviewEntry{
+				OriginalPath: c.relative,
+				ProposedPath: c.relative,
+				Status:       StatusSkipped,
+			}
+			if req.Missing == MissingSkip {
+				preview.Reason = result.missing.Error()
+			} else {
+				summary.AddConflict(Conflict{
+					OriginalPath: c.relative,
+					ProposedPath: c.relative,
+					Reason:       result.missing.Error(),
+				})
+			}
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		proposedName := result.name
+		proposedRelative := joinRelative(c.relative, proposedName)
+		originalAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(c.relative))
+		proposedAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative))
+
+		preview := PreviewEntry{
+			OriginalPath: c.relative,
+			ProposedPath: proposedRelative,
+			Status:       StatusChanged,
+			Segments:     result.segments,
+		}
+
+		reason := invalidName(proposedName, result.ext)
+		if reason == "" && proposedRelative != c.relative {
+			reason = blockedParent(req.WorkingDir, proposedRelative)
+		}
+		if reason != "" {
+			summary.AddConflict(Conflict{
+				OriginalPath: c.relative,
+				ProposedPath: proposedRelative,
+				Reason:       reason,
+			})
+			preview.Status = StatusSkipped
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		if proposedRelative == c.relative {
+			preview.Status = StatusNoChange
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		reason, other, err := detector.evaluate(c.relative, proposedRelative, originalAbsolute, proposedAbsolute)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reason != "" && other != "" && len(digests) > 0 && !c.info.IsDir() {
+			// Content-addressed names collide exactly when files are identical; say so instead of
+			// reporting a generic duplicate target.
+			otherAbsolute := filepath.Join(req.WorkingDir, filepath.FromSlash(other))
+			same, err := contenthash.SameContent(originalAbsolute, otherAbsolute)
+			if err != nil {
+				return nil, nil, err
+			}
+			if same {
+				reason = fmt.Sprintf("duplicate_content with %s", other)
+				summary.AddWarning("identical files share a content hash; review them with `renamer dupes`")
+			}
+		}
+		if reason == "" {
+			if violation, ok := pathlimit.Check(req.WorkingDir, proposedRelative); !ok {
+				reason = violation.Reason()
+			}
+		}
+		if reason != "" {
+			summary.AddConflict(Conflict{
+				OriginalPath: c.relative,
+				ProposedPath: proposedRelative,
+				Reason:       reason,
+			})
+			preview.Status = StatusSkipped
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		operations = append(operations, PlannedOperation{
+			OriginalRelative: c.relative,
+			OriginalAbsolute: originalAbsolute,
+			ProposedRelative: proposedRelative,
+			ProposedAbsolute: proposedAbsolute,
+			IsDir:            c.info.IsDir(),
+			Depth:            c.depth,
+		})
+		summary.RecordEntry(preview)
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+type renderResult struct {
+	name     string
+	ext      string
+	segments []RenderedSegment
+	missing  *MissingTagError
+}
+
+// invalidName returns a conflict reason when a rendered name cannot be used. A "/" places the
+// entry in a subdirectory of its current directory; every component must be a usable name. The
+// final component must keep a stem beyond the candidate's extension ext, so "{stem|slice:5:2}{ext}"
+// cannot turn notes.txt into a bare ".txt".
+func invalidName(name, ext string) string {
+	base := path.Base(name)
+	switch {
+	case strings.TrimSpace(name) == "", strings.TrimSpace(strings.TrimSuffix(base, ext)) == "":
+		return "empty_name"
+	case strings.Contains(name, "\\"):
+		return "path_separator"
+	}
+	for _, part := range strings.Split(name, "/") {
+		switch part {
+		case "":
+			return "empty_path_component"
+		case ".", "..":
+			return "invalid_name"
+		}
+	}
+	return ""
+}
+
+// blockedParent reports a conflict when an existing file sits where a target subdirectory would
+// be created.
+func blockedParent(workingDir, relative string) string {
+	dir := path.Dir(relative)
+	for dir != "." && dir != "/" {
+		info, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(dir)))
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Sprintf("parent_not_directory (%s)", dir)
+			}
+			return ""
+		}
+		dir = path.Dir(dir)
+	}
+	return ""
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
@@ -0,0 +1,145 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/casing"
+)
+
+// filterDocs documents the built-in filters for help output, in display order.
+var filterDocs = []TokenDoc{
+	{Name: "lower", Usage: "|lower", Description: "any case style: lower, upper, title, sentence, snake, kebab, camel, pascal"},
+	{Name: "trim", Usage: "|trim[:CHARS]", Description: "strip surrounding whitespace, or the given characters"},
+	{Name: "slice", Usage: "|slice:START[:END]", Description: "rune-indexed substring; negative indexes count from the end"},
+	{Name: "replace", Usage: "|replace:OLD:NEW", Description: "replace every occurrence of OLD"},
+	{Name: "pad", Usage: "|pad:WIDTH[:CHAR]", Description: "left-pad to WIDTH runes with CHAR (default 0)"},
+	{Name: "default", Usage: "|default:VALUE", Description: "substitute VALUE when the token renders empty"},
+}
+
+// Filters lists the built-in filters for help output.
+func Filters() []TokenDoc {
+	return append([]TokenDoc(nil), filterDocs...)
+}
+
+func validateFilter(filter Filter) error {
+	argCount := func(min, max int) error {
+		if len(filter.Args) < min || len(filter.Args) > max {
+			if min == max {
+				return fmt.Errorf("filter %q expects %d argument(s)", filter.Name, min)
+			}
+			return fmt.Errorf("filter %q expects %d to %d arguments", filter.Name, min, max)
+		}
+		return nil
+	}
+
+	if _, err := casing.ParseStyle(filter.Name); err == nil {
+		return argCount(0, 0)
+	}
+
+	switch filter.Name {
+	case "trim":
+		return argCount(0, 1)
+	case "slice":
+		if err := argCount(1, 2); err != nil {
+			return err
+		}
+		for _, arg := range filter.Args {
+			if arg == "" {
+				continue
+			}
+			if _, err := strconv.Atoi(arg); err != nil {
+				return fmt.Errorf("filter slice: invalid index %q", arg)
+			}
+		}
+		return nil
+	case "replace":
+		if err := argCount(2, 2); err != nil {
+			return err
+		}
+		if filter.Args[0] == "" {
+			return fmt.Errorf("filter replace: search text cannot be empty")
+		}
+		return nil
+	case "pad":
+		if err := argCount(1, 2); err != nil {
+			return err
+		}
+		if width, err := strconv.Atoi(filter.Args[0]); err != nil || width < 1 {
+			return fmt.Errorf("filter pad: invalid width %q", filter.Args[0])
+		}
+		if len(filter.Args) == 2 && utf8.RuneCountInString(filter.Args[1]) != 1 {
+			return fmt.Errorf("filter pad: fill must be a single character")
+		}
+		return nil
+	case "default":
+		return argCount(1, 1)
+	default:
+		return fmt.Errorf("unknown filter %q", filter.Name)
+	}
+}
+
+// applyFilter runs a validated filter against value.
+func applyFilter(value string, filter Filter) string {
+	if style, err := casing.ParseStyle(filter.Name); err == nil {
+		return casing.Convert(value, style)
+	}
+
+	switch filter.Name {
+	case "trim":
+		if len(filter.Args) == 1 {
+			return strings.Trim(value, filter.Args[0])
+		}
+		return strings.TrimSpace(value)
+	case "slice":
+		runes := []rune(value)
+		start := sliceIndex(filter.Args[0], 0, len(runes))
+		end := len(runes)
+		if len(filter.Args) == 2 {
+			end = sliceIndex(filter.Args[1], len(runes), len(runes))
+		}
+		if start >= end {
+			return ""
+		}
+		return string(runes[start:end])
+	case "replace":
+		return strings.ReplaceAll(value, filter.Args[0], filter.Args[1])
+	case "pad":
+		width, _ := strconv.Atoi(filter.Args[0])
+		fill := "0"
+		if len(filter.Args) == 2 {
+			fill = filter.Args[1]
+		}
+		if missing := width - utf8.RuneCountInString(value); missing > 0 {
+			return strings.Repeat(fill, missing) + value
+		}
+		return value
+	case "default":
+		if value == "" {
+			return filter.Args[0]
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// sliceIndex resolves a possibly negative or empty index and clamps it to [0, length].
+func sliceIndex(raw string, fallback, length int) int {
+	if raw == "" {
+		return fallback
+	}
+	index, _ := strconv.Atoi(raw)
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
@@ -0,0 +1,142 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+)
+
+func init() {
+	registry["hash"] = tokenSpec{
+		usage:       "{hash:ALG:LEN}",
+		description: "content digest (sha256, xxhash, or blake3; default sha256) truncated to LEN hex characters, e.g. {hash:8} or {hash:blake3:12}",
+		validate: func(arg string) error {
+			_, _, err := parseHashArg(arg)
+			return err
+		},
+		render: renderHash,
+	}
+}
+
+// parseHashArg reads [ALG][:LEN]; either part may be omitted.
+func parseHashArg(arg string) (contenthash.Algorithm, int, error) {
+	alg := contenthash.DefaultAlgorithm
+	length := 0
+	if arg == "" {
+		return alg, alg.HexLength(), nil
+	}
+
+	parts := strings.Split(arg, ":")
+	if len(parts) > 2 {
+		return "", 0, errors.New("hash accepts at most ALG:LEN")
+	}
+	for _, part := range parts {
+		if n, err := strconv.Atoi(part); err == nil {
+			length = n
+			continue
+		}
+		parsed, err := contenthash.ParseAlgorithm(part)
+		if err != nil {
+			return "", 0, err
+		}
+		alg = parsed
+	}
+	if length == 0 {
+		length = alg.HexLength()
+	}
+	if length < 1 || length > alg.HexLength() {
+		return "", 0, fmt.Errorf("%s digest length must be between 1 and %d", alg, alg.HexLength())
+	}
+	return alg, length, nil
+}
+
+func renderHash(ctx *Context, arg string) (string, error) {
+	alg, length, err := parseHashArg(arg)
+	if err != nil {
+		return "", err
+	}
+	digest, err := ctx.Hash(alg)
+	if err != nil {
+		return "", err
+	}
+	return digest[:length], nil
+}
+
+// Hash returns the candidate's content digest, using a digest computed ahead of time by the
+// parallel pre-pass when available.
+func (c *Context) Hash(alg contenthash.Algorithm) (string, error) {
+	if c.IsDir {
+		return "", fmt.Errorf("{hash} cannot be used with directory %s", c.RelativePath)
+	}
+	if digest, ok := c.hashes[alg]; ok {
+		return digest, nil
+	}
+	digest, err := contenthash.File(c.AbsolutePath(), alg)
+	if err != nil {
+		return "", err
+	}
+	if c.hashes == nil {
+		c.hashes = make(map[contenthash.Algorithm]string)
+	}
+	c.hashes[alg] = digest
+	return digest, nil
+}
+
+// hashAlgorithms lists the distinct algorithms referenced by {hash} placeholders.
+func (t *Template) hashAlgorithms() []contenthash.Algorithm {
+	seen := make(map[contenthash.Algorithm]bool)
+	algorithms := make([]contenthash.Algorithm, 0)
+	for _, token := range t.Tokens() {
+		if token.Name != "hash" {
+			continue
+		}
+		alg, _, err := parseHashArg(token.Arg)
+		if err != nil || seen[alg] {
+			continue
+		}
+		seen[alg] = true
+		algorithms = append(algorithms, alg)
+	}
+	return algorithms
+}
+
+// precomputeHashes digests every file candidate in parallel so rendering does not read files one
+// at a time. The result maps relative paths to digests per algorithm.
+func precomputeHashes(ctx context.Context, req *Request, candidates []candidate) (map[string]map[contenthash.Algorithm]string, error) {
+	algorithms := req.Template.hashAlgorithms()
+	if len(algorithms) == 0 {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(candidates))
+	relByPath := make(map[string]string, len(candidates))
+	for _, c := range candidates {
+		if c.info.IsDir() {
+			continue
+		}
+		abs := filepath.Join(req.WorkingDir, filepath.FromSlash(c.relative))
+		paths = append(paths, abs)
+		relByPath[abs] = c.relative
+	}
+
+	digests := make(map[string]map[contenthash.Algorithm]string, len(paths))
+	for _, alg := range algorithms {
+		results, err := contenthash.Files(ctx, paths, alg, contenthash.Options{Progress: req.Progress})
+		if err != nil {
+			return nil, err
+		}
+		for abs, digest := range results {
+			rel := relByPath[abs]
+			if digests[rel] == nil {
+				digests[rel] = make(map[contenthash.Algorithm]string, len(algorithms))
+			}
+			digests[rel][alg] = digest
+		}
+	}
+	return digests, nil
+}
@@ -0,0 +1,51 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+func init() {
+	registry["mime"] = tokenSpec{
+		usage:       "{mime}",
+		description: "MIME type with the slash replaced by '-', e.g. image-jpeg",
+		validate:    noArg,
+		render: func(ctx *Context, _ string) (string, error) {
+			mimeType, err := ctx.MIMEType()
+			if err != nil {
+				return "", err
+			}
+			return strings.ReplaceAll(mimeType, "/", "-"), nil
+		},
+	}
+	registry["mime-major"] = tokenSpec{
+		usage:       "{mime-major}",
+		description: "top-level MIME type: image, video, audio, text, or application",
+		validate:    noArg,
+		render: func(ctx *Context, _ string) (string, error) {
+			mimeType, err := ctx.MIMEType()
+			if err != nil {
+				return "", err
+			}
+			major, _, _ := strings.Cut(mimeType, "/")
+			return major, nil
+		},
+	}
+}
+
+// MIMEType returns the candidate's MIME type from its extension or content, detected once.
+func (c *Context) MIMEType() (string, error) {
+	if c.IsDir {
+		return "", fmt.Errorf("{mime} cannot be used with directory %s", c.RelativePath)
+	}
+	if c.mimeType == "" {
+		detected, err := metadata.MIMEType(c.AbsolutePath())
+		if err != nil {
+			return "", err
+		}
+		c.mimeType = detected
+	}
+	return c.mimeType, nil
+}
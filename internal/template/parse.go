@@ -0,0 +1,152 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template is a parsed rename template.
+type Template struct {
+	source   string
+	segments []segment
+}
+
+// segment is either literal text or a token placeholder.
+type segment struct {
+	literal string
+	token   *Token
+}
+
+// Token is a single {name:arg|filter...} placeholder.
+type Token struct {
+	// Name identifies the registered token, e.g. "stem" or "mtime".
+	Name string
+	// Arg is the raw text after the first ':' (layouts such as "15:04" keep their colons).
+	Arg string
+	// Filters are applied left to right to the rendered value.
+	Filters []Filter
+	// Raw is the placeholder text between the braces, used in preview explanations.
+	Raw string
+}
+
+// Filter is a single |name:arg:arg step.
+type Filter struct {
+	Name string
+	Args []string
+}
+
+// Parse compiles a template. Literal braces are written as {{ and }}. Unknown tokens, unknown
+// filters, and malformed arguments are rejected so errors surface before any preview runs.
+func Parse(source string) (*Template, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("template cannot be empty")
+	}
+
+	tmpl := &Template{source: source}
+	var literal strings.Builder
+
+	flush := func() {
+		if literal.Len() > 0 {
+			tmpl.segments = append(tmpl.segments, segment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(source); i++ {
+		ch := source[i]
+		switch ch {
+		case '{':
+			if i+1 < len(source) && source[i+1] == '{' {
+				literal.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(source[i+1:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed placeholder starting at offset %d", i)
+			}
+			body := source[i+1 : i+1+end]
+			token, err := parseToken(body)
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			tmpl.segments = append(tmpl.segments, segment{token: token})
+			i += end + 1
+		case '}':
+			if i+1 < len(source) && source[i+1] == '}' {
+				literal.WriteByte('}')
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '}' at offset %d (escape literal braces as }})", i)
+		default:
+			literal.WriteByte(ch)
+		}
+	}
+	flush()
+
+	return tmpl, nil
+}
+
+// String returns the original template source.
+func (t *Template) String() string {
+	return t.source
+}
+
+// Tokens lists the placeholders in template order.
+func (t *Template) Tokens() []Token {
+	tokens := make([]Token, 0, len(t.segments))
+	for _, seg := range t.segments {
+		if seg.token != nil {
+			tokens = append(tokens, *seg.token)
+		}
+	}
+	return tokens
+}
+
+// Uses reports whether the template references the named token.
+func (t *Template) Uses(name string) bool {
+	for _, seg := range t.segments {
+		if seg.token != nil && seg.token.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseToken(body string) (*Token, error) {
+	parts := strings.Split(body, "|")
+	head := strings.TrimSpace(parts[0])
+	if head == "" {
+		return nil, fmt.Errorf("empty placeholder {%s}", body)
+	}
+
+	token := &Token{Name: head, Raw: body}
+	if colon := strings.IndexByte(head, ':'); colon >= 0 {
+		token.Name = head[:colon]
+		token.Arg = head[colon+1:]
+	}
+	token.Name = strings.ToLower(strings.TrimSpace(token.Name))
+
+	spec, ok := lookupToken(token.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown token {%s} (see `renamer template --help` for the token library)", token.Name)
+	}
+	if spec.validate != nil {
+		if err := spec.validate(token.Arg); err != nil {
+			return nil, fmt.Errorf("token {%s}: %w", body, err)
+		}
+	}
+
+	for _, raw := range parts[1:] {
+		fields := strings.Split(strings.TrimSpace(raw), ":")
+		filter := Filter{Name: strings.ToLower(fields[0]), Args: fields[1:]}
+		if err := validateFilter(filter); err != nil {
+			return nil, fmt.Errorf("token {%s}: %w", body, err)
+		}
+		token.Filters = append(token.Filters, filter)
+	}
+
+	return token, nil
+}
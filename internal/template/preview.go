@@ -0,0 +1,103 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Preview computes planned template renames, renders preview output, and returns the summary.
+func Preview(ctx context.Context, req *Request, out io.Writer) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("template request cannot be nil")
+	}
+
+	summary, operations, err := BuildPlan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary.LedgerMetadata["template"] = req.Template.String()
+	if req.Template.usesTags() {
+		summary.LedgerMetadata["missing"] = string(req.Missing)
+		if req.Missing == MissingPlaceholder {
+			summary.LedgerMetadata["placeholder"] = req.Placeholder
+		}
+	}
+	scope := map[string]any{
+		"includeDirs":   req.IncludeDirs,
+		"recursive":     req.Recursive,
+		"includeHidden": req.IncludeHidden,
+	}
+	if len(req.ExtensionFilter) > 0 {
+		scope["extensionFilter"] = append([]string(nil), req.ExtensionFilter...)
+	}
+	if nameFilter := req.NameFilter.Metadata(); nameFilter != nil {
+		scope["nameFilter"] = nameFilter
+	}
+	summary.LedgerMetadata["scope"] = scope
+
+	if out != nil {
+		conflictReasons := make(map[string]string, len(summary.Conflicts))
+		for _, conflict := range summary.Conflicts {
+			conflictReasons[conflict.OriginalPath+"->"+conflict.ProposedPath] = conflict.Reason
+		}
+
+		for _, entry := range summary.Entries {
+			switch entry.Status {
+			case StatusChanged:
+				fmt.Fprintf(out, "%s -> %s\n", entry.OriginalPath, entry.ProposedPath)
+				writeSources(out, entry.Segments)
+			case StatusNoChange:
+				fmt.Fprintf(out, "%s (no change)\n", entry.OriginalPath)
+			case StatusSkipped:
+				reason := entry.Reason
+				if reason == "" {
+					reason = conflictReasons[entry.OriginalPath+"->"+entry.ProposedPath]
+				}
+				if reason == "" {
+					reason = "skipped"
+				}
+				if entry.ProposedPath == entry.OriginalPath {
+					fmt.Fprintf(out, "%s (skipped: %s)\n", entry.OriginalPath, reason)
+					continue
+				}
+				fmt.Fprintf(out, "%s -> %s (skipped: %s)\n", entry.OriginalPath, entry.ProposedPath, reason)
+			}
+		}
+		summary.Sidecars.WritePreview(out)
+		summary.References.WritePreview(out)
+
+		if summary.TotalCandidates > 0 {
+			fmt.Fprintf(out, "\nSummary: %d candidates, %d will change, %d unchanged",
+				summary.TotalCandidates, summary.TotalChanged, summary.NoChange)
+			if summary.Skipped > 0 {
+				fmt.Fprintf(out, ", %d skipped", summary.Skipped)
+			}
+			fmt.Fprintln(out)
+		} else {
+			fmt.Fprintln(out, "No candidates found.")
+		}
+
+		if len(summary.Warnings) > 0 {
+			fmt.Fprintln(out)
+			for _, warning := range summary.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+		}
+	}
+
+	return summary, operations, nil
+}
+
+// writeSources lists the metadata field behind each placeholder that read file metadata, so the
+// user can see which tag produced each part of the name.
+func writeSources(out io.Writer, segments []RenderedSegment) {
+	for _, segment := range segments {
+		if segment.Source == "" {
+			continue
+		}
+		fmt.Fprintf(out, "    {%s} = %q (%s)\n", segment.Token, segment.Value, segment.Source)
+	}
+}
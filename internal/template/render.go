@@ -0,0 +1,50 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderedSegment records the text a placeholder produced, for preview explanations.
+type RenderedSegment struct {
+	Token string
+	Value string
+	// Source names the metadata field behind the value (e.g. "ID3v2.3 TPE1"), empty for tokens
+	// derived from the name or filesystem.
+	Source string
+}
+
+// Render evaluates the template for one candidate.
+func (t *Template) Render(ctx *Context) (string, []RenderedSegment, error) {
+	var builder strings.Builder
+	rendered := make([]RenderedSegment, 0, len(t.segments))
+
+	for _, seg := range t.segments {
+		if seg.token == nil {
+			builder.WriteString(seg.literal)
+			continue
+		}
+
+		spec, ok := lookupToken(seg.token.Name)
+		if !ok {
+			return "", nil, fmt.Errorf("unknown token {%s}", seg.token.Name)
+		}
+		value, err := spec.render(ctx, seg.token.Arg)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, filter := range seg.token.Filters {
+			value = applyFilter(value, filter)
+		}
+
+		source := ""
+		if spec.explain != nil {
+			source = spec.explain(ctx, seg.token.Arg)
+		}
+
+		builder.WriteString(value)
+		rendered = append(rendered, RenderedSegment{Token: seg.token.Raw, Value: value, Source: source})
+	}
+
+	return builder.String(), rendered, nil
+}
@@ -0,0 +1,127 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Request encapsulates the inputs required to run a template rename.
+type Request struct {
+	WorkingDir      string
+	Template        *Template
+	Missing         MissingPolicy
+	Placeholder     string
+	Progress        contenthash.Progress
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest constructs a Request from shared listing scope.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode updates dry-run and auto-apply preferences.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetTemplate stores the parsed template.
+func (r *Request) SetTemplate(tmpl *Template) {
+	r.Template = tmpl
+}
+
+// SetMissingPolicy configures how audio tag tokens without a value are handled.
+func (r *Request) SetMissingPolicy(policy MissingPolicy, placeholder string) {
+	r.Missing = policy
+	r.Placeholder = placeholder
+}
+
+// SetProgress registers a callback for content hashing progress.
+func (r *Request) SetProgress(progress contenthash.Progress) {
+	r.Progress = progress
+}
+
+// Normalize ensures working directory, template, and timestamp fields are ready for execution.
+func (r *Request) Normalize() error {
+	if r.Template == nil {
+		return errors.New("template is required")
+	}
+
+	if r.Missing == "" {
+		r.Missing = MissingFail
+	}
+	if r.Missing == MissingPlaceholder {
+		if r.Placeholder == "" {
+			r.Placeholder = DefaultPlaceholder
+		}
+		if strings.ContainsAny(r.Placeholder, "/\\") {
+			return errors.New("placeholder cannot contain path separators")
+		}
+	}
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+// MissingPolicy decides what happens when a candidate lacks an audio tag the template uses.
+type MissingPolicy string
+
+const (
+	// MissingFail reports the candidate as a conflict, blocking apply.
+	MissingFail MissingPolicy = "fail"
+	// MissingSkip leaves the candidate unchanged.
+	MissingSkip MissingPolicy = "skip"
+	// MissingPlaceholder substitutes a placeholder value.
+	MissingPlaceholder MissingPolicy = "placeholder"
+)
+
+// DefaultPlaceholder is substituted for missing tags under MissingPlaceholder.
+const DefaultPlaceholder = "Unknown"
+
+// ParseMissingPolicy validates a --missing value.
+func ParseMissingPolicy(value string) (MissingPolicy, error) {
+	switch policy := MissingPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case MissingFail, MissingSkip, MissingPlaceholder:
+		return policy, nil
+	case "":
+		return MissingFail, nil
+	default:
+		return "", fmt.Errorf("unsupported missing-tag policy %q (use fail, skip, or placeholder)", value)
+	}
+}
+
+// MissingTagError reports a tag token that had no value for a candidate.
+type MissingTagError struct {
+	Tag string
+}
+
+func (e *MissingTagError) Error() string {
+	return fmt.Sprintf("missing_tag: %s", e.Tag)
+}
+
+// sourcePlaceholder labels segments filled by the missing-tag placeholder.
+const sourcePlaceholder = "placeholder"
+
+var tagDescriptions = map[string]string{
+	metadata.TagArtist:      "track artist (ID3 TPE1, Vorbis ARTIST, MP4 ©ART)",
+	metadata.TagAlbum:       "album title (ID3 TALB, Vorbis ALBUM, MP4 ©alb)",
+	metadata.TagAlbumArtist: "album artist (ID3 TPE2, Vorbis ALBUMARTIST, MP4 aART)",
+	metadata.TagTitle:       "track title (ID3 TIT2, Vorbis TITLE, MP4 ©nam)",
+	metadata.TagTrack:       "track number, optionally zero-padded: {track:02}",
+	metadata.TagDisc:        "disc number, optionally zero-padded: {disc:02}",
+	metadata.TagYear:        "release year (ID3 TYER/TDRC, Vorbis DATE, MP4 ©day)",
+	metadata.TagGenre:       "genre (ID3 TCON, Vorbis GENRE, MP4 ©gen)",
+}
+
+func init() {
+	for _, field := range metadata.TagFields {
+		field := field
+		numeric := field == metadata.TagTrack || field == metadata.TagDisc
+		spec := tokenSpec{
+			usage:       "{" + field + "}",
+			description: tagDescriptions[field],
+			validate:    noArg,
+			render: func(ctx *Context, arg string) (string, error) {
+				return renderTag(ctx, field, arg)
+			},
+			explain: func(ctx *Context, _ string) string {
+				return tagSource(ctx, field)
+			},
+		}
+		if numeric {
+			spec.usage = "{" + field + ":WIDTH}"
+			spec.validate = validateWidth
+		}
+		registry[field] = spec
+	}
+}
+
+// AudioTags returns the candidate's audio tags, reading the file at most once.
+func (c *Context) AudioTags() (metadata.Tags, error) {
+	if !c.tagsLoaded {
+		if !c.IsDir {
+			tags, _, err := metadata.AudioTags(c.AbsolutePath())
+			if err != nil {
+				return nil, err
+			}
+			c.tags = tags
+		}
+		c.tagsLoaded = true
+	}
+	return c.tags, nil
+}
+
+func renderTag(ctx *Context, field, arg string) (string, error) {
+	tags, err := ctx.AudioTags()
+	if err != nil {
+		return "", err
+	}
+	tag, ok := tags[field]
+	if !ok {
+		if ctx.Missing == MissingPlaceholder {
+			return ctx.Placeholder, nil
+		}
+		return "", &MissingTagError{Tag: field}
+	}
+
+	value := tag.Value
+	if arg != "" {
+		width, _ := strconv.Atoi(arg)
+		if number, convErr := strconv.Atoi(value); convErr == nil {
+			value = fmt.Sprintf("%0*d", width, number)
+		}
+	}
+	// Tag values such as "AC/DC" must not introduce directories, and control characters (NUL
+	// included) have no place in a file name.
+	value = strings.NewReplacer("/", "_", "\\", "_").Replace(value)
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value), nil
+}
+
+func tagSource(ctx *Context, field string) string {
+	if tag, ok := ctx.tags[field]; ok {
+		return tag.Source
+	}
+	return sourcePlaceholder
+}
+
+func validateWidth(arg string) error {
+	if arg == "" {
+		return nil
+	}
+	width, err := strconv.Atoi(arg)
+	if err != nil || width < 1 {
+		return errors.New("width must be a positive integer")
+	}
+	return nil
+}
+
+// usesTags reports whether any placeholder reads audio tags.
+func (t *Template) usesTags() bool {
+	for _, field := range metadata.TagFields {
+		if t.Uses(field) {
+			return true
+		}
+	}
+	return false
+}
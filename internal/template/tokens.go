@@ -0,0 +1,259 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+// Context carries the attributes of one candidate while rendering.
+type Context struct {
+	WorkingDir string
+	// RelativePath is the slash-separated path relative to WorkingDir.
+	RelativePath string
+	Name         string
+	Stem         string
+	Ext          string
+	IsDir        bool
+	Info         fs.FileInfo
+	// Index is the zero-based position of the candidate in traversal order.
+	Index int
+	// Missing and Placeholder control audio tag tokens that have no value.
+	Missing     MissingPolicy
+	Placeholder string
+
+	capture       time.Time
+	captureSource metadata.Source
+	captureLoaded bool
+	tags          metadata.Tags
+	tagsLoaded    bool
+	hashes        map[contenthash.Algorithm]string
+	mimeType      string
+}
+
+// NewContext derives name parts for the entry at relativePath, splitting the extension with
+// extensions so compound ones such as .tar.gz stay whole.
+func NewContext(workingDir, relativePath string, info fs.FileInfo, index int, extensions fileext.Model) *Context {
+	name := path.Base(relativePath)
+	ctx := &Context{
+		WorkingDir:   workingDir,
+		RelativePath: relativePath,
+		Name:         name,
+		Stem:         name,
+		IsDir:        info != nil && info.IsDir(),
+		Info:         info,
+		Index:        index,
+	}
+	if !ctx.IsDir {
+		ctx.Stem, ctx.Ext = extensions.Split(name)
+	}
+	return ctx
+}
+
+// AbsolutePath returns the candidate's path on disk.
+func (c *Context) AbsolutePath() string {
+	return filepath.Join(c.WorkingDir, filepath.FromSlash(c.RelativePath))
+}
+
+// CaptureTime returns the media capture time (falling back to mtime), reading metadata at most
+// once per candidate.
+func (c *Context) CaptureTime() (time.Time, error) {
+	if !c.captureLoaded {
+		ts, source, err := metadata.CaptureTime(c.AbsolutePath(), c.Info)
+		if err != nil {
+			return time.Time{}, err
+		}
+		c.capture, c.captureSource, c.captureLoaded = ts, source, true
+	}
+	return c.capture, nil
+}
+
+// tokenSpec describes a registered token.
+type tokenSpec struct {
+	usage       string
+	description string
+	validate    func(arg string) error
+	render      func(ctx *Context, arg string) (string, error)
+	// explain optionally names the metadata a rendered value came from, for previews.
+	explain func(ctx *Context, arg string) string
+}
+
+var registry = map[string]tokenSpec{
+	"name": {
+		usage:       "{name}",
+		description: "full base name including the extension",
+		validate:    noArg,
+		render:      func(ctx *Context, _ string) (string, error) { return ctx.Name, nil },
+	},
+	"stem": {
+		usage:       "{stem}",
+		description: "base name without the extension (whole name for directories)",
+		validate:    noArg,
+		render:      func(ctx *Context, _ string) (string, error) { return ctx.Stem, nil },
+	},
+	"ext": {
+		usage:       "{ext}",
+		description: "extension including the leading dot, empty for directories",
+		validate:    noArg,
+		render:      func(ctx *Context, _ string) (string, error) { return ctx.Ext, nil },
+	},
+	"parent": {
+		usage:       "{parent}",
+		description: "name of the containing directory",
+		validate:    noArg,
+		render:      renderParent,
+	},
+	"reldir": {
+		usage:       "{reldir}",
+		description: "slash-separated directory relative to --path, empty at the root",
+		validate:    noArg,
+		render: func(ctx *Context, _ string) (string, error) {
+			dir := path.Dir(ctx.RelativePath)
+			if dir == "." {
+				return "", nil
+			}
+			return dir, nil
+		},
+	},
+	"n": {
+		usage:       "{n:WIDTH:START:STEP}",
+		description: "counter in traversal order; e.g. {n:03} or {n:2:10:5} (defaults width 1, start 1, step 1)",
+		validate: func(arg string) error {
+			_, _, _, err := parseCounter(arg)
+			return err
+		},
+		render: func(ctx *Context, arg string) (string, error) {
+			width, start, step, err := parseCounter(arg)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%0*d", width, start+ctx.Index*step), nil
+		},
+	},
+	"size": {
+		usage:       "{size}",
+		description: "size in bytes",
+		validate:    noArg,
+		render: func(ctx *Context, _ string) (string, error) {
+			if ctx.Info == nil {
+				return "", fmt.Errorf("size unavailable for %s", ctx.RelativePath)
+			}
+			return strconv.FormatInt(ctx.Info.Size(), 10), nil
+		},
+	},
+	"mtime": {
+		usage:       "{mtime:LAYOUT}",
+		description: "modification time formatted with a Go layout (default 2006-01-02)",
+		render: func(ctx *Context, layout string) (string, error) {
+			if ctx.Info == nil {
+				return "", fmt.Errorf("mtime unavailable for %s", ctx.RelativePath)
+			}
+			return ctx.Info.ModTime().Format(timeLayout(layout)), nil
+		},
+	},
+	"exif": {
+		usage:       "{exif:LAYOUT}",
+		description: "capture time from EXIF, QuickTime/MP4, or PNG metadata, falling back to mtime",
+		render: func(ctx *Context, layout string) (string, error) {
+			ts, err := ctx.CaptureTime()
+			if err != nil {
+				return "", err
+			}
+			return ts.Format(timeLayout(layout)), nil
+		},
+		explain: func(ctx *Context, _ string) string { return string(ctx.captureSource) },
+	},
+	"ctime": {
+		usage:       "{ctime:LAYOUT}",
+		description: "inode change time (mtime where unsupported) formatted with a Go layout",
+		render: func(ctx *Context, layout string) (string, error) {
+			if ctx.Info == nil {
+				return "", fmt.Errorf("ctime unavailable for %s", ctx.RelativePath)
+			}
+			return changeTime(ctx.Info).Format(timeLayout(layout)), nil
+		},
+	},
+}
+
+func lookupToken(name string) (tokenSpec, bool) {
+	spec, ok := registry[name]
+	return spec, ok
+}
+
+// TokenDoc documents a registered token for help output.
+type TokenDoc struct {
+	Name        string
+	Usage       string
+	Description string
+}
+
+// Library lists the registered tokens sorted by name.
+func Library() []TokenDoc {
+	docs := make([]TokenDoc, 0, len(registry))
+	for name, spec := range registry {
+		docs = append(docs, TokenDoc{Name: name, Usage: spec.usage, Description: spec.description})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
+
+func noArg(arg string) error {
+	if arg != "" {
+		return fmt.Errorf("takes no argument")
+	}
+	return nil
+}
+
+func renderParent(ctx *Context, _ string) (string, error) {
+	dir := path.Dir(ctx.RelativePath)
+	if dir == "." {
+		return path.Base(strings.ReplaceAll(ctx.WorkingDir, "\\", "/")), nil
+	}
+	return path.Base(dir), nil
+}
+
+func timeLayout(layout string) string {
+	if layout == "" {
+		return "2006-01-02"
+	}
+	return layout
+}
+
+// parseCounter reads WIDTH:START:STEP, each part optional.
+func parseCounter(arg string) (width, start, step int, err error) {
+	width, start, step = 1, 1, 1
+	if arg == "" {
+		return width, start, step, nil
+	}
+	parts := strings.Split(arg, ":")
+	if len(parts) > 3 {
+		return 0, 0, 0, fmt.Errorf("counter accepts at most WIDTH:START:STEP")
+	}
+	values := []*int{&width, &start, &step}
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		value, convErr := strconv.Atoi(part)
+		if convErr != nil {
+			return 0, 0, 0, fmt.Errorf("invalid counter value %q", part)
+		}
+		*values[i] = value
+	}
+	if width < 1 {
+		return 0, 0, 0, fmt.Errorf("counter width must be at least 1")
+	}
+	if step == 0 {
+		return 0, 0, 0, fmt.Errorf("counter step cannot be zero")
+	}
+	return width, start, step, nil
+}
@@ -0,0 +1,3 @@
+// Package truncate implements the `renamer truncate` engine, shortening names that exceed byte
+// limits while preserving extensions and disambiguating collisions with a short content-free hash.
+package truncate
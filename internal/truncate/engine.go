@@ -0,0 +1,252 @@
+package truncate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rogeecn/renamer/internal/sidecar"
+	"github.com/rogeecn/renamer/internal/traversal"
+)
+
+// PlannedOperation captures a filesystem rename to be applied.
+type PlannedOperation struct {
+	OriginalRelative string
+	OriginalAbsolute string
+	ProposedRelative string
+	ProposedAbsolute string
+	IsDir            bool
+	Depth            int
+}
+
+type candidate struct {
+	relative string
+	name     string
+	isDir    bool
+	depth    int
+}
+
+// BuildPlan enumerates candidates, shortens names that exceed the limits, and disambiguates
+// truncation collisions with a short hash of the original name. Every member of a colliding
+// group is hashed, so the result does not depend on traversal order.
+func BuildPlan(ctx context.Context, req *Request) (*Summary, []PlannedOperation, error) {
+	if req == nil {
+		return nil, nil, errors.New("truncate request cannot be nil")
+	}
+	if err := req.Normalize(); err != nil {
+		return nil, nil, err
+	}
+
+	summary := NewSummary()
+	operations := make([]PlannedOperation, 0)
+
+	filterSet := make(map[string]struct{}, len(req.ExtensionFilter))
+	for _, ext := range req.ExtensionFilter {
+		filterSet[strings.ToLower(ext)] = struct{}{}
+	}
+
+	candidates := make([]candidate, 0)
+	walker := traversal.NewWalker()
+
+	err := walker.Walk(
+		req.WorkingDir,
+		req.Recursive,
+		req.IncludeDirs,
+		req.IncludeHidden,
+		0,
+		func(relPath string, entry fs.DirEntry, depth int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if relPath == "." {
+				return nil
+			}
+
+			isDir := entry.IsDir()
+			if isDir && !req.IncludeDirs {
+				return nil
+			}
+
+			relative := filepath.ToSlash(relPath)
+			name := entry.Name()
+
+			if !isDir && !req.ExtensionModel.Allows(name, filterSet) {
+				return nil
+			}
+
+			if !req.NameFilter.Allows(relative, isDir) || (!isDir && req.Sidecars.IsFollower(relative)) {
+				return nil
+			}
+
+			candidates = append(candidates, candidate{relative: relative, name: name, isDir: isDir, depth: depth})
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Names that already fit are reserved first so a truncated name never claims a sibling's slot.
+	budgets := make([]int, len(candidates))
+	planned := make(map[string]string, len(candidates))
+	for i, c := range candidates {
+		budgets[i] = nameBudget(req, c.relative)
+		if len(c.name) <= budgets[i] {
+			planned[strings.ToLower(c.relative)] = c.relative
+		}
+	}
+
+	// Count how many shortened names land on each target before choosing any of them.
+	fitted := make([]string, len(candidates))
+	claims := make(map[string]int, len(candidates))
+	for i, c := range candidates {
+		if len(c.name) <= budgets[i] {
+			continue
+		}
+		if name, ok := Fit(c.name, c.isDir, budgets[i], req.ExtensionModel); ok {
+			fitted[i] = name
+			claims[strings.ToLower(joinRelative(c.relative, name))]++
+		}
+	}
+
+	for i, c := range candidates {
+		preview := PreviewEntry{
+			OriginalPath:  c.relative,
+			ProposedPath:  c.relative,
+			Status:        StatusNoChange,
+			OriginalBytes: len(c.name),
+			ProposedBytes: len(c.name),
+		}
+
+		if len(c.name) <= budgets[i] {
+			summary.RecordEntry(preview)
+			continue
+		}
+
+		skip := func(proposed, reason string) {
+			preview.ProposedPath = proposed
+			preview.Status = StatusSkipped
+			summary.AddConflict(Conflict{OriginalPath: c.relative, ProposedPath: proposed, Reason: reason})
+			summary.RecordEntry(preview)
+		}
+
+		name := fitted[i]
+		if name == "" {
+			skip(c.relative, "extension_exceeds_limit")
+			continue
+		}
+		proposedRelative := joinRelative(c.relative, name)
+
+		// A target shared by several shortened names is never given to any one of them.
+		free := claims[strings.ToLower(proposedRelative)] == 1
+		if free {
+			var err error
+			if free, err = available(req.WorkingDir, planned, c.relative, proposedRelative); err != nil {
+				return nil, nil, err
+			}
+		}
+		if !free {
+			hashed, ok := FitWithHash(c.name, c.isDir, budgets[i], req.ExtensionModel)
+			if !ok {
+				skip(proposedRelative, fmt.Sprintf("limit_too_small_to_disambiguate (needs %d bytes)", hashedMinimum(c.name, c.isDir, req.ExtensionModel)))
+				continue
+			}
+			hashedRelative := joinRelative(c.relative, hashed)
+			free, err := available(req.WorkingDir, planned, c.relative, hashedRelative)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !free {
+				skip(hashedRelative, "duplicate_target")
+				continue
+			}
+			name, proposedRelative = hashed, hashedRelative
+			preview.Hashed = true
+		}
+
+		planned[strings.ToLower(proposedRelative)] = c.relative
+		preview.ProposedPath = proposedRelative
+		preview.ProposedBytes = len(name)
+		preview.Status = StatusChanged
+		operations = append(operations, PlannedOperation{
+			OriginalRelative: c.relative,
+			OriginalAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(c.relative)),
+			ProposedRelative: proposedRelative,
+			ProposedAbsolute: filepath.Join(req.WorkingDir, filepath.FromSlash(proposedRelative)),
+			IsDir:            c.isDir,
+			Depth:            c.depth,
+		})
+		summary.RecordEntry(preview)
+	}
+
+	sort.SliceStable(summary.Entries, func(i, j int) bool {
+		return summary.Entries[i].OriginalPath < summary.Entries[j].OriginalPath
+	})
+
+	renames := make([]sidecar.Rename, 0, len(operations))
+	for _, op := range operations {
+		renames = append(renames, sidecar.Rename{From: op.OriginalRelative, To: op.ProposedRelative})
+	}
+	summary.Sidecars = req.Sidecars.Expand(req.WorkingDir, renames)
+	for _, conflict := range summary.Sidecars.Conflicts {
+		summary.AddConflict(Conflict{OriginalPath: conflict.From, ProposedPath: conflict.To, Reason: conflict.Reason})
+	}
+	refs, err := req.References.Plan(renames, summary.Sidecars.Renames)
+	if err != nil {
+		return nil, nil, err
+	}
+	summary.References = refs
+
+	return summary, operations, nil
+}
+
+// nameBudget returns the byte budget for the final component of relative. The path budget is
+// computed against the original parent directory, so deep entries may be shortened more than
+// strictly necessary when their parents are truncated in the same run.
+func nameBudget(req *Request, relative string) int {
+	budget := req.MaxNameBytes
+	if req.MaxPathBytes > 0 {
+		parent := filepath.Dir(filepath.Join(req.WorkingDir, filepath.FromSlash(relative)))
+		if remaining := req.MaxPathBytes - len(parent) - 1; remaining < budget {
+			budget = remaining
+		}
+	}
+	return budget
+}
+
+// available reports whether proposedRel is free: not claimed by another planned target (compared
+// case-insensitively) and not an existing entry other than the candidate itself.
+func available(workingDir string, planned map[string]string, candidateRel, proposedRel string) (bool, error) {
+	if existing, ok := planned[strings.ToLower(proposedRel)]; ok && existing != candidateRel {
+		return false, nil
+	}
+
+	info, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(proposedRel)))
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	origInfo, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(candidateRel)))
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(info, origInfo), nil
+}
+
+func joinRelative(relative, name string) string {
+	dir := filepath.Dir(filepath.FromSlash(relative))
+	if dir == "." {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(filepath.Join(dir, name))
+}
@@ -0,0 +1,76 @@
+package truncate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+)
+
+// hashLength is the number of hex digits appended when truncation causes a collision.
+const hashLength = 8
+
+// hashSeparator joins the truncated stem and the disambiguating hash.
+const hashSeparator = "~"
+
+// splitName separates the stem from the extension, keeping compound extensions such as .tar.gz
+// whole. Directories and dotfiles such as ".env" are treated as all stem.
+func splitName(name string, isDir bool, extensions fileext.Model) (string, string) {
+	if isDir {
+		return name, ""
+	}
+	return extensions.Split(name)
+}
+
+// cutAtRune returns the longest prefix of value that fits in budget bytes without splitting a
+// UTF-8 sequence.
+func cutAtRune(value string, budget int) string {
+	if budget <= 0 {
+		return ""
+	}
+	if len(value) <= budget {
+		return value
+	}
+	cut := budget
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+// Fit shortens name so it occupies at most budget bytes, cutting the stem at a rune boundary and
+// keeping the extension as split by extensions. It reports false when not even one rune of the
+// stem fits beside the extension.
+func Fit(name string, isDir bool, budget int, extensions fileext.Model) (string, bool) {
+	if len(name) <= budget {
+		return name, true
+	}
+	stem, ext := splitName(name, isDir, extensions)
+	cut := cutAtRune(stem, budget-len(ext))
+	if cut == "" {
+		return "", false
+	}
+	return cut + ext, true
+}
+
+// FitWithHash behaves like Fit but reserves room for a short hash of the original name between the
+// truncated stem and the extension, e.g. "very-long-na~1a2b3c4d.txt".
+func FitWithHash(name string, isDir bool, budget int, extensions fileext.Model) (string, bool) {
+	stem, ext := splitName(name, isDir, extensions)
+	sum := sha256.Sum256([]byte(name))
+	suffix := hashSeparator + hex.EncodeToString(sum[:])[:hashLength]
+	cut := cutAtRune(stem, budget-len(ext)-len(suffix))
+	if cut == "" {
+		return "", false
+	}
+	return cut + suffix + ext, true
+}
+
+// hashedMinimum returns the smallest budget FitWithHash can satisfy for name: one rune of the
+// stem, the hash, and the extension.
+func hashedMinimum(name string, isDir bool, extensions fileext.Model) int {
+	stem, ext := splitName(name, isDir, extensions)
+	_, first := utf8.DecodeRuneInString(stem)
+	return first + len(hashSeparator) + hashLength + len(ext)
+}
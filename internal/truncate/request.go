@@ -0,0 +1,107 @@
+package truncate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/filters"
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/pathlimit"
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+// Request encapsulates the inputs required to truncate names.
+type Request struct {
+	WorkingDir      string
+	MaxNameBytes    int
+	MaxPathBytes    int
+	IncludeDirs     bool
+	Recursive       bool
+	IncludeHidden   bool
+	ExtensionFilter []string
+	ExtensionModel  fileext.Model
+	NameFilter      filters.NameFilter
+	Sidecars        *sidecar.Groups
+	References      *references.Rewriter
+	DryRun          bool
+	AutoConfirm     bool
+	Timestamp       time.Time
+}
+
+// NewRequest constructs a Request from shared listing scope.
+func NewRequest(scope *listing.ListingRequest) *Request {
+	if scope == nil {
+		return &Request{MaxNameBytes: pathlimit.DefaultMaxNameBytes, MaxPathBytes: pathlimit.DefaultMaxPathBytes}
+	}
+
+	return &Request{
+		WorkingDir:      scope.WorkingDir,
+		MaxNameBytes:    pathlimit.DefaultMaxNameBytes,
+		MaxPathBytes:    pathlimit.DefaultMaxPathBytes,
+		IncludeDirs:     scope.IncludeDirectories,
+		Recursive:       scope.Recursive,
+		IncludeHidden:   scope.IncludeHidden,
+		ExtensionFilter: append([]string(nil), scope.Extensions...),
+		ExtensionModel:  scope.ExtensionModel,
+		NameFilter:      scope.NameFilter,
+		Sidecars:        scope.Sidecars,
+		References:      scope.References,
+	}
+}
+
+// SetExecutionMode updates dry-run and auto-apply preferences.
+func (r *Request) SetExecutionMode(dryRun, autoConfirm bool) {
+	r.DryRun = dryRun
+	r.AutoConfirm = autoConfirm
+}
+
+// SetLimits stores the per-name and whole-path byte budgets. A zero path budget disables the
+// path check.
+func (r *Request) SetLimits(maxNameBytes, maxPathBytes int) {
+	r.MaxNameBytes = maxNameBytes
+	r.MaxPathBytes = maxPathBytes
+}
+
+// Normalize ensures working directory, limits, and timestamp fields are ready for execution.
+func (r *Request) Normalize() error {
+	if r.MaxNameBytes <= 0 {
+		return fmt.Errorf("--max-bytes must be positive, got %d", r.MaxNameBytes)
+	}
+	if r.MaxPathBytes < 0 {
+		return fmt.Errorf("--max-path cannot be negative, got %d", r.MaxPathBytes)
+	}
+
+	if r.WorkingDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("determine working directory: %w", err)
+		}
+		r.WorkingDir = cwd
+	}
+
+	if !filepath.IsAbs(r.WorkingDir) {
+		abs, err := filepath.Abs(r.WorkingDir)
+		if err != nil {
+			return fmt.Errorf("resolve working directory: %w", err)
+		}
+		r.WorkingDir = abs
+	}
+
+	info, err := os.Stat(r.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("stat working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory %q is not a directory", r.WorkingDir)
+	}
+
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	return nil
+}
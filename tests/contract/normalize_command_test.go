@@ -0,0 +1,98 @@
+package contract
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/listing"
+	"github.com/rogeecn/renamer/internal/normalize"
+)
+
+func TestNormalizeConvertsNFDToNFC(t *testing.T) {
+	tmp := t.TempDir()
+	nfd := "re\u0301sume\u0301.txt"
+	createFile(t, filepath.Join(tmp, nfd))
+
+	req := normalize.NewRequest(normalizeScope(t, tmp))
+
+	summary, planned, err := normalize.Preview(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("preview error: %v", err)
+	}
+
+	if summary.HasConflicts() {
+		t.Fatalf("unexpected conflicts: %#v", summary.Conflicts)
+	}
+	if summary.Invisible != 1 {
+		t.Fatalf("expected 1 normalization-only change, got %d", summary.Invisible)
+	}
+	if len(planned) != 1 || planned[0].ProposedRelative != "r\u00e9sum\u00e9.txt" {
+		t.Fatalf("unexpected plan: %#v", planned)
+	}
+
+	entry, err := normalize.Apply(context.Background(), req, planned, summary)
+	if err != nil {
+		t.Fatalf("apply error: %v", err)
+	}
+	if entry.Command != "normalize" || entry.Metadata["form"] != "nfc" {
+		t.Fatalf("unexpected ledger entry: %#v", entry)
+	}
+}
+
+func TestNormalizeASCIITransliteration(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "Straße Café.txt"))
+	createFile(t, filepath.Join(tmp, "Grüße.txt"))
+
+	req := normalize.NewRequest(normalizeScope(t, tmp))
+	req.SetTransliteration(true, map[string]string{"ü": "ue"}, "_")
+
+	summary, planned, err := normalize.Preview(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("preview error: %v", err)
+	}
+	if summary.TotalChanged != 2 {
+		t.Fatalf("expected 2 changes, got %d", summary.TotalChanged)
+	}
+
+	targets := map[string]bool{}
+	for _, op := range planned {
+		targets[op.ProposedRelative] = true
+	}
+	for _, expected := range []string{"Strasse Cafe.txt", "Gruesse.txt"} {
+		if !targets[expected] {
+			t.Fatalf("expected target %s in plan %#v", expected, planned)
+		}
+	}
+}
+
+func TestNormalizeReportsCollidingTargets(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "café.txt"))
+	createFile(t, filepath.Join(tmp, "cafè.txt"))
+
+	req := normalize.NewRequest(normalizeScope(t, tmp))
+	req.SetTransliteration(true, nil, "_")
+
+	summary, _, err := normalize.Preview(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("preview error: %v", err)
+	}
+	if len(summary.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %#v", summary.Conflicts)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "cafe.txt")); err == nil {
+		t.Fatalf("preview must not touch the filesystem")
+	}
+}
+
+func normalizeScope(t *testing.T, dir string) *listing.ListingRequest {
+	t.Helper()
+	scope := &listing.ListingRequest{WorkingDir: dir, Format: listing.FormatTable}
+	if err := scope.Validate(); err != nil {
+		t.Fatalf("validate scope: %v", err)
+	}
+	return scope
+}
@@ -25,7 +25,7 @@ func TestSequencePreviewWithPrefixPlacement(t *testing.T) {
 		t.Fatalf("preview error: %v", err)
 	}
 
-	expected := []string{"010-cover.png", "011-index.png"}
+	expected := []string{"10-cover.png", "11-index.png"}
 	if len(plan.Candidates) != len(expected) {
 		t.Fatalf("expected %d candidates, got %d", len(expected), len(plan.Candidates))
 	}
@@ -60,7 +60,7 @@ func TestSequencePreviewWithNumberPrefixLabel(t *testing.T) {
 		t.Fatalf("preview error: %v", err)
 	}
 
-	expected := []string{"seq001-cover.png", "seq002-index.png"}
+	expected := []string{"seq1-cover.png", "seq2-index.png"}
 	if len(plan.Candidates) != len(expected) {
 		t.Fatalf("expected %d candidates, got %d", len(expected), len(plan.Candidates))
 	}
@@ -30,7 +30,7 @@ func TestSequencePreviewDefaultNumbering(t *testing.T) {
 		t.Fatalf("expected 3 renamed entries, got %d", plan.Summary.RenamedCount)
 	}
 
-	expected := []string{"001_draft.txt", "002_notes.txt", "003_plan.txt"}
+	expected := []string{"1_draft.txt", "2_notes.txt", "3_plan.txt"}
 	if len(plan.Candidates) != 3 {
 		t.Fatalf("expected 3 planned candidates, got %d", len(plan.Candidates))
 	}
@@ -40,8 +40,8 @@ func TestSequencePreviewDefaultNumbering(t *testing.T) {
 		}
 	}
 
-	if plan.Summary.AppliedWidth != 3 {
-		t.Fatalf("expected applied width 3, got %d", plan.Summary.AppliedWidth)
+	if plan.Summary.AppliedWidth != 1 {
+		t.Fatalf("expected applied width 1, got %d", plan.Summary.AppliedWidth)
 	}
 
 	if plan.Config.Start != 1 {
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+)
+
+func TestCaseOnlyRenameAppliesAndUndoes(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "foo bar.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "Report.PDF"))
+
+	var applyOut bytes.Buffer
+	apply := renamercmd.NewRootCommand()
+	apply.SetOut(&applyOut)
+	apply.SetErr(&applyOut)
+	apply.SetArgs([]string{"case", "title", "--apply-to", "both", "--yes", "--path", tmp})
+
+	if err := apply.Execute(); err != nil {
+		t.Fatalf("case command failed: %v\noutput: %s", err, applyOut.String())
+	}
+	if !strings.Contains(applyOut.String(), "(case only)") {
+		t.Fatalf("expected case-only annotation in preview: %s", applyOut.String())
+	}
+
+	assertDirNames(t, tmp, "Foo Bar.Txt", "Report.Pdf")
+
+	var undoOut bytes.Buffer
+	undo := renamercmd.NewRootCommand()
+	undo.SetOut(&undoOut)
+	undo.SetErr(&undoOut)
+	undo.SetArgs([]string{"undo", "--path", tmp})
+
+	if err := undo.Execute(); err != nil {
+		t.Fatalf("undo command failed: %v\noutput: %s", err, undoOut.String())
+	}
+
+	assertDirNames(t, tmp, "Report.PDF", "foo bar.txt")
+}
+
+func TestCaseConversionDetectsFoldedCollisions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "fooBar.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "foo_bar.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"case", "snake", "--yes", "--path", tmp})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected conflict error, output: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "skipped") {
+		t.Fatalf("expected skipped conflict in preview: %s", out.String())
+	}
+}
+
+func assertDirNames(t *testing.T, dir string, expected ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() == ".renamer" {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected entries %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/ai"
+	"github.com/rogeecn/renamer/internal/ai/flow"
+	"github.com/rogeecn/renamer/internal/sequence"
+)
+
+func TestCompoundExtensionsStayWholeAcrossEngines(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"backup.tar.gz", "index.d.ts", "bundle.tar.lz"} {
+		createFile(t, filepath.Join(tmp, name))
+	}
+
+	output, err := runRenamer(t, "insert", "$", "_v2", "--path", tmp, "--compound-ext", ".tar.lz")
+	if err != nil {
+		t.Fatalf("insert preview failed: %v\n%s", err, output)
+	}
+	for _, want := range []string{"backup.tar.gz -> backup_v2.tar.gz", "index.d.ts -> index_v2.d.ts", "bundle.tar.lz -> bundle_v2.tar.lz"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in insert preview:\n%s", want, output)
+		}
+	}
+
+	output, err = runRenamer(t, "case", "upper", "--path", tmp)
+	if err != nil {
+		t.Fatalf("case preview failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "backup.tar.gz -> BACKUP.tar.gz") {
+		t.Fatalf("expected case to leave the compound extension alone:\n%s", output)
+	}
+
+	output, err = runRenamer(t, "regex", "^(\\w+)$", "old_@1", "--path", tmp)
+	if err != nil {
+		t.Fatalf("regex preview failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "backup.tar.gz -> old_backup.tar.gz") {
+		t.Fatalf("expected regex to match the stem before .tar.gz:\n%s", output)
+	}
+
+	opts := sequence.DefaultOptions()
+	opts.WorkingDir = tmp
+	opts.Placement = sequence.PlacementSuffix
+	opts.Extensions = []string{".gz"}
+	assertProposals(t, sequenceProposals(t, opts), []string{"backup_1.tar.gz"})
+}
+
+func TestCompoundExtensionFilterAcrossEngines(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"backup 01.tar.gz", "notes 01.gz"} {
+		createFile(t, filepath.Join(tmp, name))
+	}
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"template", "{stem}_x{ext}"}, want: "backup 01.tar.gz -> backup 01_x.tar.gz"},
+		{args: []string{"pad", "--width", "3"}, want: "backup 01.tar.gz -> backup 001.tar.gz"},
+		{args: []string{"replace", "01", "02"}, want: "backup 01.tar.gz -> backup 02.tar.gz"},
+		{args: []string{"bucket", "--size", "1"}, want: "backup 01.tar.gz -> 1-1/backup 01.tar.gz"},
+	}
+	for _, tc := range cases {
+		args := append(tc.args, "-e", ".tar.gz", "--path", tmp)
+		output, err := runRenamer(t, args...)
+		if err != nil {
+			t.Fatalf("%s preview failed: %v\n%s", tc.args[0], err, output)
+		}
+		if !strings.Contains(output, tc.want) {
+			t.Fatalf("expected %q in %s preview:\n%s", tc.want, tc.args[0], output)
+		}
+		if strings.Contains(output, "notes 01.gz") {
+			t.Fatalf("expected -e .tar.gz to exclude plain .gz files in %s:\n%s", tc.args[0], output)
+		}
+	}
+}
+
+func TestExtensionTreatsCompoundExtensionsAsOneUnit(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "backup.tar.gz"))
+	createFile(t, filepath.Join(tmp, "log.gz"))
+
+	output, err := runRenamer(t, "extension", ".gz", ".zst", "--path", tmp)
+	if err != nil {
+		t.Fatalf("preview failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "log.gz -> log.zst") || strings.Contains(output, "backup.tar.zst") {
+		t.Fatalf("expected only the plain .gz file to change:\n%s", output)
+	}
+	if !strings.Contains(output, "backup.tar.gz keeps its compound extension .tar.gz") {
+		t.Fatalf("expected a compound-extension warning:\n%s", output)
+	}
+
+	if output, err = runRenamer(t, "extension", ".tar.gz", ".tgz", "--yes", "--path", tmp); err != nil {
+		t.Fatalf("apply failed: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "backup.tgz")); err != nil {
+		t.Fatalf("expected backup.tgz: %v", err)
+	}
+}
+
+func TestAIValidationRejectsDroppedCompoundExtension(t *testing.T) {
+	suggestions := []flow.Suggestion{{Original: "backup.tar.gz", Suggested: "archive.gz"}}
+	result := ai.ValidateSuggestions([]string{"backup.tar.gz"}, suggestions)
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Reason != "file extension changed" {
+		t.Fatalf("expected an extension conflict, got %+v", result)
+	}
+
+	suggestions[0].Suggested = "archive.tar.gz"
+	if result := ai.ValidateSuggestions([]string{"backup.tar.gz"}, suggestions); len(result.Conflicts) != 0 {
+		t.Fatalf("expected the compound extension to be accepted, got %+v", result.Conflicts)
+	}
+}
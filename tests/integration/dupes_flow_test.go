@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+)
+
+func writeIntegrationContent(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestDupesReportsIdenticalFiles(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	writeIntegrationContent(t, filepath.Join(tmp, "a.jpg"), "same bytes")
+	writeIntegrationContent(t, filepath.Join(tmp, "nested", "copy.jpg"), "same bytes")
+	writeIntegrationContent(t, filepath.Join(tmp, "near.jpg"), "same bytez")
+	writeIntegrationContent(t, filepath.Join(tmp, "empty-1.txt"), "")
+	writeIntegrationContent(t, filepath.Join(tmp, "empty-2.txt"), "")
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"dupes", "--recursive", "--algorithm", "xxhash", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("dupes command failed: %v\noutput: %s", err, out.String())
+	}
+
+	output := out.String()
+	for _, snippet := range []string{
+		"Group 1: 2 identical files, 10 B each (xxhash ",
+		"  a.jpg\n  nested/copy.jpg\n",
+		"Summary: 3 files scanned, 3 hashed, 1 duplicate groups, 1 redundant copies (10 B reclaimable)",
+	} {
+		if !strings.Contains(output, snippet) {
+			t.Fatalf("expected %q in output:\n%s", snippet, output)
+		}
+	}
+	if strings.Contains(output, "near.jpg") || strings.Contains(output, "empty-") {
+		t.Fatalf("expected only identical non-empty files to be grouped:\n%s", output)
+	}
+}
+
+func TestTemplateHashReportsDuplicateContent(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	writeIntegrationContent(t, filepath.Join(tmp, "first.txt"), "hello\n")
+	writeIntegrationContent(t, filepath.Join(tmp, "second.txt"), "hello\n")
+	writeIntegrationContent(t, filepath.Join(tmp, "third.txt"), "world\n")
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{hash:8}{ext}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected identical files to block apply")
+	}
+	if !strings.Contains(out.String(), "second.txt -> 5891b5b5.txt (skipped: duplicate_content with first.txt)") {
+		t.Fatalf("expected duplicate_content conflict, got:\n%s", out.String())
+	}
+	assertDirNames(t, tmp, "first.txt", "second.txt", "third.txt")
+
+	writeIntegrationContent(t, filepath.Join(tmp, "second.txt"), "hello again\n")
+	out.Reset()
+	cmd = renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{hash:blake3:10}{ext}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("template command failed: %v\noutput: %s", err, out.String())
+	}
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".renamer" {
+			continue
+		}
+		if len(entry.Name()) != len("0123456789.txt") {
+			t.Fatalf("expected 10-character blake3 names, got %s", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+)
+
+var (
+	pngBytes  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegBytes = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'}
+	pdfBytes  = []byte("%PDF-1.7\n")
+)
+
+func writeContentFile(t *testing.T, path string, content []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func runExtension(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"extension"}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestExtensionByContentFixesLyingExtensions(t *testing.T) {
+	tmp := t.TempDir()
+	writeContentFile(t, filepath.Join(tmp, "photo.jpg"), pngBytes)
+	writeContentFile(t, filepath.Join(tmp, "download"), pdfBytes)
+	writeContentFile(t, filepath.Join(tmp, "IMG_0001.JPEG"), jpegBytes)
+	writeContentFile(t, filepath.Join(tmp, "logo.png"), pngBytes)
+	writeContentFile(t, filepath.Join(tmp, "notes.txt"), []byte("plain text"))
+
+	output, err := runExtension(t, "--by-content", "--path", tmp)
+	if err != nil {
+		t.Fatalf("preview failed: %v\n%s", err, output)
+	}
+	for _, want := range []string{
+		"photo.jpg -> photo.png [png, high confidence]",
+		"download -> download.pdf [pdf, high confidence]",
+		"IMG_0001.JPEG -> IMG_0001.JPG [jpeg, high confidence]",
+		"logo.png (no change) [png, high confidence]",
+		"notes.txt (unknown type)",
+		"Summary: 5 candidates, 3 will change, 1 mismatched, 1 missing extension, 1 aliases, 1 unknown type",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in preview:\n%s", want, output)
+		}
+	}
+
+	output, err = runExtension(t, "--by-content", "--only-mismatched", "--path", tmp)
+	if err != nil {
+		t.Fatalf("filtered preview failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "photo.jpg -> photo.png") || strings.Contains(output, "download") || strings.Contains(output, "logo.png") {
+		t.Fatalf("expected only the mismatched file, got:\n%s", output)
+	}
+
+	if output, err = runExtension(t, "--by-content", "--only-mismatched", "--yes", "--path", tmp); err != nil {
+		t.Fatalf("apply failed: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "photo.png")); err != nil {
+		t.Fatalf("expected photo.png after apply: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "download")); err != nil {
+		t.Fatalf("expected download to be left alone: %v", err)
+	}
+
+	undo := renamercmd.NewRootCommand()
+	var undoOut bytes.Buffer
+	undo.SetOut(&undoOut)
+	undo.SetArgs([]string{"undo", "--path", tmp})
+	if err := undo.Execute(); err != nil {
+		t.Fatalf("undo failed: %v\n%s", err, undoOut.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "photo.jpg")); err != nil {
+		t.Fatalf("expected photo.jpg after undo: %v", err)
+	}
+}
+
+func TestExtensionByContentRejectsExtensionArguments(t *testing.T) {
+	tmp := t.TempDir()
+	if _, err := runExtension(t, "--by-content", ".jpg", ".png", "--path", tmp); err == nil {
+		t.Fatalf("expected extension arguments to be rejected with --by-content")
+	}
+	if _, err := runExtension(t, ".jpeg", ".jpg", "--only-mismatched", "--path", tmp); err == nil {
+		t.Fatalf("expected --only-mismatched without --by-content to fail")
+	}
+}
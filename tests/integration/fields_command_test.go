@@ -0,0 +1,39 @@
+package integration
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFieldsReordersNamesAndUndoes(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"Doe, Jane - Report.pdf", "Smith, John - Notes.txt", "Loose Title.pdf"} {
+		createFile(t, filepath.Join(tmp, name))
+	}
+
+	output, err := runRenamer(t, "fields", "--path", tmp, "--split", ", | - ", "--order", "3,2,1", "--join", " - | ", "--yes")
+	if err != nil {
+		t.Fatalf("fields apply failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "Loose Title.pdf (field mismatch: 1 field, expected 3)", "1 field mismatch\n")
+	assertDirNames(t, tmp, "Loose Title.pdf", "Notes - John Smith.txt", "Report - Jane Doe.pdf")
+
+	if output, err := runRenamer(t, "undo", "--path", tmp); err != nil {
+		t.Fatalf("undo failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "Doe, Jane - Report.pdf", "Loose Title.pdf", "Smith, John - Notes.txt")
+}
+
+func TestFieldsDroppingDistinctFieldsReportsConflict(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"band - 01 - intro.mp3", "band - 02 - intro.mp3"} {
+		createFile(t, filepath.Join(tmp, name))
+	}
+
+	output, err := runRenamer(t, "fields", "--path", tmp, "--split", " - ", "--order", "1,3", "--join", "_", "--yes")
+	if err == nil || !strings.Contains(output, "duplicate_target") {
+		t.Fatalf("expected the shared target to block apply, got %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "band - 01 - intro.mp3", "band - 02 - intro.mp3")
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestFlattenApplyRemovesEmptiedDirectoriesAndUndoRestoresThem(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "top.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "a", "b", "c.jpg"))
+	createIntegrationFile(t, filepath.Join(tmp, "a", "d.jpg"))
+	createIntegrationFile(t, filepath.Join(tmp, "keep", "e.jpg"))
+	createIntegrationFile(t, filepath.Join(tmp, "keep", ".hidden"))
+
+	output, err := runRenamer(t, "flatten", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("flatten command failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output,
+		"a/b/c.jpg -> a_b_c.jpg",
+		"top.txt (no change)",
+		"Directories to remove:\n  a/\n  a/b/\n",
+		"Summary: 4 candidates, 3 will move, 1 already at the top level, 0 skipped, 2 directories to remove",
+	)
+	// keep/ still holds a hidden file, so it survives.
+	assertDirNames(t, tmp, "a_b_c.jpg", "a_d.jpg", "keep", "keep_e.jpg", "top.txt")
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertDirNames(t, tmp, "a", "keep", "top.txt")
+	assertDirNames(t, filepath.Join(tmp, "a"), "b", "d.jpg")
+	assertDirNames(t, filepath.Join(tmp, "a", "b"), "c.jpg")
+}
+
+func TestFlattenCollisionPolicies(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		policy  string
+		fails   bool
+		snippet string
+		names   []string
+	}{
+		{policy: "fail", fails: true, snippet: "x/y.txt -> x_y.txt (skipped: collision with x_y.txt)", names: []string{"x", "x_y.txt"}},
+		{policy: "suffix", snippet: "x/y.txt -> x_y_1.txt (suffixed)", names: []string{"x_y.txt", "x_y_1.txt"}},
+		{policy: "skip", snippet: "x/y.txt (skipped: collision with x_y.txt)", names: []string{"x", "x_y.txt"}},
+	} {
+		tmp := t.TempDir()
+		createIntegrationFile(t, filepath.Join(tmp, "x", "y.txt"))
+		createIntegrationFile(t, filepath.Join(tmp, "x_y.txt"))
+
+		output, err := runRenamer(t, "flatten", "--on-collision", tc.policy, "--yes", "--path", tmp)
+		if (err != nil) != tc.fails {
+			t.Fatalf("%s: unexpected error state %v\n%s", tc.policy, err, output)
+		}
+		assertOutputContains(t, output, tc.snippet)
+		assertDirNames(t, tmp, tc.names...)
+	}
+}
+
+func TestBucketApplyAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	for i := 1; i <= 5; i++ {
+		createIntegrationFile(t, filepath.Join(tmp, fmt.Sprintf("img%d.jpg", i)))
+	}
+
+	output, err := runRenamer(t, "bucket", "--size", "2", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("bucket command failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output,
+		"img1.jpg -> 1-2/img1.jpg",
+		"img5.jpg -> 5-6/img5.jpg",
+		"Summary: 5 candidates, 5 will move, 0 skipped, 3 directories to create",
+	)
+	assertDirNames(t, tmp, "1-2", "3-4", "5-6")
+	assertDirNames(t, filepath.Join(tmp, "3-4"), "img3.jpg", "img4.jpg")
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertDirNames(t, tmp, "img1.jpg", "img2.jpg", "img3.jpg", "img4.jpg", "img5.jpg")
+}
+
+func TestBucketPadsToWidestBoundAndRejectsFileNamedLikeBucket(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	for i := 0; i < 3; i++ {
+		createIntegrationFile(t, filepath.Join(tmp, fmt.Sprintf("f%d.txt", i)))
+	}
+
+	output, err := runRenamer(t, "bucket", "--size", "100", "--start", "0", "--dry-run", "--path", tmp)
+	if err != nil {
+		t.Fatalf("bucket command failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "f0.txt -> 00-99/f0.txt")
+
+	if err := os.WriteFile(filepath.Join(tmp, "00-99"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	output, err = runRenamer(t, "bucket", "--size", "100", "--start", "0", "--yes", "--path", tmp)
+	if err == nil {
+		t.Fatalf("expected conflict error\n%s", output)
+	}
+	assertOutputContains(t, output, "parent_not_directory (00-99)")
+}
@@ -1,8 +1,37 @@
 package integration
 
-import "os"
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+)
 
 func fileExistsTestHelper(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
+
+// runRenamer executes the root command with args and returns its combined output.
+func runRenamer(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// assertOutputContains fails unless output holds every snippet.
+func assertOutputContains(t *testing.T, output string, snippets ...string) {
+	t.Helper()
+	for _, snippet := range snippets {
+		if !strings.Contains(output, snippet) {
+			t.Fatalf("expected %q in output:\n%s", snippet, output)
+		}
+	}
+}
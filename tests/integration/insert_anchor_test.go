@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+)
+
+func runInsert(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"insert"}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestInsertAnchorsLiteralAndRegex(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createInsertFile(t, filepath.Join(tmp, "song_v1_v2.mp3"))
+	createInsertFile(t, filepath.Join(tmp, "plain.mp3"))
+
+	output, err := runInsert(t, "--before", "_v", "_final", "--nth", "-1", "--dry-run", "--path", tmp)
+	if err != nil {
+		t.Fatalf("preview failed: %v\n%s", err, output)
+	}
+	if !contains(t, output,
+		"song_v1_v2.mp3 -> song_v1_final_v2.mp3",
+		"plain.mp3 (skipped: anchor not found: before:_v#-1)",
+		"1 missing anchor",
+	) {
+		t.Fatalf("unexpected preview:\n%s", output)
+	}
+
+	output, err = runInsert(t, "--after-regex", `v\d`, "+x", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("apply failed: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "song_v1+x_v2.mp3")); err != nil {
+		t.Fatalf("expected first regex match to anchor the insertion: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "plain.mp3")); err != nil {
+		t.Fatalf("candidate without anchor should be untouched: %v", err)
+	}
+}
+
+func TestInsertMultiplePointsWithTokens(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	album := filepath.Join(tmp, "album")
+	if err := os.Mkdir(album, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	createInsertFile(t, filepath.Join(album, "abcd.txt"))
+	createInsertFile(t, filepath.Join(album, "wxyz.txt"))
+
+	output, err := runInsert(t, "^", "{parent}_", "--at", "2=-", "--at", "$=_{n:02}{{x}}", "--tokens", "--yes", "--path", album)
+	if err != nil {
+		t.Fatalf("apply failed: %v\n%s", err, output)
+	}
+
+	// --at 2 resolves against the original stem, unaffected by the prefix.
+	for _, name := range []string{"album_ab-cd_01{x}.txt", "album_wx-yz_02{x}.txt"} {
+		if _, err := os.Stat(filepath.Join(album, name)); err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+	}
+}
+
+func TestInsertKeepsBracesLiteralWithoutTokens(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createInsertFile(t, filepath.Join(tmp, "notes.txt"))
+
+	output, err := runInsert(t, "^", "{draft} ", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("apply failed: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "{draft} notes.txt")); err != nil {
+		t.Fatalf("expected literal braces in the name: %v\n%s", err, output)
+	}
+}
+
+func TestInsertAnchorFlagValidation(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createInsertFile(t, filepath.Join(tmp, "demo.txt"))
+
+	if output, err := runInsert(t, "--before", "a", "--after", "b", "x", "--path", tmp); err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Fatalf("expected exclusive anchor error, got %v\n%s", err, output)
+	}
+	if output, err := runInsert(t, "--before-regex", "(", "x", "--path", tmp); err == nil {
+		t.Fatalf("expected invalid regex error\n%s", output)
+	}
+	if output, err := runInsert(t, "^", "x", "--nth", "2", "--path", tmp); err == nil {
+		t.Fatalf("expected --nth without anchor to fail\n%s", output)
+	}
+	if output, err := runInsert(t, "^", "{bogus}", "--tokens", "--path", tmp); err == nil {
+		t.Fatalf("expected unknown token error\n%s", output)
+	}
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+)
+
+func copyMediaFixtures(t *testing.T) string {
+	t.Helper()
+	return copyFixtures(t, "media")
+}
+
+// copyFixtures copies testdata/<name> (minus its README) into a fresh temporary directory.
+func copyFixtures(t *testing.T, name string) string {
+	t.Helper()
+	src := filepath.Join("..", "..", "testdata", name)
+	dst := t.TempDir()
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatalf("read fixtures: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == "README.md" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatalf("read fixture %s: %v", entry.Name(), err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0o644); err != nil {
+			t.Fatalf("write fixture %s: %v", entry.Name(), err)
+		}
+	}
+	return dst
+}
+
+func TestSequenceSortsByCaptureTime(t *testing.T) {
+	t.Parallel()
+
+	tmp := copyMediaFixtures(t)
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"sequence", "--sort", "exif", "--width", "1", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("sequence command failed: %v\noutput: %s", err, out.String())
+	}
+
+	assertDirNames(t, tmp, "1_scan.tif", "2_clip.mp4", "3_photo.jpg", "4_photo.heic", "5_screenshot.png", "6_notes.txt")
+}
+
+func TestTemplateExifToken(t *testing.T) {
+	t.Parallel()
+
+	tmp := copyMediaFixtures(t)
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{exif:2006-01}_{stem}{ext}", "--extensions", ".mp4|.tif", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("template command failed: %v\noutput: %s", err, out.String())
+	}
+
+	for _, name := range []string{"2020-02_clip.mp4", "2019-12_scan.tif"} {
+		if _, err := os.Stat(filepath.Join(tmp, name)); err != nil {
+			t.Fatalf("expected %s to exist: %v\noutput: %s", name, err, out.String())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestMatchFilterLimitsSequenceScope(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "IMG_0001.jpg"))
+	createIntegrationFile(t, filepath.Join(tmp, "IMG_0002.jpg"))
+	createIntegrationFile(t, filepath.Join(tmp, "notes.jpg"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"sequence", "--match", `^IMG_\d+`, "--yes", "--path", tmp})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("sequence command failed: %v\noutput: %s", err, out.String())
+	}
+
+	for _, name := range []string{"1_IMG_0001.jpg", "2_IMG_0002.jpg", "notes.jpg"} {
+		if _, err := os.Stat(filepath.Join(tmp, name)); err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+	}
+
+	entry, err := history.Undo(tmp)
+	if err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	filter, ok := entry.Metadata["nameFilter"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected nameFilter metadata, got %#v", entry.Metadata)
+	}
+	if filter["match"] != `^IMG_\d+` || filter["target"] != "stem" {
+		t.Fatalf("unexpected nameFilter metadata: %#v", filter)
+	}
+}
+
+func TestNotMatchFilterAgainstRelativePath(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "keep", "a.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "skip", "b.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list", "--recursive", "--not-match", "^skip/", "--match-target", "path", "--format", "plain", "--path", tmp})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list command failed: %v\noutput: %s", err, out.String())
+	}
+
+	listing := out.String()
+	if !strings.Contains(listing, "keep/a.txt") {
+		t.Fatalf("expected keep/a.txt in output: %s", listing)
+	}
+	if strings.Contains(listing, "skip/b.txt") {
+		t.Fatalf("expected skip/b.txt to be filtered out: %s", listing)
+	}
+}
+
+func TestMatchFilterRejectsInvalidExpression(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list", "--match", "(", "--path", tmp})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected invalid --match expression to fail")
+	}
+}
@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestOrganizeByExtensionApplyAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "a.JPG"))
+	createIntegrationFile(t, filepath.Join(tmp, "b.png"))
+	createIntegrationFile(t, filepath.Join(tmp, "notes.txt"))
+	if err := os.Mkdir(filepath.Join(tmp, "jpg"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"organize", "{ext|trim:.|lower}/", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("organize command failed: %v\noutput: %s", err, out.String())
+	}
+
+	output := out.String()
+	for _, snippet := range []string{
+		"a.JPG -> jpg/a.JPG",
+		"Directories to create:\n  png/\n  txt/\n",
+		"Summary: 3 candidates, 3 will move, 0 already in place, 2 directories to create",
+	} {
+		if !strings.Contains(output, snippet) {
+			t.Fatalf("expected %q in output:\n%s", snippet, output)
+		}
+	}
+	assertDirNames(t, tmp, "jpg", "png", "txt")
+	assertDirNames(t, filepath.Join(tmp, "png"), "b.png")
+
+	entry, err := history.Undo(tmp)
+	if err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	if entry.Metadata["rule"] != "{ext|trim:.|lower}/" {
+		t.Fatalf("expected rule metadata, got %#v", entry.Metadata)
+	}
+	// The pre-existing jpg directory stays; the created ones are removed.
+	assertDirNames(t, tmp, "a.JPG", "b.png", "jpg", "notes.txt")
+}
+
+func TestOrganizeExtensionRuleDoesNotCreateHiddenDirectories(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "photo.JPG"))
+	createIntegrationFile(t, filepath.Join(tmp, "backup.tar.gz"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"organize", "{ext}/", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("organize command failed: %v\noutput: %s", err, out.String())
+	}
+	if strings.Contains(out.String(), "hidden") {
+		t.Fatalf("expected no hidden-directory warning:\n%s", out.String())
+	}
+	assertDirNames(t, tmp, "JPG", "tar.gz")
+	assertDirNames(t, filepath.Join(tmp, "tar.gz"), "backup.tar.gz")
+}
+
+func TestOrganizeUndoKeepsDirectoriesWithNewContent(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "report.pdf"))
+
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"organize", "docs/{ext|trim:.}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("organize command failed: %v", err)
+	}
+	createIntegrationFile(t, filepath.Join(tmp, "docs", "later.txt"))
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertDirNames(t, tmp, "docs", "report.pdf")
+	assertDirNames(t, filepath.Join(tmp, "docs"), "later.txt")
+}
+
+func TestOrganizeReportsCollisions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "one", "same.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "two", "same.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "text"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"organize", "{mime-major}", "--recursive", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected conflicts to block apply")
+	}
+	if !strings.Contains(out.String(), "one/same.txt -> text/same.txt (skipped: parent_not_directory (text))") {
+		t.Fatalf("expected parent_not_directory conflict, got:\n%s", out.String())
+	}
+
+	if err := os.Remove(filepath.Join(tmp, "text")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	out.Reset()
+	cmd = renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"organize", "{mime-major}", "--recursive", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected duplicate targets to block apply")
+	}
+	if !strings.Contains(out.String(), "two/same.txt -> text/same.txt (skipped: duplicate_target with one/same.txt)") {
+		t.Fatalf("expected duplicate_target conflict, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPadAutoWidthPerDirectoryAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	season := filepath.Join(tmp, "season")
+	if err := os.Mkdir(season, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"ep1.mkv", "ep2.mkv", "ep10.mkv", "notes.txt"} {
+		createIntegrationFile(t, filepath.Join(tmp, name))
+	}
+	for _, name := range []string{"s1e2.mkv", "s1e100.mkv"} {
+		createIntegrationFile(t, filepath.Join(season, name))
+	}
+
+	output, err := runRenamer(t, "pad", "--recursive", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("pad failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "1 without digits")
+	assertDirNames(t, tmp, "ep01.mkv", "ep02.mkv", "ep10.mkv", "notes.txt", "season")
+	// Each run position is sized separately: the season stays one digit, episodes take three.
+	assertDirNames(t, season, "s1e002.mkv", "s1e100.mkv")
+
+	if output, err := runRenamer(t, "undo", "--path", tmp); err != nil {
+		t.Fatalf("undo failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "ep1.mkv", "ep10.mkv", "ep2.mkv", "notes.txt", "season")
+	assertDirNames(t, season, "s1e100.mkv", "s1e2.mkv")
+}
+
+func TestPadFixedWidthOccurrenceAndStrip(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "2024_take7.wav"))
+	createIntegrationFile(t, filepath.Join(tmp, "2024_take0012.wav"))
+
+	if output, err := runRenamer(t, "pad", "--width", "3", "--occurrence", "last", "--yes", "--path", tmp); err != nil {
+		t.Fatalf("pad failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "2024_take007.wav", "2024_take012.wav")
+
+	if output, err := runRenamer(t, "pad", "--strip", "--occurrence", "last", "--yes", "--path", tmp); err != nil {
+		t.Fatalf("strip failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "2024_take12.wav", "2024_take7.wav")
+
+	if output, err := runRenamer(t, "pad", "--strip", "--width", "2", "--path", tmp); err == nil {
+		t.Fatalf("expected --strip with --width to fail\n%s", output)
+	}
+}
+
+func TestPadReportsCollisions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "ep01.mkv"))
+	createIntegrationFile(t, filepath.Join(tmp, "ep1.mkv"))
+
+	output, err := runRenamer(t, "pad", "--yes", "--path", tmp)
+	if err == nil || !strings.Contains(output, "ep1.mkv -> ep01.mkv (skipped:") {
+		t.Fatalf("expected collision to block apply, err=%v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "ep01.mkv", "ep1.mkv")
+}
+
+func TestPadAutoWidthIsGroupedByNameShape(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	for _, name := range []string{"s1e03.mkv", "s2e10.mkv", "ep001x.mkv", "ep2x.mkv"} {
+		createIntegrationFile(t, filepath.Join(tmp, name))
+	}
+
+	output, err := runRenamer(t, "pad", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("pad failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "  ep#x: 3\n", "  s#e#: 1, 2\n")
+	// ep001x shares the directory and run position but not the shape, so s1e03 keeps its width.
+	assertDirNames(t, tmp, "ep001x.mkv", "ep002x.mkv", "s1e03.mkv", "s2e10.mkv")
+
+	output, err = runRenamer(t, "pad", "--strip", "--dry-run", "--path", tmp)
+	if err != nil {
+		t.Fatalf("pad --strip failed: %v\n%s", err, output)
+	}
+	assertOutputContains(t, output, "3 will change, 1 no leading zeros, 0 without digits")
+}
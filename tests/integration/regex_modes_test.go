@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func runRegexPreview(t *testing.T, tmp string, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append(append([]string{"regex"}, args...), "--path", tmp))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("regex command failed: %v\noutput: %s", err, out.String())
+	}
+	return out.String()
+}
+
+func TestRegexReplaceModesKeepUnmatchedText(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "a b  c.txt"))
+
+	output := runRegexPreview(t, tmp, `\s+`, "_", "--replace", "all", "--dry-run")
+	for _, snippet := range []string{"a b  c.txt -> a_b_c.txt", "    match: a[ ]b[  ]c.txt"} {
+		if !strings.Contains(output, snippet) {
+			t.Fatalf("expected %q in output:\n%s", snippet, output)
+		}
+	}
+
+	output = runRegexPreview(t, tmp, `\s+`, "_", "--replace", "first", "--dry-run")
+	if !strings.Contains(output, "a b  c.txt -> a_b  c.txt") {
+		t.Fatalf("expected first-match substitution in output:\n%s", output)
+	}
+}
+
+func TestRegexIgnoreCaseAndBasenameTarget(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "Draft.TXT"))
+
+	output := runRegexPreview(t, tmp, `^draft\.txt$`, "final.txt", "--target", "basename", "--ignore-case", "--dry-run")
+	for _, snippet := range []string{"Draft.TXT -> final.txt", "    match: [Draft.TXT]"} {
+		if !strings.Contains(output, snippet) {
+			t.Fatalf("expected %q in output:\n%s", snippet, output)
+		}
+	}
+}
+
+func TestRegexPathTargetMovesEntriesAndUndoRemovesDirectories(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "2024", "trip.jpg"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"regex", `^(\d{4})/(.*)$`, "archive/@1/@2", "--target", "path", "--recursive", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("regex command failed: %v\noutput: %s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "2024/trip.jpg -> archive/2024/trip.jpg") {
+		t.Fatalf("expected path rewrite in output:\n%s", out.String())
+	}
+	assertDirNames(t, filepath.Join(tmp, "archive", "2024"), "trip.jpg")
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertDirNames(t, tmp, "2024")
+	assertDirNames(t, filepath.Join(tmp, "2024"), "trip.jpg")
+}
+
+func TestRegexPathTargetRejectsEscapingPaths(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "a.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"regex", `^(.*)$`, "../@1", "--target", "path", "--dry-run", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected conflict error\noutput: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "invalid_template") {
+		t.Fatalf("expected invalid_template conflict in output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegexTemplateModifiersAndNamedGroups(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		files    []string
+		pattern  string
+		template string
+		expected []string
+	}{
+		{
+			name:     "case and padding",
+			files:    []string{"img7.jpg", "img42.jpg"},
+			pattern:  `^([a-z]+)(\d+)$`,
+			template: "@{1:upper}_@{2:03}",
+			expected: []string{"img7.jpg -> IMG_007.jpg", "img42.jpg -> IMG_042.jpg"},
+		},
+		{
+			name:     "named groups",
+			files:    []string{"report-2024-03.txt"},
+			pattern:  `^(?P<title>[a-z]+)-(?P<date>\d{4}-\d{2})$`,
+			template: "@{date}_@{title:title}",
+			expected: []string{"report-2024-03.txt -> 2024-03_Report.txt"},
+		},
+		{
+			name:     "defaults for unmatched groups",
+			files:    []string{"song.mp3", "song-live.mp3"},
+			pattern:  `^(\w+)(?:-(\w+))?$`,
+			template: "@1 (@{2:-studio})",
+			expected: []string{"song.mp3 -> song (studio).mp3", "song-live.mp3 -> song (live).mp3"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmp := t.TempDir()
+			for _, name := range tc.files {
+				createIntegrationFile(t, filepath.Join(tmp, name))
+			}
+
+			output, err := runRenamer(t, "regex", tc.pattern, tc.template, "--dry-run", "--path", tmp)
+			if err != nil {
+				t.Fatalf("regex command failed: %v\noutput: %s", err, output)
+			}
+			assertOutputContains(t, output, tc.expected...)
+		})
+	}
+}
+
+func TestRegexTemplateRejectsUnknownGroupsAndModifiers(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		template string
+		message  string
+	}{
+		{"@{date}", `no group named "date"`},
+		{"@{1:shout}", `unknown modifier "shout"`},
+		{"@{1", "unclosed placeholder"},
+	} {
+		tmp := t.TempDir()
+		createIntegrationFile(t, filepath.Join(tmp, "a1.txt"))
+
+		_, err := runRenamer(t, "regex", `^(a)(\d)$`, tc.template, "--dry-run", "--path", tmp)
+		if err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Fatalf("template %q: expected error containing %q, got %v", tc.template, tc.message, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestRemoveModesApplyAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "Movie [1080p] (1).mkv"))
+	createIntegrationFile(t, filepath.Join(tmp, "ab.txt"))
+
+	output, err := runRenamer(t, "remove", "--bracketed", "--trim-separators", "--range", "^:1", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("remove command failed: %v\noutput: %s", err, output)
+	}
+	assertOutputContains(t, output,
+		"Movie [1080p] (1).mkv -> ovie.mkv",
+		"ab.txt -> b.txt",
+		"  bracketed ()[]{} -> 2 occurrences",
+	)
+	assertDirNames(t, tmp, "b.txt", "ovie.mkv")
+
+	entry, err := history.Undo(tmp)
+	if err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	if _, ok := entry.Metadata["modes"]; !ok {
+		t.Fatalf("expected modes in ledger metadata, got %#v", entry.Metadata)
+	}
+	assertDirNames(t, tmp, "Movie [1080p] (1).mkv", "ab.txt")
+}
+
+func TestRemoveSkipsStemsTooShortForRange(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "abcdef.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "ab.txt"))
+
+	output, err := runRenamer(t, "remove", "--last", "3", "--dry-run", "--path", tmp)
+	if err != nil {
+		t.Fatalf("remove command failed: %v\noutput: %s", err, output)
+	}
+	assertOutputContains(t, output,
+		"abcdef.txt -> abc.txt",
+		"Warning: ab.txt: range last 3 out of bounds for 2-character stem; skipping",
+	)
+}
+
+func TestRemoveReportsEmptyStemsAsConflicts(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "ab .txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "cd.txt"))
+
+	output, err := runRenamer(t, "remove", "--class", "letters", "--yes", "--path", tmp)
+	if err == nil {
+		t.Fatalf("expected empty stems to block apply\noutput: %s", output)
+	}
+	assertOutputContains(t, output,
+		"CONFLICT: ab .txt ->  .txt (stem would be empty)",
+		"CONFLICT: cd.txt -> .txt (stem would be empty)",
+	)
+	assertDirNames(t, tmp, "ab .txt", "cd.txt")
+}
+
+func TestRemoveModesKeepCompoundExtensions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--last", "5"}, want: "backup.tar.gz"},
+		{args: []string{"--class", "punct"}, want: "backupcopy.tar.gz"},
+	} {
+		tmp := t.TempDir()
+		createIntegrationFile(t, filepath.Join(tmp, "backup_copy.tar.gz"))
+		args := append([]string{"remove", "--yes", "--path", tmp}, tc.args...)
+		if output, err := runRenamer(t, args...); err != nil {
+			t.Fatalf("remove %v failed: %v\n%s", tc.args, err, output)
+		}
+		assertDirNames(t, tmp, tc.want)
+	}
+}
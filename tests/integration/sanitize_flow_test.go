@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestSanitizeResolvesCollisionsDeterministically(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "a:b.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "a?b.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "a_b.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "NUL.log"))
+
+	var preview bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&preview)
+	cmd.SetErr(&preview)
+	cmd.SetArgs([]string{"sanitize", "--profile", "windows", "--dry-run", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("sanitize preview failed: %v\noutput: %s", err, preview.String())
+	}
+
+	for _, line := range []string{
+		"a:b.txt -> a_b_1.txt (illegal_characters, deduplicated)",
+		"a?b.txt -> a_b_2.txt (illegal_characters, deduplicated)",
+		"NUL.log -> NUL_.log (reserved_name)",
+		"a_b.txt (no change)",
+	} {
+		if !strings.Contains(preview.String(), line) {
+			t.Fatalf("expected preview line %q, got:\n%s", line, preview.String())
+		}
+	}
+
+	var out bytes.Buffer
+	cmd = renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"sanitize", "--profile", "windows", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("sanitize apply failed: %v\noutput: %s", err, out.String())
+	}
+	assertDirNames(t, tmp, "NUL_.log", "a_b.txt", "a_b_1.txt", "a_b_2.txt")
+
+	entry, err := history.Undo(tmp)
+	if err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	if entry.Command != "sanitize" {
+		t.Fatalf("expected sanitize ledger entry, got %q", entry.Command)
+	}
+	assertDirNames(t, tmp, "NUL.log", "a:b.txt", "a?b.txt", "a_b.txt")
+}
+
+func TestSanitizeSuffixesBeforeCompoundExtensions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "a:b.tar.gz"))
+	createIntegrationFile(t, filepath.Join(tmp, "a_b.tar.gz"))
+
+	output, err := runRenamer(t, "sanitize", "--profile", "windows", "--yes", "--path", tmp)
+	if err != nil {
+		t.Fatalf("sanitize apply failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "a_b.tar.gz", "a_b_1.tar.gz")
+}
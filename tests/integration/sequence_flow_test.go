@@ -40,10 +40,10 @@ func TestSequenceApplyAndUndo(t *testing.T) {
 		t.Fatalf("expected ledger command 'sequence', got %s", entry.Command)
 	}
 
-	if _, err := os.Stat(filepath.Join(tmp, "001_draft.txt")); err != nil {
+	if _, err := os.Stat(filepath.Join(tmp, "1_draft.txt")); err != nil {
 		t.Fatalf("expected renamed file exists: %v", err)
 	}
-	if _, err := os.Stat(filepath.Join(tmp, "002_notes.txt")); err != nil {
+	if _, err := os.Stat(filepath.Join(tmp, "2_notes.txt")); err != nil {
 		t.Fatalf("expected renamed file exists: %v", err)
 	}
 
@@ -32,10 +32,10 @@ func TestSequenceApplyWithNumberPrefix(t *testing.T) {
 		t.Fatalf("apply error: %v", err)
 	}
 
-	if _, err := os.Stat(filepath.Join(tmp, "seq001-cover.png")); err != nil {
+	if _, err := os.Stat(filepath.Join(tmp, "seq1-cover.png")); err != nil {
 		t.Fatalf("expected renamed file exists: %v", err)
 	}
-	if _, err := os.Stat(filepath.Join(tmp, "seq002-index.png")); err != nil {
+	if _, err := os.Stat(filepath.Join(tmp, "seq2-index.png")); err != nil {
 		t.Fatalf("expected renamed file exists: %v", err)
 	}
 
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/history"
+	"github.com/rogeecn/renamer/internal/sequence"
+)
+
+func sequenceProposals(t *testing.T, opts sequence.Options) []string {
+	t.Helper()
+	plan, err := sequence.Preview(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("preview error: %v", err)
+	}
+	proposed := make([]string, 0, len(plan.Candidates))
+	for _, candidate := range plan.Candidates {
+		proposed = append(proposed, candidate.ProposedPath)
+	}
+	return proposed
+}
+
+func assertProposals(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("candidate %d: expected %s, got %s (all: %v)", i, want[i], got[i], got)
+		}
+	}
+}
+
+func TestSequenceNaturalSortReverseAndStep(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"file10.txt", "file2.txt", "file1.txt"} {
+		createIntegrationFile(t, filepath.Join(tmp, name))
+	}
+
+	opts := sequence.DefaultOptions()
+	opts.WorkingDir = tmp
+	opts.Sort = sequence.SortNatural
+	assertProposals(t, sequenceProposals(t, opts), []string{"1_file1.txt", "2_file2.txt", "3_file10.txt"})
+
+	opts.Reverse = true
+	opts.Start = 10
+	opts.Step = 10
+	assertProposals(t, sequenceProposals(t, opts), []string{"10_file10.txt", "20_file2.txt", "30_file1.txt"})
+}
+
+func TestSequencePerDirectoryRestartsCounter(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"a/x.txt", "a/y.txt", "b/z.txt"} {
+		path := filepath.Join(tmp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		createIntegrationFile(t, path)
+	}
+
+	opts := sequence.DefaultOptions()
+	opts.WorkingDir = tmp
+	opts.Recursive = true
+	opts.PerDirectory = true
+	assertProposals(t, sequenceProposals(t, opts), []string{"a/1_x.txt", "a/2_y.txt", "b/1_z.txt"})
+}
+
+func TestSequenceSortByMtimeAndSeededRandom(t *testing.T) {
+	tmp := t.TempDir()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"c.txt", "a.txt", "b.txt", "d.txt"} {
+		path := filepath.Join(tmp, name)
+		createIntegrationFile(t, path)
+		ts := base.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, ts, ts); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	opts := sequence.DefaultOptions()
+	opts.WorkingDir = tmp
+	opts.Sort = sequence.SortMtime
+	assertProposals(t, sequenceProposals(t, opts), []string{"1_c.txt", "2_a.txt", "3_b.txt", "4_d.txt"})
+
+	opts.Sort = sequence.SortRandom
+	opts.SortSeed = 7
+	first := sequenceProposals(t, opts)
+	assertProposals(t, sequenceProposals(t, opts), first)
+
+	if _, _, err := sequence.ParseSort("random"); err == nil {
+		t.Fatalf("expected random without a seed to be rejected")
+	}
+}
+
+func TestSequenceRenumberClosesGapsAndSwapsSafely(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"photo_002.jpg", "photo_005.jpg", "photo_010.jpg", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(tmp, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	opts := sequence.DefaultOptions()
+	opts.WorkingDir = tmp
+	opts.Renumber = true
+	opts.Sort = sequence.SortNumber
+
+	plan, err := sequence.Preview(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("preview error: %v", err)
+	}
+	if len(plan.Summary.Warnings) != 1 {
+		t.Fatalf("expected a warning for notes.txt, got %v", plan.Summary.Warnings)
+	}
+	if _, err := sequence.Apply(context.Background(), opts, plan); err != nil {
+		t.Fatalf("apply error: %v", err)
+	}
+	assertContent := func(name, want string) {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(tmp, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(data) != want {
+			t.Fatalf("%s holds %q, want %q", name, data, want)
+		}
+	}
+	assertContent("photo_001.jpg", "photo_002.jpg")
+	assertContent("photo_002.jpg", "photo_005.jpg")
+	assertContent("photo_003.jpg", "photo_010.jpg")
+
+	// Reversing swaps 001 and 003 in place, which needs a temporary hop.
+	opts.Reverse = true
+	plan, err = sequence.Preview(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("reverse preview error: %v", err)
+	}
+	if plan.Summary.SkippedCount != 0 {
+		t.Fatalf("expected overlapping targets to be accepted, got %+v", plan.SkippedConflicts)
+	}
+	if _, err := sequence.Apply(context.Background(), opts, plan); err != nil {
+		t.Fatalf("reverse apply error: %v", err)
+	}
+	assertContent("photo_001.jpg", "photo_010.jpg")
+	assertContent("photo_003.jpg", "photo_002.jpg")
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertContent("photo_001.jpg", "photo_002.jpg")
+	assertContent("photo_003.jpg", "photo_010.jpg")
+}
@@ -25,7 +25,7 @@ func TestSequenceApplyWithStartOffset(t *testing.T) {
 		t.Fatalf("preview error: %v", err)
 	}
 
-	expected := []string{"010_shotA.exr", "011_shotB.exr"}
+	expected := []string{"10_shotA.exr", "11_shotB.exr"}
 	if len(plan.Candidates) != len(expected) {
 		t.Fatalf("expected %d candidates, got %d", len(expected), len(plan.Candidates))
 	}
@@ -44,10 +44,10 @@ func TestSequenceApplyWithStartOffset(t *testing.T) {
 		t.Fatalf("expected 2 operations, got %d", len(entry.Operations))
 	}
 
-	if _, err := os.Stat(filepath.Join(tmp, "010_shotA.exr")); err != nil {
+	if _, err := os.Stat(filepath.Join(tmp, "10_shotA.exr")); err != nil {
 		t.Fatalf("expected renamed file exists: %v", err)
 	}
-	if _, err := os.Stat(filepath.Join(tmp, "011_shotB.exr")); err != nil {
+	if _, err := os.Stat(filepath.Join(tmp, "11_shotB.exr")); err != nil {
 		t.Fatalf("expected renamed file exists: %v", err)
 	}
 
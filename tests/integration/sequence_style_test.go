@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/sequence"
+)
+
+func TestSequenceStyles(t *testing.T) {
+	tmp := t.TempDir()
+	for i := 1; i <= 12; i++ {
+		createIntegrationFile(t, filepath.Join(tmp, fmt.Sprintf("f%02d.txt", i)))
+	}
+
+	cases := []struct {
+		style sequence.Style
+		start int
+		first string
+		last  string
+	}{
+		{sequence.StyleDecimal, 1, "01_f01.txt", "12_f12.txt"},
+		{sequence.StyleAlpha, 20, "t_f01.txt", "ae_f12.txt"},
+		{sequence.StyleAlphaUpper, 1, "A_f01.txt", "L_f12.txt"},
+		{sequence.StyleRoman, 1, "I_f01.txt", "XII_f12.txt"},
+		{sequence.StyleHex, 250, "0fa_f01.txt", "105_f12.txt"},
+		{sequence.StyleOf, 1, "01of12_f01.txt", "12of12_f12.txt"},
+	}
+	for _, tc := range cases {
+		opts := sequence.DefaultOptions()
+		opts.WorkingDir = tmp
+		opts.Style = tc.style
+		opts.Start = tc.start
+		proposed := sequenceProposals(t, opts)
+		if proposed[0] != tc.first || proposed[len(proposed)-1] != tc.last {
+			t.Fatalf("style %s: got %s .. %s, want %s .. %s", tc.style, proposed[0], proposed[len(proposed)-1], tc.first, tc.last)
+		}
+	}
+
+	if _, err := sequence.ParseStyle("octal"); err == nil {
+		t.Fatalf("expected unsupported style error")
+	}
+}
+
+func TestSequenceAutoWidthAndOrderWarning(t *testing.T) {
+	tmp := t.TempDir()
+	for i := 1; i <= 12; i++ {
+		createIntegrationFile(t, filepath.Join(tmp, fmt.Sprintf("f%02d.txt", i)))
+	}
+
+	opts := sequence.DefaultOptions()
+	opts.WorkingDir = tmp
+	opts.Start = 995
+	proposed := sequenceProposals(t, opts)
+	if proposed[0] != "0995_f01.txt" {
+		t.Fatalf("expected width sized for the largest number, got %s", proposed[0])
+	}
+
+	opts.Start = 1
+	opts.Width = 1
+	opts.WidthSet = true
+	plan, err := sequence.Preview(t.Context(), opts, nil)
+	if err != nil {
+		t.Fatalf("preview error: %v", err)
+	}
+	warned := false
+	for _, warning := range plan.Summary.Warnings {
+		if strings.Contains(warning, "10 sorts before 9") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Fatalf("expected sort order warning, got %v", plan.Summary.Warnings)
+	}
+}
+
+func TestSequenceUnpaddedStylesReportNoWidth(t *testing.T) {
+	tmp := t.TempDir()
+	for i := 1; i <= 12; i++ {
+		createIntegrationFile(t, filepath.Join(tmp, fmt.Sprintf("f%02d.txt", i)))
+	}
+
+	for _, style := range []string{"roman", "alpha"} {
+		output, err := runRenamer(t, "sequence", "--style", style, "--path", tmp)
+		if err != nil {
+			t.Fatalf("%s preview failed: %v\n%s", style, err, output)
+		}
+		if !strings.Contains(output, "Preview: 12 candidates, 12 renames, 0 skipped.\n") {
+			t.Fatalf("expected no width for %s labels:\n%s", style, output)
+		}
+	}
+
+	output, err := runRenamer(t, "sequence", "--path", tmp)
+	if err != nil {
+		t.Fatalf("decimal preview failed: %v\n%s", err, output)
+	}
+	// Twelve files need two digits.
+	if !strings.Contains(output, "(width 2).") {
+		t.Fatalf("expected the width of the largest number:\n%s", output)
+	}
+}
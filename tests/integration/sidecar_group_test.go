@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGroupSidecarsNumbersClusterOnceAndUndoes(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"IMG_0001.CR2", "IMG_0001.JPG", "IMG_0001.xmp", "IMG_0001.CR2.dop", "IMG_0002.CR2", "movie.mkv", "movie.en.srt"} {
+		createFile(t, filepath.Join(tmp, name))
+	}
+
+	output, err := runRenamer(t, "sequence", "--path", tmp, "--group-sidecars", "--yes")
+	if err != nil {
+		t.Fatalf("sequence apply failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "IMG_0001.JPG -> 1_IMG_0001.JPG (follows IMG_0001.CR2)") {
+		t.Fatalf("expected the JPEG to follow its raw file:\n%s", output)
+	}
+	assertDirNames(t, tmp,
+		"1_IMG_0001.CR2", "1_IMG_0001.CR2.dop", "1_IMG_0001.JPG", "1_IMG_0001.xmp",
+		"2_IMG_0002.CR2", "3_movie.en.srt", "3_movie.mkv")
+
+	if output, err := runRenamer(t, "undo", "--path", tmp); err != nil {
+		t.Fatalf("undo failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, "IMG_0001.CR2", "IMG_0001.CR2.dop", "IMG_0001.JPG", "IMG_0001.xmp", "IMG_0002.CR2", "movie.en.srt", "movie.mkv")
+}
+
+func TestGroupSidecarsConflictBlocksWholeCluster(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"IMG_0001.CR2", "IMG_0001.xmp", "X_IMG_0001.xmp"} {
+		createFile(t, filepath.Join(tmp, name))
+	}
+
+	output, err := runRenamer(t, "insert", "^", "X_", "--path", tmp, "-e", ".CR2", "--group-sidecars", "--yes")
+	if err == nil {
+		t.Fatalf("expected the companion conflict to block apply:\n%s", output)
+	}
+	if !strings.Contains(output, "IMG_0001.xmp -> X_IMG_0001.xmp (skipped: existing_file; blocks IMG_0001.CR2)") {
+		t.Fatalf("expected the blocking companion in the preview:\n%s", output)
+	}
+	assertDirNames(t, tmp, "IMG_0001.CR2", "IMG_0001.xmp", "X_IMG_0001.xmp")
+}
+
+func TestSidecarExtensionsRequireGrouping(t *testing.T) {
+	tmp := t.TempDir()
+	output, err := runRenamer(t, "list", "--path", tmp, "--sidecar-ext", ".xmp")
+	if err == nil || !strings.Contains(err.Error(), "--sidecar-ext requires --group-sidecars") {
+		t.Fatalf("expected --sidecar-ext without --group-sidecars to fail, got %v\n%s", err, output)
+	}
+}
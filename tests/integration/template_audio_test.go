@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+const audioTemplate = "{artist} - {album}/{track:02} {title}{ext}"
+
+func TestTemplateAudioTagsIntoSubdirectoriesAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmp := copyFixtures(t, "audio")
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", audioTemplate, "--missing", "skip", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("template command failed: %v\noutput: %s", err, out.String())
+	}
+
+	output := out.String()
+	for _, snippet := range []string{
+		"queen.mp3 -> Queen - Innuendo/01 Innuendo.mp3",
+		`{artist} = "Queen" (ID3v2.3 TPE1)`,
+		`{track:02} = "01" (MP4 trkn)`,
+		"untagged.mp3 (skipped: missing_tag: artist)",
+	} {
+		if !strings.Contains(output, snippet) {
+			t.Fatalf("expected %q in output:\n%s", snippet, output)
+		}
+	}
+
+	for _, rel := range []string{
+		"Queen - Innuendo/01 Innuendo.mp3",
+		"Björk - Homogenic/03 Jóga.mp3",
+		"Miles Davis - Kind of Blue/01 So What.flac",
+		"Nina Simone - Pastel Blues/07 Sinnerman.ogg",
+		"Daft Punk - Discovery/01 One More Time.m4a",
+		"untagged.mp3",
+	} {
+		if _, err := os.Stat(filepath.Join(tmp, filepath.FromSlash(rel))); err != nil {
+			t.Fatalf("expected %s after apply: %v", rel, err)
+		}
+	}
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertDirNames(t, tmp, "bjork.mp3", "one-more-time.m4a", "queen.mp3", "sinnerman.ogg", "so-what.flac", "untagged.mp3")
+}
+
+func TestTemplateMissingTagPolicies(t *testing.T) {
+	t.Parallel()
+
+	tmp := copyFixtures(t, "audio")
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{artist}{ext}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected missing tags to block apply under the default fail policy")
+	}
+	if !strings.Contains(out.String(), "untagged.mp3 (skipped: missing_tag: artist)") {
+		t.Fatalf("expected missing_tag conflict, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	cmd = renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{year}-{title}{ext}", "--missing", "placeholder", "--placeholder", "NA", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("template command failed: %v\noutput: %s", err, out.String())
+	}
+	if !strings.Contains(out.String(), `{title} = "NA" (placeholder)`) {
+		t.Fatalf("expected placeholder source in preview, got:\n%s", out.String())
+	}
+	assertDirNames(t, tmp, "1959-So What.flac", "1991-Innuendo.mp3", "1997-Jóga.mp3", "2001-One More Time.m4a", "NA-NA.mp3", "NA-Sinnerman.ogg")
+}
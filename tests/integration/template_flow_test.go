@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestTemplateRenameApplyAndUndo(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "Beach Day.JPG"))
+	createIntegrationFile(t, filepath.Join(tmp, "city-night.jpg"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{n:02}_{stem|snake}{ext|lower}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("template command failed: %v\noutput: %s", err, out.String())
+	}
+	assertDirNames(t, tmp, "01_beach_day.jpg", "02_city_night.jpg")
+
+	entry, err := history.Undo(tmp)
+	if err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	if entry.Metadata["template"] != "{n:02}_{stem|snake}{ext|lower}" {
+		t.Fatalf("expected template metadata, got %#v", entry.Metadata)
+	}
+	assertDirNames(t, tmp, "Beach Day.JPG", "city-night.jpg")
+}
+
+func TestTemplateReportsDuplicateTargets(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "a.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "b.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "same{ext}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected duplicate targets to block apply")
+	}
+	if !strings.Contains(out.String(), "b.txt -> same.txt (skipped: duplicate_target with a.txt)") {
+		t.Fatalf("expected duplicate_target conflict in preview, got:\n%s", out.String())
+	}
+	assertDirNames(t, tmp, "a.txt", "b.txt")
+}
+
+func TestTemplateKeepsCompoundExtensions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "backup.tar.gz"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{stem}_x{ext}", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("template command failed: %v\noutput: %s", err, out.String())
+	}
+	assertDirNames(t, tmp, "backup_x.tar.gz")
+}
+
+func TestTemplateReportsUninspectableTargetsAsConflicts(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, strings.Repeat("a", 100)+".txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "short.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"template", "{stem}{stem}{stem}{ext}", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected the oversized target to block the run")
+	}
+	if !strings.Contains(out.String(), "(skipped: target_unreadable (file name too long))") {
+		t.Fatalf("expected a per-candidate conflict, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "short.txt -> shortshortshort.txt") {
+		t.Fatalf("expected other candidates to still be planned, got:\n%s", out.String())
+	}
+}
+
+func TestTemplateReportsEmptyStemsAsConflicts(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "notes.txt"))
+
+	for _, source := range []string{"{stem|slice:5:2}{ext}", "{stem|slice:5:2}  {ext}"} {
+		var out bytes.Buffer
+		cmd := renamercmd.NewRootCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs([]string{"template", source, "--yes", "--path", tmp})
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("expected %q to be blocked", source)
+		}
+		if !strings.Contains(out.String(), "(skipped: empty_name)") {
+			t.Fatalf("expected an empty_name conflict for %q, got:\n%s", source, out.String())
+		}
+	}
+	assertDirNames(t, tmp, "notes.txt")
+}
@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	renamercmd "github.com/rogeecn/renamer/cmd"
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestTruncateHashesCollidingNames(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "meeting-notes-2024-january.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "meeting-notes-2024-february.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "short.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"truncate", "--max-bytes", "20", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("truncate failed: %v\noutput: %s", err, out.String())
+	}
+
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() == ".renamer" {
+			continue
+		}
+		if len(entry.Name()) > 20 {
+			t.Fatalf("name %q exceeds the limit", entry.Name())
+		}
+		names = append(names, entry.Name())
+	}
+	// Both colliding names are hashed, so neither keeps the plain truncated name.
+	joined := strings.Join(names, "|")
+	if strings.Contains(joined, "meeting-notes-20.txt") || strings.Count(joined, "meeting~") != 2 || !strings.Contains(joined, "short.txt") {
+		t.Fatalf("unexpected names after truncate: %v", names)
+	}
+
+	if _, err := history.Undo(tmp); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+	assertDirNames(t, tmp, "meeting-notes-2024-february.txt", "meeting-notes-2024-january.txt", "short.txt")
+}
+
+func TestPreviewRejectsOverlongTargets(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "report.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"insert", "^", strings.Repeat("x", 300), "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected insert to refuse an over-long name\noutput: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "name_too_long (310 bytes > 255)") {
+		t.Fatalf("expected name_too_long reason in preview, got:\n%s", out.String())
+	}
+	assertDirNames(t, tmp, "report.txt")
+}
+
+func TestTruncateReportsLimitTooSmallToDisambiguate(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	createIntegrationFile(t, filepath.Join(tmp, "meeting-notes-2024-january.txt"))
+	createIntegrationFile(t, filepath.Join(tmp, "meeting-notes-2024-february.txt"))
+
+	var out bytes.Buffer
+	cmd := renamercmd.NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"truncate", "--max-bytes", "12", "--yes", "--path", tmp})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected the collision to block apply\noutput: %s", out.String())
+	}
+	if strings.Count(out.String(), "(skipped: limit_too_small_to_disambiguate (needs 14 bytes))") != 2 {
+		t.Fatalf("expected both names to report the limit, got:\n%s", out.String())
+	}
+	assertDirNames(t, tmp, "meeting-notes-2024-february.txt", "meeting-notes-2024-january.txt")
+}
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateReferencesRewritesDocumentsAndUndoes(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "img", "IMG_0001.jpg"))
+	original := "# Trip\n![first](img/IMG_0001.jpg)\n"
+	readme := filepath.Join(tmp, "README.md")
+	if err := os.WriteFile(readme, []byte(original), 0o644); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+
+	output, err := runRenamer(t, "insert", "^", "trip_", "--path", tmp, "-r", "-e", ".jpg", "--update-references", "*.md", "--yes")
+	if err != nil {
+		t.Fatalf("insert apply failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "-![first](img/IMG_0001.jpg)\n+![first](img/trip_IMG_0001.jpg)") {
+		t.Fatalf("expected the rewrite in the preview diff:\n%s", output)
+	}
+	assertDirNames(t, filepath.Join(tmp, "img"), "trip_IMG_0001.jpg")
+	if data, _ := os.ReadFile(readme); string(data) != "# Trip\n![first](img/trip_IMG_0001.jpg)\n" {
+		t.Fatalf("unexpected document after apply:\n%s", data)
+	}
+
+	if output, err := runRenamer(t, "undo", "--path", tmp); err != nil {
+		t.Fatalf("undo failed: %v\n%s", err, output)
+	}
+	assertDirNames(t, filepath.Join(tmp, "img"), "IMG_0001.jpg")
+	if data, _ := os.ReadFile(readme); string(data) != original {
+		t.Fatalf("expected undo to restore the document, got:\n%s", data)
+	}
+}
+
+func TestUndoRefusesDocumentsEditedAfterRewrite(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "song.mp3"))
+	playlist := filepath.Join(tmp, "mix.m3u")
+	if err := os.WriteFile(playlist, []byte("song.mp3\n"), 0o644); err != nil {
+		t.Fatalf("write playlist: %v", err)
+	}
+
+	if output, err := runRenamer(t, "case", "upper", "--path", tmp, "-e", ".mp3", "--update-references", "*.m3u", "--yes"); err != nil {
+		t.Fatalf("case apply failed: %v\n%s", err, output)
+	}
+	if err := os.WriteFile(playlist, []byte("SONG.mp3\nother.mp3\n"), 0o644); err != nil {
+		t.Fatalf("edit playlist: %v", err)
+	}
+
+	output, err := runRenamer(t, "undo", "--path", tmp)
+	if err == nil || !strings.Contains(err.Error(), "was modified after renamer edited it") {
+		t.Fatalf("expected undo to refuse the edited playlist, got %v\n%s", err, output)
+	}
+	assertDirNames(t, tmp, ".renamer-backups", "SONG.mp3", "mix.m3u")
+}
+
+func TestUndoRestoresLargeRewrittenDocument(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "cover.png"))
+	original := strings.Repeat("Filler line that pads the notes past the scanner limit.\n", 2000) + "![cover](cover.png)\n"
+	notes := filepath.Join(tmp, "notes.md")
+	if err := os.WriteFile(notes, []byte(original), 0o644); err != nil {
+		t.Fatalf("write notes: %v", err)
+	}
+
+	if output, err := runRenamer(t, "insert", "^", "new_", "--path", tmp, "-e", ".png", "--update-references", "*.md", "--yes"); err != nil {
+		t.Fatalf("insert apply failed: %v\n%s", err, output)
+	}
+	if data, _ := os.ReadFile(notes); !strings.HasSuffix(string(data), "![cover](new_cover.png)\n") {
+		t.Fatalf("expected the reference to be rewritten")
+	}
+
+	if output, err := runRenamer(t, "undo", "--path", tmp); err != nil {
+		t.Fatalf("undo failed: %v\n%s", err, output)
+	}
+	if data, _ := os.ReadFile(notes); string(data) != original {
+		t.Fatalf("expected undo to restore the %d-byte document, got %d bytes", len(original), len(data))
+	}
+	if _, err := os.Stat(filepath.Join(tmp, ".renamer-backups")); !os.IsNotExist(err) {
+		t.Fatalf("expected undo to remove the backup directory, stat err: %v", err)
+	}
+}
+
+func TestUpdateReferencesWarnsAboutSkippedDocuments(t *testing.T) {
+	tmp := t.TempDir()
+	createFile(t, filepath.Join(tmp, "cover.png"))
+	if err := os.WriteFile(filepath.Join(tmp, "latin1.md"), []byte("caf\xe9 ![cover](cover.png)\n"), 0o644); err != nil {
+		t.Fatalf("write latin1.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "binary.md"), []byte("![cover](cover.png)\x00\n"), 0o644); err != nil {
+		t.Fatalf("write binary.md: %v", err)
+	}
+
+	output, err := runRenamer(t, "insert", "^", "new_", "--path", tmp, "-e", ".png", "--update-references", "*.md", "--dry-run")
+	if err != nil {
+		t.Fatalf("insert preview failed: %v\n%s", err, output)
+	}
+	for _, want := range []string{
+		"Warning: binary.md not scanned for references (contains NUL bytes)",
+		"Warning: latin1.md not scanned for references (not valid UTF-8)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in preview:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package replace_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/casing"
+)
+
+func TestSplitWordsBoundaries(t *testing.T) {
+	cases := map[string][]string{
+		"fooBar baz":      {"foo", "Bar", "baz"},
+		"HTTPServer_log":  {"HTTP", "Server", "log"},
+		"file2name-final": {"file", "2", "name", "final"},
+		"Ça va_Été":       {"Ça", "va", "Été"},
+		"项目A报告":           {"项目A报告"},
+	}
+
+	for input, expected := range cases {
+		if got := casing.SplitWords(input); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("SplitWords(%q) = %#v, expected %#v", input, got, expected)
+		}
+	}
+}
+
+func TestConvertStyles(t *testing.T) {
+	input := "my holidayPhotos_2024"
+	expected := map[casing.Style]string{
+		casing.StyleLower:    "my holidayphotos_2024",
+		casing.StyleUpper:    "MY HOLIDAYPHOTOS_2024",
+		casing.StyleTitle:    "My Holidayphotos_2024",
+		casing.StyleSentence: "My holidayphotos_2024",
+		casing.StyleSnake:    "my_holiday_photos_2024",
+		casing.StyleKebab:    "my-holiday-photos-2024",
+		casing.StyleCamel:    "myHolidayPhotos2024",
+		casing.StylePascal:   "MyHolidayPhotos2024",
+	}
+
+	for style, want := range expected {
+		if got := casing.Convert(input, style); got != want {
+			t.Fatalf("Convert(%q, %s) = %q, expected %q", input, style, got, want)
+		}
+	}
+}
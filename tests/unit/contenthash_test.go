@@ -0,0 +1,76 @@
+package replace_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/contenthash"
+)
+
+func TestContentHashKnownDigests(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	cases := map[contenthash.Algorithm]string{
+		contenthash.SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		contenthash.XXHash: "ef46db3751d8e999",
+		contenthash.BLAKE3: "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262",
+	}
+	for alg, want := range cases {
+		got, err := contenthash.File(empty, alg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", alg, err)
+		}
+		if got != want {
+			t.Fatalf("%s: expected %s, got %s", alg, want, got)
+		}
+		if len(got) != alg.HexLength() {
+			t.Fatalf("%s: expected %d hex characters, got %d", alg, alg.HexLength(), len(got))
+		}
+	}
+}
+
+func TestContentHashFilesParallelWithProgress(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		path := filepath.Join(dir, string(rune('a'+i))+".bin")
+		if err := os.WriteFile(path, []byte{byte(i % 5)}, 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	lastDone := 0
+	var lastBytes int64
+	digests, err := contenthash.Files(context.Background(), paths, contenthash.XXHash, contenthash.Options{
+		Workers: 4,
+		Progress: func(done, total int, bytes int64) {
+			if done <= lastDone || total != len(paths) {
+				t.Errorf("unexpected progress %d/%d after %d", done, total, lastDone)
+			}
+			lastDone, lastBytes = done, bytes
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(digests) != len(paths) || lastDone != len(paths) || lastBytes != int64(len(paths)) {
+		t.Fatalf("expected %d digests and complete progress, got %d digests, progress %d (%d bytes)",
+			len(paths), len(digests), lastDone, lastBytes)
+	}
+	if digests[paths[0]] != digests[paths[5]] || digests[paths[0]] == digests[paths[1]] {
+		t.Fatalf("expected equal content to share digests only")
+	}
+}
+
+func TestContentHashRejectsUnknownAlgorithm(t *testing.T) {
+	if _, err := contenthash.ParseAlgorithm("md5"); err == nil {
+		t.Fatalf("expected md5 to be rejected")
+	}
+}
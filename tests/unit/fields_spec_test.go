@@ -0,0 +1,64 @@
+package replace_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/fields"
+)
+
+func TestFieldsSplitAndJoinRearrangeNames(t *testing.T) {
+	delimiters, err := fields.ParseDelimiters(", | - ")
+	if err != nil {
+		t.Fatalf("parse delimiters: %v", err)
+	}
+	parts := fields.Split("Doe, Jane - Annual Report", delimiters)
+	if strings.Join(parts, "|") != "Doe|Jane|Annual Report" {
+		t.Fatalf("unexpected fields %q", parts)
+	}
+
+	cases := []struct {
+		order string
+		join  string
+		want  string
+	}{
+		{order: "3,2,1", join: " - | ", want: "Annual Report - Jane Doe"},
+		{order: "3:snake,1", join: "_", want: "annual_report_Doe"},
+		{order: "2:upper,2:lower,1", join: "-", want: "JANE-jane-Doe"},
+		{order: "", join: "_", want: "Doe_Jane_Annual Report"},
+	}
+	for _, tc := range cases {
+		var specs []fields.FieldSpec
+		if tc.order != "" {
+			if specs, err = fields.ParseOrder(tc.order); err != nil {
+				t.Fatalf("parse order %q: %v", tc.order, err)
+			}
+		}
+		joiners, err := fields.ParseJoiners(tc.join)
+		if err != nil {
+			t.Fatalf("parse join %q: %v", tc.join, err)
+		}
+		if got := fields.Join(parts, specs, joiners, true); got != tc.want {
+			t.Fatalf("order %q join %q: expected %q, got %q", tc.order, tc.join, tc.want, got)
+		}
+	}
+
+	untrimmed := fields.Split("a -  b", []string{"-"})
+	if got := fields.Join(untrimmed, []fields.FieldSpec{{Index: 2}, {Index: 1, Trim: true}}, []string{"+"}, false); got != "  b+a" {
+		t.Fatalf("expected per-field trim only, got %q", got)
+	}
+}
+
+func TestFieldsParseRejectsInvalidSpecs(t *testing.T) {
+	for _, order := range []string{"0", "a", "1,,2", "2:shout"} {
+		if _, err := fields.ParseOrder(order); err == nil {
+			t.Fatalf("expected order %q to be rejected", order)
+		}
+	}
+	if _, err := fields.ParseDelimiters("-||_"); err == nil {
+		t.Fatalf("expected an empty delimiter to be rejected")
+	}
+	if _, err := fields.ParseJoiners("a/b"); err == nil {
+		t.Fatalf("expected a joiner with a path separator to be rejected")
+	}
+}
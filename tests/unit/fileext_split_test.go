@@ -0,0 +1,54 @@
+package replace_test
+
+import (
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+)
+
+func TestFileextSplitRecognisesCompoundExtensions(t *testing.T) {
+	model, err := fileext.New([]string{".tar.lz", ".SPEC.TS"})
+	if err != nil {
+		t.Fatalf("new model: %v", err)
+	}
+
+	cases := []struct {
+		name, stem, ext string
+	}{
+		{"backup.tar.gz", "backup", ".tar.gz"},
+		{"BACKUP.TAR.ZST", "BACKUP", ".TAR.ZST"},
+		{"index.d.ts", "index", ".d.ts"},
+		{"app.min.js", "app", ".min.js"},
+		{"app.js", "app", ".js"},
+		{"archive.tar.lz", "archive", ".tar.lz"},
+		{"button.spec.ts", "button", ".spec.ts"},
+		{"my.photo.jpg", "my.photo", ".jpg"},
+		{".env", ".env", ""},
+		{".env.local", ".env", ".local"},
+		{".tar.gz", ".tar", ".gz"},
+		{"README", "README", ""},
+	}
+	for _, tc := range cases {
+		stem, ext := model.Split(tc.name)
+		if stem != tc.stem || ext != tc.ext {
+			t.Fatalf("%s: expected (%q, %q), got (%q, %q)", tc.name, tc.stem, tc.ext, stem, ext)
+		}
+	}
+
+	if _, ext := (fileext.Model{}).Split("button.spec.ts"); ext != ".ts" {
+		t.Fatalf("zero model must only know the defaults, got %q", ext)
+	}
+
+	filter := map[string]struct{}{".gz": {}}
+	if !model.Allows("backup.tar.gz", filter) || model.Allows("notes.txt", filter) {
+		t.Fatalf("expected .gz filter to select compound archives only")
+	}
+}
+
+func TestFileextRejectsSingleExtensions(t *testing.T) {
+	for _, bad := range []string{".gz", "tar.gz", ".tar..gz", ".tar.", ".a/b.c"} {
+		if _, err := fileext.New([]string{bad}); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
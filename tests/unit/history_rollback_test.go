@@ -0,0 +1,50 @@
+package replace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/history"
+)
+
+func TestRollbackRevertsEveryOperationKind(t *testing.T) {
+	tmp := t.TempDir()
+	writeReferenceFixture(t, tmp, map[string]string{
+		"a.jpg":      "",
+		"index.html": `<img src="a.jpg">`,
+	})
+
+	done, err := history.EnsureDir(tmp, "2024")
+	if err != nil {
+		t.Fatalf("ensure dir: %v", err)
+	}
+	if err := history.RenamePath(filepath.Join(tmp, "a.jpg"), filepath.Join(tmp, "2024", "a.jpg")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	done = append(done, history.Operation{From: "a.jpg", To: "2024/a.jpg"})
+	edit, err := history.WriteContent(tmp, "index.html", []byte(`<img src="a.jpg">`), []byte(`<img src="2024/a.jpg">`))
+	if err != nil {
+		t.Fatalf("write content: %v", err)
+	}
+	done = append(done, edit)
+
+	if err := history.Rollback(tmp, done); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 2 || names[0] != "a.jpg" || names[1] != "index.html" {
+		t.Fatalf("expected only the original files, got %v", names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmp, "index.html")); string(data) != `<img src="a.jpg">` {
+		t.Fatalf("expected the document to be restored, got %s", data)
+	}
+}
@@ -0,0 +1,107 @@
+package replace_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+func TestAudioTagsFixtures(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "audio")
+	cases := []struct {
+		file   string
+		artist string
+		title  string
+		track  string
+		source string
+	}{
+		{"queen.mp3", "Queen", "Innuendo", "1", "ID3v2.3 TPE1"},
+		{"bjork.mp3", "Björk", "Jóga", "3", "ID3v2.4 TPE1"},
+		{"so-what.flac", "Miles Davis", "So What", "1", "FLAC ARTIST"},
+		{"sinnerman.ogg", "Nina Simone", "Sinnerman", "7", "Vorbis ARTIST"},
+		{"one-more-time.m4a", "Daft Punk", "One More Time", "1", "MP4 ©ART"},
+	}
+
+	for _, tc := range cases {
+		tags, ok, err := metadata.AudioTags(filepath.Join(dir, tc.file))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.file, err)
+		}
+		if !ok {
+			t.Fatalf("%s: expected tags", tc.file)
+		}
+		if got := tags[metadata.TagArtist]; got.Value != tc.artist || got.Source != tc.source {
+			t.Fatalf("%s: expected artist %q from %s, got %#v", tc.file, tc.artist, tc.source, got)
+		}
+		if got := tags[metadata.TagTitle].Value; got != tc.title {
+			t.Fatalf("%s: expected title %q, got %q", tc.file, tc.title, got)
+		}
+		if got := tags[metadata.TagTrack].Value; got != tc.track {
+			t.Fatalf("%s: expected track %q, got %q", tc.file, tc.track, got)
+		}
+	}
+}
+
+func TestAudioTagsNormalizesYear(t *testing.T) {
+	tags, _, err := metadata.AudioTags(filepath.Join("..", "..", "testdata", "audio", "bjork.mp3"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tags[metadata.TagYear]; got.Value != "1997" || got.Source != "ID3v2.4 TDRC" {
+		t.Fatalf("expected year 1997 from TDRC, got %#v", got)
+	}
+}
+
+func TestAudioTagsUntagged(t *testing.T) {
+	_, ok, err := metadata.AudioTags(filepath.Join("..", "..", "testdata", "audio", "untagged.mp3"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected no tags for untagged.mp3")
+	}
+}
+
+func TestAudioTagsStopsAtOverlongVorbisComment(t *testing.T) {
+	// The second comment claims more bytes than the block holds; the third must not be read.
+	path := writeFLAC(t, vorbisComment(-1, "ARTIST=Nina"), vorbisComment(1<<20, ""), vorbisComment(-1, "TITLE=Bogus"))
+
+	tags, _, err := metadata.AudioTags(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tags[metadata.TagArtist].Value; got != "Nina" {
+		t.Fatalf("expected the artist before the bad length, got %q", got)
+	}
+	if got, ok := tags[metadata.TagTitle]; ok {
+		t.Fatalf("expected parsing to stop at the bad length, got title %#v", got)
+	}
+}
+
+// vorbisComment encodes one length-prefixed comment; a negative length uses the text's own.
+func vorbisComment(length int, text string) []byte {
+	if length < 0 {
+		length = len(text)
+	}
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(length)), text...)
+}
+
+// writeFLAC writes a minimal FLAC file whose only metadata block holds the given comments.
+func writeFLAC(t *testing.T, comments ...[]byte) string {
+	t.Helper()
+	block := binary.LittleEndian.AppendUint32(nil, 0) // empty vendor string
+	block = binary.LittleEndian.AppendUint32(block, uint32(len(comments)))
+	for _, comment := range comments {
+		block = append(block, comment...)
+	}
+	data := append([]byte("fLaC"), 0x80|4, byte(len(block)>>16), byte(len(block)>>8), byte(len(block)))
+	data = append(data, block...)
+	path := filepath.Join(t.TempDir(), "tagged.flac")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
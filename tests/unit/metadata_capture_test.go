@@ -0,0 +1,50 @@
+package replace_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+func TestEmbeddedTimeFixtures(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "media")
+	cases := []struct {
+		file     string
+		source   metadata.Source
+		expected time.Time
+	}{
+		{"photo.jpg", metadata.SourceEXIF, time.Date(2021, 5, 6, 7, 8, 9, 0, time.FixedZone("", 2*60*60))},
+		{"scan.tif", metadata.SourceEXIF, time.Date(2019, 12, 31, 23, 59, 58, 0, time.Local)},
+		{"photo.heic", metadata.SourceEXIF, time.Date(2022, 8, 15, 10, 20, 30, 0, time.Local)},
+		{"clip.mp4", metadata.SourceQuickTime, time.Date(2020, 2, 3, 4, 5, 6, 0, time.UTC)},
+		{"screenshot.png", metadata.SourcePNG, time.Date(2023, 3, 4, 5, 6, 7, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		ts, source, ok, err := metadata.EmbeddedTime(filepath.Join(dir, tc.file))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.file, err)
+		}
+		if !ok {
+			t.Fatalf("%s: expected embedded timestamp", tc.file)
+		}
+		if source != tc.source {
+			t.Fatalf("%s: expected source %s, got %s", tc.file, tc.source, source)
+		}
+		if !ts.Equal(tc.expected) {
+			t.Fatalf("%s: expected %s, got %s", tc.file, tc.expected, ts)
+		}
+	}
+}
+
+func TestCaptureTimeFallsBackToModTime(t *testing.T) {
+	ts, source, err := metadata.CaptureTime(filepath.Join("..", "..", "testdata", "media", "notes.txt"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source != metadata.SourceModTime || ts.IsZero() {
+		t.Fatalf("expected mtime fallback, got %s (%s)", ts, source)
+	}
+}
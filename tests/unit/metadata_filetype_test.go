@@ -0,0 +1,73 @@
+package replace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/metadata"
+)
+
+func TestDetectTypeFromMagicBytes(t *testing.T) {
+	dir := t.TempDir()
+	tarHeader := make([]byte, 512)
+	copy(tarHeader[257:], "ustar")
+
+	cases := []struct {
+		name       string
+		content    []byte
+		typeName   string
+		confidence metadata.Confidence
+		canonical  string
+	}{
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "png", metadata.ConfidenceHigh, ".png"},
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'}, "jpeg", metadata.ConfidenceHigh, ".jpg"},
+		{"pdf", []byte("%PDF-1.7\n"), "pdf", metadata.ConfidenceHigh, ".pdf"},
+		{"zip", []byte("PK\x03\x04\x14\x00\x00\x00"), "zip", metadata.ConfidenceMedium, ".zip"},
+		{"epub", []byte("PK\x03\x04\x14\x00\x00\x00\x00\x00mimetypeapplication/epub+zip"), "epub", metadata.ConfidenceHigh, ".epub"},
+		{"mov", []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00"), "quicktime", metadata.ConfidenceHigh, ".mov"},
+		{"mp4", []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00"), "mp4", metadata.ConfidenceMedium, ".mp4"},
+		{"tar", tarHeader, "tar", metadata.ConfidenceHigh, ".tar"},
+		{"frame", []byte{0xFF, 0xFB, 0x90, 0x64}, "mp3", metadata.ConfidenceLow, ".mp3"},
+	}
+
+	for _, tc := range cases {
+		path := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(path, tc.content, 0o644); err != nil {
+			t.Fatalf("write %s: %v", tc.name, err)
+		}
+		detected, ok, err := metadata.DetectType(path)
+		if err != nil || !ok {
+			t.Fatalf("%s: expected detection, got ok=%v err=%v", tc.name, ok, err)
+		}
+		if detected.Name != tc.typeName || detected.Confidence != tc.confidence || detected.Canonical() != tc.canonical {
+			t.Fatalf("%s: got %s/%s/%s", tc.name, detected.Name, detected.Confidence, detected.Canonical())
+		}
+	}
+
+	plain := filepath.Join(dir, "notes")
+	if err := os.WriteFile(plain, []byte("just some text"), 0o644); err != nil {
+		t.Fatalf("write notes: %v", err)
+	}
+	if _, ok, err := metadata.DetectType(plain); err != nil || ok {
+		t.Fatalf("expected plain text to stay undetected, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestFileTypeAcceptsAliasesAndContainers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo")
+	if err := os.WriteFile(path, []byte{0xFF, 0xD8, 0xFF, 0xE1}, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	jpeg, _, err := metadata.DetectType(path)
+	if err != nil {
+		t.Fatalf("detect: %v", err)
+	}
+	if !jpeg.Accepts(".JPG") || !jpeg.Accepts(".jpeg") || !jpeg.IsAlias(".JPEG") || jpeg.IsAlias(".jpg") {
+		t.Fatalf("unexpected jpeg extension handling: %+v", jpeg)
+	}
+	if jpeg.Accepts(".png") {
+		t.Fatalf("jpeg must not accept .png")
+	}
+}
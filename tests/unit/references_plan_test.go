@@ -0,0 +1,105 @@
+package replace_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/references"
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+func writeReferenceFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestReferencesPlanRewritesLinksAcrossSyntaxes(t *testing.T) {
+	tmp := t.TempDir()
+	writeReferenceFixture(t, tmp, map[string]string{
+		"img/IMG 1.jpg": "",
+		"img/IMG_2.jpg": "",
+		"song.mp3":      "",
+		"docs/index.md": "![one](../img/IMG%201.jpg)\n<img src='../img/IMG_2.jpg#top'>\n" +
+			"[ref]: ../img/IMG_2.jpg\n[web](https://example.com/img/IMG_2.jpg)\n[gone](../img/missing.jpg)\n",
+		"list.m3u":   "#EXTM3U\n#EXTINF:1,Song\nsong.mp3\n",
+		"notes.txt":  "[one](img/IMG_2.jpg)\n",
+		"docs/x.bin": "",
+	})
+
+	rewriter := references.New(tmp, []string{"*.md", "*.m3u"}, false)
+	plan, err := rewriter.Plan([]sidecar.Rename{
+		{From: "img/IMG 1.jpg", To: "img/trip 01.jpg"},
+		{From: "img/IMG_2.jpg", To: "img/trip_02.jpg"},
+		{From: "song.mp3", To: "music/song.mp3"},
+	})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan.Edits) != 2 {
+		t.Fatalf("expected the markdown and playlist edits only, got %#v", plan.Edits)
+	}
+
+	want := map[string]string{
+		"docs/index.md": "![one](../img/trip%2001.jpg)\n<img src='../img/trip_02.jpg#top'>\n" +
+			"[ref]: ../img/trip_02.jpg\n[web](https://example.com/img/IMG_2.jpg)\n[gone](../img/missing.jpg)\n",
+		"list.m3u": "#EXTM3U\n#EXTINF:1,Song\nmusic/song.mp3\n",
+	}
+	for _, edit := range plan.Edits {
+		if edit.After != want[edit.Path] {
+			t.Fatalf("%s: unexpected rewrite:\n%s", edit.Path, edit.After)
+		}
+	}
+
+	var out bytes.Buffer
+	plan.WritePreview(&out)
+	if !strings.Contains(out.String(), "@@ -3,1 +3,1 @@\n-song.mp3\n+music/song.mp3\n") {
+		t.Fatalf("expected a diff hunk for the playlist:\n%s", out.String())
+	}
+}
+
+func TestReferencesPlanFollowsRenamedDocumentsAndDirectories(t *testing.T) {
+	tmp := t.TempDir()
+	writeReferenceFixture(t, tmp, map[string]string{
+		"photos/a.jpg":   "",
+		"top.png":        "",
+		"guide/intro.md": "[a](../photos/a.jpg)\n[top](../top.png)\n",
+	})
+
+	rewriter := references.New(tmp, []string{"guide/*.md"}, false)
+	plan, err := rewriter.Plan(
+		[]sidecar.Rename{
+			{From: "photos/a.jpg", To: "photos/A.jpg"},
+			{From: "photos", To: "Photos"},
+			{From: "guide/intro.md", To: "intro.md"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan.Edits) != 1 {
+		t.Fatalf("expected one edit, got %#v", plan.Edits)
+	}
+	edit := plan.Edits[0]
+	if edit.Target != "intro.md" || edit.After != "[a](Photos/A.jpg)\n[top](top.png)\n" {
+		t.Fatalf("unexpected edit %#v", edit)
+	}
+
+	var none *references.Rewriter
+	if empty, err := none.Plan([]sidecar.Rename{{From: "top.png", To: "x.png"}}); err != nil || len(empty.Edits) != 0 {
+		t.Fatalf("nil rewriter must disable reference updates")
+	}
+	if _, err := references.ParsePatterns("[md"); err == nil {
+		t.Fatalf("expected an invalid glob to be rejected")
+	}
+}
@@ -0,0 +1,118 @@
+package replace_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/remove"
+)
+
+func mustRange(t *testing.T, value string) remove.Range {
+	t.Helper()
+	r, err := remove.ParseRange(value)
+	if err != nil {
+		t.Fatalf("ParseRange(%q): %v", value, err)
+	}
+	return r
+}
+
+func TestApplyRemovalsModes(t *testing.T) {
+	cases := []struct {
+		name   string
+		base   string
+		tokens []string
+		modes  remove.Modes
+		want   string
+		counts map[string]int
+	}{
+		{
+			name:   "range between positions",
+			base:   "IMG_20240101.jpg",
+			modes:  remove.Modes{Ranges: []remove.Range{mustRange(t, "3:4")}},
+			want:   "IMG20240101.jpg",
+			counts: map[string]int{"range 3:4": 1},
+		},
+		{
+			name:   "suffix positions",
+			base:   "holiday-001.png",
+			modes:  remove.Modes{Ranges: []remove.Range{mustRange(t, "4$:$")}},
+			want:   "holiday.png",
+			counts: map[string]int{"range 4$:$": 1},
+		},
+		{
+			name:   "last runes keep extension",
+			base:   "track01.mp3",
+			modes:  remove.Modes{Last: 2},
+			want:   "track.mp3",
+			counts: map[string]int{"last 2": 1},
+		},
+		{
+			name:   "bracketed segments with trimming",
+			base:   "Movie [1080p] (1).mkv",
+			modes:  remove.Modes{Brackets: remove.DefaultBrackets, TrimSeparators: true},
+			want:   "Movie.mkv",
+			counts: map[string]int{"bracketed ()[]{}": 2, "trim separators": 1},
+		},
+		{
+			name:   "nested and unbalanced brackets",
+			base:   "a(b[c])d(e.txt",
+			modes:  remove.Modes{Brackets: remove.DefaultBrackets},
+			want:   "ad(e.txt",
+			counts: map[string]int{"bracketed ()[]{}": 1},
+		},
+		{
+			name:   "digit class after tokens",
+			base:   "scan 2024 copy.pdf",
+			tokens: []string{" copy"},
+			modes:  remove.Modes{Classes: []remove.Class{remove.ClassDigits}, TrimSeparators: true},
+			want:   "scan.pdf",
+			counts: map[string]int{" copy": 1, "class digits": 4, "trim separators": 1},
+		},
+		{
+			name:   "last runes keep compound extension",
+			base:   "backup_copy.tar.gz",
+			modes:  remove.Modes{Last: 5},
+			want:   "backup.tar.gz",
+			counts: map[string]int{"last 5": 1},
+		},
+		{
+			name:   "punctuation class keeps compound extension",
+			base:   "backup_copy.tar.gz",
+			modes:  remove.Modes{Classes: []remove.Class{remove.ClassPunct}},
+			want:   "backupcopy.tar.gz",
+			counts: map[string]int{"class punct": 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := remove.ApplyRemovals(remove.Candidate{BaseName: tc.base}, tc.tokens, tc.modes, fileext.Model{})
+			if err != nil {
+				t.Fatalf("ApplyRemovals error: %v", err)
+			}
+			if result.ProposedName != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, result.ProposedName)
+			}
+			if len(result.Matches) != len(tc.counts) {
+				t.Fatalf("expected counts %v, got %v", tc.counts, result.Matches)
+			}
+			for label, count := range tc.counts {
+				if result.Matches[label] != count {
+					t.Fatalf("expected %d for %q, got %v", count, label, result.Matches)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyRemovalsRangeOutOfBounds(t *testing.T) {
+	_, err := remove.ApplyRemovals(remove.Candidate{BaseName: "ab.txt", RelativePath: "ab.txt"}, nil, remove.Modes{Ranges: []remove.Range{mustRange(t, "1:5")}}, fileext.Model{})
+	var bounds remove.ErrRangeOutOfBounds
+	if !errors.As(err, &bounds) {
+		t.Fatalf("expected ErrRangeOutOfBounds, got %v", err)
+	}
+	if _, err := remove.ParseRange("3-8"); err == nil {
+		t.Fatalf("expected malformed range to be rejected")
+	}
+}
@@ -0,0 +1,118 @@
+package replace_test
+
+import (
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/replace"
+)
+
+func TestApplyPatternsOptions(t *testing.T) {
+	cases := []struct {
+		name     string
+		base     string
+		patterns []string
+		repl     string
+		opts     replace.Options
+		want     string
+		counts   map[string]int
+	}{
+		{
+			name:     "case-sensitive default",
+			base:     "Copy of copy.txt",
+			patterns: []string{"copy"},
+			repl:     "x",
+			want:     "Copy of x.txt",
+			counts:   map[string]int{"copy": 1},
+		},
+		{
+			name:     "ignore case",
+			base:     "Copy of COPY.txt",
+			patterns: []string{"copy"},
+			repl:     "x",
+			opts:     replace.Options{IgnoreCase: true},
+			want:     "x of x.txt",
+			counts:   map[string]int{"copy": 2},
+		},
+		{
+			name:     "whole word skips embedded matches",
+			base:     "copyright copy_2 scopy.txt",
+			patterns: []string{"copy"},
+			repl:     "dup",
+			opts:     replace.Options{WholeWord: true},
+			want:     "copyright dup_2 scopy.txt",
+			counts:   map[string]int{"copy": 1},
+		},
+		{
+			name:     "first occurrence",
+			base:     "a-b-c.txt",
+			patterns: []string{"-"},
+			repl:     "_",
+			opts:     replace.Options{Occurrence: replace.OccurrenceFirst},
+			want:     "a_b-c.txt",
+			counts:   map[string]int{"-": 1},
+		},
+		{
+			name:     "last occurrence within the stem",
+			base:     "a.b.c.tar",
+			patterns: []string{"."},
+			repl:     "_",
+			opts:     replace.Options{Occurrence: replace.OccurrenceLast, StemOnly: true},
+			want:     "a.b_c.tar",
+			counts:   map[string]int{".": 1},
+		},
+		{
+			name:     "stem only leaves extension",
+			base:     "jpg-photo.jpg",
+			patterns: []string{"jpg"},
+			repl:     "img",
+			opts:     replace.Options{StemOnly: true},
+			want:     "img-photo.jpg",
+			counts:   map[string]int{"jpg": 1},
+		},
+		{
+			name:     "stem only leaves compound extension",
+			base:     "site.backup.tar.gz",
+			patterns: []string{"."},
+			repl:     "-",
+			opts:     replace.Options{StemOnly: true},
+			want:     "site-backup.tar.gz",
+			counts:   map[string]int{".": 1},
+		},
+		{
+			name:     "sequential patterns count what they replaced",
+			base:     "draft DRAFT final.txt",
+			patterns: []string{"draft", "final"},
+			repl:     "v2",
+			opts:     replace.Options{IgnoreCase: true},
+			want:     "v2 v2 v2.txt",
+			counts:   map[string]int{"draft": 2, "final": 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := replace.ApplyPatterns(replace.Candidate{BaseName: tc.base}, tc.patterns, tc.repl, tc.opts, fileext.Model{})
+			if result.ProposedName != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, result.ProposedName)
+			}
+			if len(result.Matches) != len(tc.counts) {
+				t.Fatalf("expected counts %v, got %v", tc.counts, result.Matches)
+			}
+			for pattern, count := range tc.counts {
+				if result.Matches[pattern] != count {
+					t.Fatalf("expected %d matches for %q, got %v", count, pattern, result.Matches)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOccurrence(t *testing.T) {
+	if got, err := replace.ParseOccurrence(""); err != nil || got != replace.OccurrenceAll {
+		t.Fatalf("expected default all, got %q (%v)", got, err)
+	}
+	if _, err := replace.ParseOccurrence("middle"); err == nil {
+		t.Fatalf("expected error for unsupported occurrence")
+	}
+}
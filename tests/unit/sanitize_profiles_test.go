@@ -0,0 +1,54 @@
+package replace_test
+
+import (
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/sanitize"
+)
+
+func TestSanitizeProfiles(t *testing.T) {
+	cases := []struct {
+		profile  sanitize.Profile
+		input    string
+		expected string
+	}{
+		{sanitize.ProfilePOSIX, "a:b?.txt", "a:b?.txt"},
+		{sanitize.ProfilePOSIX, "bell\a.txt", "bell.txt"},
+		{sanitize.ProfileWindows, `a:b?c.txt`, "a_b_c.txt"},
+		{sanitize.ProfileWindows, "notes.  ", "notes"},
+		{sanitize.ProfileWindows, "CON.txt", "CON_.txt"},
+		{sanitize.ProfileWindows, "lpt1", "lpt1_"},
+		{sanitize.ProfileWindows, "a<>b.txt", "a_b.txt"},
+		{sanitize.ProfileWindows, "50%#1.txt", "50%#1.txt"},
+		{sanitize.ProfilePortable, "50%#1.txt", "50_1.txt"},
+		{sanitize.ProfilePortable, "  padded.txt", "padded.txt"},
+		{sanitize.ProfileS3, "résumé (final) v2.pdf", "r_sum_(final)_v2.pdf"},
+		{sanitize.ProfileWindows, "???", "_"},
+	}
+
+	for _, tc := range cases {
+		got, _ := sanitize.Sanitize(tc.input, tc.profile, "_")
+		if got != tc.expected {
+			t.Fatalf("Sanitize(%q, %s) = %q, expected %q", tc.input, tc.profile, got, tc.expected)
+		}
+	}
+}
+
+func TestSanitizeStripsWithEmptyReplacement(t *testing.T) {
+	got, reasons := sanitize.Sanitize("a*b*c.txt", sanitize.ProfileWindows, "")
+	if got != "abc.txt" {
+		t.Fatalf("expected abc.txt, got %q", got)
+	}
+	if len(reasons) != 1 || reasons[0] != sanitize.ReasonIllegal {
+		t.Fatalf("unexpected reasons: %v", reasons)
+	}
+}
+
+func TestParseSanitizeProfile(t *testing.T) {
+	if profile, err := sanitize.ParseProfile(""); err != nil || profile != sanitize.ProfilePortable {
+		t.Fatalf("expected default portable profile, got %q (%v)", profile, err)
+	}
+	if _, err := sanitize.ParseProfile("dos"); err == nil {
+		t.Fatalf("expected unknown profile to fail")
+	}
+}
@@ -0,0 +1,103 @@
+package replace_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rogeecn/renamer/internal/sidecar"
+)
+
+func TestSidecarBuildClustersCompanionsUnderPrimary(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{
+		"IMG_0001.CR2", "IMG_0001.xmp", "IMG_0001.JPG", "IMG_0001.CR2.dop",
+		"IMG_0002.JPG", "IMG_0002.xmp",
+		"movie.mkv", "movie.en.srt", "movie.nfo",
+		"lone.xmp", "clip.mov", "clip.CR2",
+	} {
+		if err := os.WriteFile(filepath.Join(tmp, name), nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	groups, err := sidecar.Build(sidecar.Scope{WorkingDir: tmp}, sidecar.DefaultRules())
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	cases := map[string]string{
+		"IMG_0001.CR2": "IMG_0001.CR2.dop|IMG_0001.JPG|IMG_0001.xmp",
+		"IMG_0002.JPG": "IMG_0002.xmp",
+		"movie.mkv":    "movie.en.srt|movie.nfo",
+		"lone.xmp":     "",
+		"clip.mov":     "",
+		"clip.CR2":     "",
+	}
+	for head, want := range cases {
+		followers := groups.Followers(head)
+		sort.Strings(followers)
+		if got := strings.Join(followers, "|"); got != want {
+			t.Fatalf("%s: expected followers %q, got %q", head, want, got)
+		}
+	}
+	if !groups.IsFollower("IMG_0001.JPG") || groups.IsFollower("IMG_0002.JPG") {
+		t.Fatalf("a JPEG follows a raw file but heads a cluster of its own")
+	}
+
+	var none *sidecar.Groups
+	if none.IsFollower("IMG_0001.xmp") || len(none.Expand(tmp, []sidecar.Rename{{From: "a", To: "b"}}).Renames) != 0 {
+		t.Fatalf("nil groups must disable grouping")
+	}
+}
+
+func TestSidecarExpandCarriesTailsAndReportsConflicts(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"IMG_0001.CR2", "IMG_0001.xmp", "IMG_0001.CR2.dop", "IMG_0002.CR2", "IMG_0002.xmp", "taken.xmp"} {
+		if err := os.WriteFile(filepath.Join(tmp, name), nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	groups, err := sidecar.Build(sidecar.Scope{WorkingDir: tmp}, sidecar.DefaultRules())
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	plan := groups.Expand(tmp, []sidecar.Rename{
+		{From: "IMG_0001.CR2", To: "trip_01.cr2"},
+		{From: "IMG_0002.CR2", To: "taken.CR2"},
+	})
+
+	got := make([]string, 0, len(plan.Renames))
+	for _, rename := range plan.Renames {
+		got = append(got, rename.From+">"+rename.To)
+	}
+	sort.Strings(got)
+	if want := "IMG_0001.CR2.dop>trip_01.cr2.dop|IMG_0001.xmp>trip_01.xmp"; strings.Join(got, "|") != want {
+		t.Fatalf("expected renames %q, got %q", want, strings.Join(got, "|"))
+	}
+
+	if len(plan.Conflicts) != 1 {
+		t.Fatalf("expected one conflict, got %#v", plan.Conflicts)
+	}
+	conflict := plan.Conflicts[0]
+	if conflict.Head != "IMG_0002.CR2" || conflict.From != "IMG_0002.xmp" || conflict.To != "taken.xmp" || conflict.Reason != "existing_file" {
+		t.Fatalf("unexpected conflict %#v", conflict)
+	}
+}
+
+func TestSidecarParseRulesValidatesExtensions(t *testing.T) {
+	rules, err := sidecar.ParseRules(".XMP|.pp3")
+	if err != nil {
+		t.Fatalf("parse rules: %v", err)
+	}
+	if strings.Join(rules.Extensions, "|") != ".xmp|.pp3" {
+		t.Fatalf("unexpected extensions %v", rules.Extensions)
+	}
+	if _, err := sidecar.ParseRules("xmp"); err == nil {
+		t.Fatalf("expected an extension without a dot to be rejected")
+	}
+}
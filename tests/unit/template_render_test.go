@@ -0,0 +1,80 @@
+package replace_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/template"
+)
+
+func TestTemplateRenderTokensAndFilters(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "albums", "Summer Trip Photo.JPG")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("12345"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	mtime := time.Date(2024, 7, 9, 15, 4, 5, 0, time.Local)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	cases := map[string]string{
+		"{mtime:2006-01-02}_{parent}_{stem}_{n:03}{ext}": "2024-07-09_albums_Summer Trip Photo_003.JPG",
+		"{stem|lower|slice:0:6}{ext|lower}":              "summer.jpg",
+		"{stem|snake}-{size}b":                           "summer_trip_photo-5b",
+		"{mtime:15:04}|{reldir}":                         "15:04|albums",
+		"{n:2:10:5}|{stem|slice:-5}":                     "20|Photo",
+		"{{literal}} {ext|replace:.:_|pad:6:x}":          "{literal} xx_JPG",
+	}
+
+	for source, expected := range cases {
+		tmpl, err := template.Parse(source)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", source, err)
+		}
+		got, _, err := tmpl.Render(template.NewContext(tmp, "albums/Summer Trip Photo.JPG", info, 2, fileext.Model{}))
+		if err != nil {
+			t.Fatalf("Render(%q) error: %v", source, err)
+		}
+		if got != expected {
+			t.Fatalf("Render(%q) = %q, expected %q", source, got, expected)
+		}
+	}
+}
+
+func TestTemplateParseRejectsInvalidPlaceholders(t *testing.T) {
+	for _, source := range []string{"{unknown}", "{stem|shout}", "{stem", "stem}", "{n:0}", "{ext:x}", "{stem|slice:a}"} {
+		if _, err := template.Parse(source); err == nil {
+			t.Fatalf("expected Parse(%q) to fail", source)
+		}
+	}
+}
+
+func TestTemplateTagsDropControlCharacters(t *testing.T) {
+	path := writeFLAC(t, vorbisComment(-1, "ARTIST=AC/DC\x00\tLive\x1b"))
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	tmpl, err := template.Parse("{artist}{ext}")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, _, err := tmpl.Render(template.NewContext(filepath.Dir(path), filepath.Base(path), info, 0, fileext.Model{}))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if got != "AC_DCLive.flac" {
+		t.Fatalf("expected AC_DCLive.flac, got %q", got)
+	}
+}
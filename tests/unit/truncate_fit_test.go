@@ -0,0 +1,58 @@
+package replace_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rogeecn/renamer/internal/fileext"
+	"github.com/rogeecn/renamer/internal/truncate"
+)
+
+func TestFitKeepsExtensionAndRuneBoundary(t *testing.T) {
+	name := strings.Repeat("é", 10) + ".txt" // 20 stem bytes
+	got, ok := truncate.Fit(name, false, 11, fileext.Model{})
+	if !ok {
+		t.Fatalf("expected name to fit")
+	}
+	if got != "ééé.txt" {
+		t.Fatalf("expected ééé.txt, got %q", got)
+	}
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated name is not valid UTF-8: %q", got)
+	}
+}
+
+func TestFitTreatsDirectoriesAsStem(t *testing.T) {
+	got, ok := truncate.Fit("archive.2024.backup", true, 7, fileext.Model{})
+	if !ok || got != "archive" {
+		t.Fatalf("expected archive, got %q (%v)", got, ok)
+	}
+}
+
+func TestFitRejectsOversizedExtension(t *testing.T) {
+	if _, ok := truncate.Fit("a."+strings.Repeat("x", 20), false, 10, fileext.Model{}); ok {
+		t.Fatalf("expected oversized extension to fail")
+	}
+}
+
+func TestFitWithHashIsDeterministic(t *testing.T) {
+	first, ok := truncate.FitWithHash("quarterly-report-final.pdf", false, 20, fileext.Model{})
+	if !ok {
+		t.Fatalf("expected hashed name to fit")
+	}
+	second, _ := truncate.FitWithHash("quarterly-report-final.pdf", false, 20, fileext.Model{})
+	if first != second {
+		t.Fatalf("expected deterministic hash, got %q and %q", first, second)
+	}
+	if len(first) > 20 || !strings.HasPrefix(first, "quarter~") || !strings.HasSuffix(first, ".pdf") {
+		t.Fatalf("unexpected hashed name %q", first)
+	}
+}
+
+func TestFitKeepsCompoundExtension(t *testing.T) {
+	got, ok := truncate.Fit("nightly-database-backup.tar.gz", false, 16, fileext.Model{})
+	if !ok || got != "nightly-d.tar.gz" {
+		t.Fatalf("expected nightly-d.tar.gz, got %q (%v)", got, ok)
+	}
+}